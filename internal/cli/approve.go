@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func newApproveCmd() *cobra.Command {
+	var (
+		reject   bool
+		feedback string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "approve <phase>",
+		Short: "Approve a phase waiting at a forge auto gate",
+		Long: `Decide on a phase that forge auto paused at an approval gate.
+
+Gates are configured per phase in .forge/config.yaml:
+
+  gates:
+    implementation: confirm      # pause after the phase
+    deployment:
+      mode: manual               # always wait for 'forge approve'
+      when: before               # pause before running the phase
+
+After deciding, run 'forge auto' to continue. Approving with --feedback
+re-runs the phase (or starts it, for 'before' gates) with your feedback.`,
+		Example: `  # Approve the implementation phase
+  forge approve implementation
+
+  # Ask for changes; the phase is re-run with this feedback
+  forge approve implementation --feedback "Add input validation"
+
+  # Reject the phase and stop the run
+  forge approve deployment --reject`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runApprove(args[0], reject, feedback)
+		},
+	}
+
+	cmd.Flags().BoolVar(&reject, "reject", false, "reject the phase instead of approving it")
+	cmd.Flags().StringVar(&feedback, "feedback", "", "request changes with this feedback")
+
+	return cmd
+}
+
+func runApprove(phase string, reject bool, feedback string) error {
+	if !core.IsValidPhase(phase) {
+		return fmt.Errorf("invalid phase: %s. Valid phases: %s",
+			phase, strings.Join(core.PhaseNames(), ", "))
+	}
+	if reject && feedback != "" {
+		return fmt.Errorf("--reject and --feedback cannot be combined")
+	}
+
+	decision := core.ApprovalDecision{Status: core.ApprovalApproved}
+	switch {
+	case reject:
+		decision.Status = core.ApprovalRejected
+	case feedback != "":
+		decision.Status = core.ApprovalChangesRequested
+		decision.Feedback = feedback
+	}
+
+	err := updateState(func(state *core.ProjectState) error {
+		return state.DecideApproval(phase, decision)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Phase '%s': %s\n", phase, decision.Status)
+	fmt.Println("\nRun 'forge auto' to continue.")
+	return nil
+}
+
+// terminalApprover asks for gate decisions on the terminal
+type terminalApprover struct {
+	reader *bufio.Reader
+}
+
+func newTerminalApprover() *terminalApprover {
+	return &terminalApprover{reader: bufio.NewReader(os.Stdin)}
+}
+
+func (a *terminalApprover) RequestApproval(req core.ApprovalRequest) (core.ApprovalDecision, error) {
+	fmt.Println()
+	fmt.Print(req.Summary)
+
+	for {
+		fmt.Printf("\n%s (%s): [a]pprove, [r]eject, [e]dit feedback: ", req.Phase, req.When)
+		choice, err := a.reader.ReadString('\n')
+		if err != nil {
+			return core.ApprovalDecision{}, fmt.Errorf("read approval: %w", err)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(choice)) {
+		case "a", "approve", "y", "yes":
+			return core.ApprovalDecision{Status: core.ApprovalApproved}, nil
+		case "r", "reject", "n", "no":
+			return core.ApprovalDecision{Status: core.ApprovalRejected}, nil
+		case "e", "edit", "feedback":
+			fmt.Print("Feedback: ")
+			feedback, err := a.reader.ReadString('\n')
+			if err != nil {
+				return core.ApprovalDecision{}, fmt.Errorf("read feedback: %w", err)
+			}
+			feedback = strings.TrimSpace(feedback)
+			if feedback == "" {
+				fmt.Println("Feedback cannot be empty.")
+				continue
+			}
+			return core.ApprovalDecision{Status: core.ApprovalChangesRequested, Feedback: feedback}, nil
+		default:
+			fmt.Println("Please enter a, r or e.")
+		}
+	}
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal
+func stdinIsTerminal() bool {
+	stat, err := os.Stdin.Stat()
+	return err == nil && (stat.Mode()&os.ModeCharDevice) != 0
+}
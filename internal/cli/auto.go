@@ -1,7 +1,11 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/rice0649/fabric-lite/internal/core"
 	"github.com/rice0649/fabric-lite/internal/tools"
@@ -14,6 +18,8 @@ func newAutoCmd() *cobra.Command {
 		untilPhase     string
 		skipValidation bool
 		dryRun         bool
+		maxRetries     int
+		forceCommit    bool
 	)
 
 	cmd := &cobra.Command{
@@ -22,11 +28,32 @@ func newAutoCmd() *cobra.Command {
 		Long: `Run development phases sequentially with optional AI-powered validation.
 
 By default, runs all phases from the current state to deployment.
-Use --from and --until to specify a range of phases.
+Use --from and --until to specify a range of phases. Ranges follow the
+phase dependencies: --from runs that phase and the phases depending on it,
+--until stops at that phase and the phases it depends on.
 
-Between each phase, an AI validator checks that the phase output
-meets quality criteria before proceeding. Use --skip-validation
-to disable this.
+Each phase runs its tool non-interactively with the artifacts of earlier
+phases as context. Captured output is saved to .forge/artifacts/<phase>/,
+and each tool run is bounded by advanced.tool_timeout (seconds).
+
+Between each phase, the checkpoint file checks run and an AI validator
+reviews the phase artifacts against each checkpoint criterion before
+proceeding. Use --skip-validation to disable this. When validation fails, the phase is re-run with the
+validator's feedback up to --max-retries times (advanced.max_retries).
+Every attempt is recorded in state and in .forge/history/.
+
+Phases with an approval gate (gates.<phase>: confirm or manual) pause
+before or after running and show the artifacts and checkpoint results.
+On a terminal, confirm gates ask to approve, reject or give feedback;
+otherwise the run stops until 'forge approve <phase>' is used.
+
+Phases declare dependencies (override with depends_on in config.yaml);
+phases whose dependencies are met run concurrently, e.g. testing and
+deployment docs both only need the implementation.
+
+With integrations.git.auto_commit, each completed phase commits .forge
+(and is tagged forge/<phase> with integrations.git.tag). The run refuses to
+start while other files have uncommitted changes unless --force-commit is given.
 
 If interrupted, forge auto will resume from where it left off.`,
 		Example: `  # Run all phases from current state
@@ -38,10 +65,13 @@ If interrupted, forge auto will resume from where it left off.`,
   # Run without validation checkpoints
   forge auto --skip-validation
 
+  # Retry failing phases up to twice with validation feedback
+  forge auto --max-retries 2
+
   # Preview what would run
   forge auto --dry-run`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runAuto(fromPhase, untilPhase, skipValidation, dryRun)
+			return runAuto(fromPhase, untilPhase, skipValidation, dryRun, maxRetries, forceCommit)
 		},
 	}
 
@@ -49,11 +79,13 @@ If interrupted, forge auto will resume from where it left off.`,
 	cmd.Flags().StringVar(&untilPhase, "until", "", "stop after this phase")
 	cmd.Flags().BoolVar(&skipValidation, "skip-validation", false, "skip AI validation between phases")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be executed without running")
+	cmd.Flags().IntVar(&maxRetries, "max-retries", -1, "retry a phase up to N times with validation feedback (-1 uses advanced.max_retries)")
+	cmd.Flags().BoolVar(&forceCommit, "force-commit", false, "with integrations.git.auto_commit, commit even when other files have uncommitted changes")
 
 	return cmd
 }
 
-func runAuto(fromPhase, untilPhase string, skipValidation, dryRun bool) error {
+func runAuto(fromPhase, untilPhase string, skipValidation, dryRun bool, maxRetries int, forceCommit bool) error {
 	// Load project config
 	config, err := core.LoadProjectConfig(".forge/config.yaml")
 	if err != nil {
@@ -69,6 +101,9 @@ func runAuto(fromPhase, untilPhase string, skipValidation, dryRun bool) error {
 
 	// Create auto runner
 	runner := core.NewAutoRunner(config, state, statePath)
+	if maxRetries >= 0 {
+		runner.MaxRetries = maxRetries
+	}
 
 	// Check for resumable state
 	canResume, lastPhase, nextPhase := runner.GetResumeInfo()
@@ -81,6 +116,11 @@ func runAuto(fromPhase, untilPhase string, skipValidation, dryRun bool) error {
 		runner.Validator = createValidator()
 	}
 
+	// Gates ask interactively on a terminal; otherwise the run stops for 'forge approve'
+	if stdinIsTerminal() && !dryRun {
+		runner.Approver = newTerminalApprover()
+	}
+
 	// Set up executor
 	runner.Executor = &defaultPhaseExecutor{
 		config: config,
@@ -93,13 +133,20 @@ func runAuto(fromPhase, untilPhase string, skipValidation, dryRun bool) error {
 		return showDryRun(runner, fromPhase, untilPhase, skipValidation)
 	}
 
+	// Commit .forge after each completed phase when enabled
+	runner.Git = core.NewGitIntegration(config, ".", forceCommit)
+
 	// Run phases
-	return runner.Run(fromPhase, untilPhase, skipValidation)
+	err = runner.Run(fromPhase, untilPhase, skipValidation)
+	if errors.Is(err, core.ErrDirtyWorktree) {
+		return fmt.Errorf("%w\nCommit or stash them, or use --force-commit", err)
+	}
+	return err
 }
 
 // showDryRun displays what would be executed
 func showDryRun(runner *core.AutoRunner, from, until string, skipValidation bool) error {
-	phases, err := getPhaseRangeForDisplay(from, until, runner)
+	waves, err := runner.Plan(from, until)
 	if err != nil {
 		return err
 	}
@@ -107,67 +154,47 @@ func showDryRun(runner *core.AutoRunner, from, until string, skipValidation bool
 	fmt.Println("Dry run - would execute:")
 	fmt.Println()
 
-	for i, phase := range phases {
-		phaseInfo := core.GetPhase(phase)
-		fmt.Printf("%d. Phase: %s\n", i+1, phase)
-		fmt.Printf("   Tool: %s\n", phaseInfo.PrimaryTool)
-		fmt.Printf("   Artifacts: %v\n", phaseInfo.Artifacts)
-		if !skipValidation {
-			fmt.Printf("   Validation: enabled\n")
-		}
-		fmt.Println()
-	}
-
-	if skipValidation {
-		fmt.Println("Note: Validation checkpoints disabled")
+	graph, err := runner.Config.PhaseGraph()
+	if err != nil {
+		return err
 	}
 
-	return nil
-}
-
-// getPhaseRangeForDisplay returns phases for dry-run display
-func getPhaseRangeForDisplay(from, until string, runner *core.AutoRunner) ([]string, error) {
-	allPhases := core.PhaseNames()
-
-	startIdx := 0
-	if from != "" {
-		for i, p := range allPhases {
-			if p == from {
-				startIdx = i
-				break
-			}
+	step := 0
+	for i, wave := range waves {
+		if len(wave) > 1 {
+			fmt.Printf("Wave %d (concurrent): %s\n\n", i+1, strings.Join(wave, ", "))
 		}
-	} else if runner.State.Auto != nil && runner.State.Auto.LastCompletedPhase != "" {
-		for i, p := range allPhases {
-			if p == runner.State.Auto.LastCompletedPhase {
-				startIdx = i + 1
-				break
+		for _, phase := range wave {
+			step++
+			phaseInfo := core.GetPhase(phase)
+			fmt.Printf("%d. Phase: %s\n", step, phase)
+			fmt.Printf("   Tool: %s\n", phaseInfo.PrimaryTool)
+			fmt.Printf("   Artifacts: %v\n", phaseInfo.Artifacts)
+			if deps := graph.DependsOn(phase); len(deps) > 0 {
+				fmt.Printf("   Depends on: %s\n", strings.Join(deps, ", "))
 			}
-		}
-	}
-
-	endIdx := len(allPhases) - 1
-	if until != "" {
-		for i, p := range allPhases {
-			if p == until {
-				endIdx = i
-				break
+			if !skipValidation {
+				fmt.Printf("   Validation: enabled\n")
+			}
+			if gate := runner.Config.GateFor(phase); gate.Mode != core.GateAuto {
+				fmt.Printf("   Approval: %s (%s)\n", gate.Mode, gate.When)
 			}
+			fmt.Println()
 		}
 	}
 
-	if startIdx > endIdx || startIdx >= len(allPhases) {
-		return nil, fmt.Errorf("invalid phase range")
+	if skipValidation {
+		fmt.Println("Note: Validation checkpoints disabled")
 	}
 
-	return allPhases[startIdx : endIdx+1], nil
+	return nil
 }
 
 // createValidator creates a phase validator using fabric-lite
 func createValidator() *core.PhaseValidator {
 	fabricTool := tools.NewFabricTool()
 	if !fabricTool.IsAvailable() {
-		fmt.Println("Warning: fabric-lite not available, validation disabled")
+		fmt.Fprintln(os.Stderr, "Warning: fabric-lite not available, AI validation disabled (checkpoint checks still run)")
 		return nil
 	}
 
@@ -189,6 +216,23 @@ func createValidator() *core.PhaseValidator {
 	}
 }
 
+// gitContext collects git content for tool context. Failures only warn so
+// a missing repository does not stop the phase.
+func gitContext(src core.GitSource) string {
+	if src.IsZero() {
+		return ""
+	}
+	changes, err := src.Collect(".")
+	if err != nil {
+		fmt.Printf("  → Warning: skipping git context: %v\n", err)
+		return ""
+	}
+	if changes == "" {
+		return ""
+	}
+	return "\n\n# Local Git Changes\n\n" + changes
+}
+
 // defaultPhaseExecutor executes phases using the configured tools
 type defaultPhaseExecutor struct {
 	config *core.ProjectConfig
@@ -196,44 +240,91 @@ type defaultPhaseExecutor struct {
 	dryRun bool
 }
 
-func (e *defaultPhaseExecutor) Execute(phase string) error {
+func (e *defaultPhaseExecutor) Execute(phase string, feedback core.PhaseFeedback) (*core.PhaseExecution, error) {
 	if e.dryRun {
-		return nil
+		return &core.PhaseExecution{}, nil
 	}
 
 	phaseInfo := core.GetPhase(phase)
 	if phaseInfo == nil {
-		return fmt.Errorf("unknown phase: %s", phase)
+		return nil, fmt.Errorf("unknown phase: %s", phase)
 	}
 
 	toolName := phaseInfo.PrimaryTool
+	if override, ok := e.config.Phases[phase]; ok && override != "" {
+		toolName = override
+	}
 
 	// Get the tool
 	tool, err := tools.GetTool(toolName)
 	if err != nil {
-		return fmt.Errorf("get tool %s: %w", toolName, err)
+		return nil, fmt.Errorf("get tool %s: %w", toolName, err)
 	}
 
 	if !tool.IsAvailable() {
-		return fmt.Errorf("tool %s is not installed or not in PATH", toolName)
+		return nil, fmt.Errorf("tool %s is not installed or not in PATH", toolName)
+	}
+
+	// Prior phase artifacts are passed along so each phase builds on the last
+	graph, err := e.config.PhaseGraph()
+	if err != nil {
+		return nil, err
+	}
+	priorContext, err := core.PriorArtifactsContext(graph, phase, core.DefaultArtifactContextLimit)
+	if err != nil {
+		return nil, fmt.Errorf("collect prior artifacts: %w", err)
+	}
+
+	// The implementation phase also sees local changes when integrations.git.context is set
+	if phase == "implementation" {
+		priorContext += gitContext(e.config.Integrations.Git.Context.Source())
+	}
+
+	// On retries, lead with the feedback on the previous attempt
+	if !feedback.IsZero() {
+		reason := "A previous attempt at this phase was rejected by validation."
+		if feedback.Source == core.FeedbackReviewer {
+			reason = "A reviewer requested changes to a previous attempt at this phase."
+		}
+		priorContext = fmt.Sprintf("%s\nAddress the following feedback:\n\n%s\n\n%s", reason, feedback.Text, priorContext)
 	}
 
 	// Build execution context
 	ctx := tools.ExecutionContext{
-		Phase: phase,
+		Phase:   phase,
+		Context: priorContext,
+		WorkDir: ".",
+		Timeout: e.config.Advanced.ToolTimeoutDuration(),
 	}
 
-	fmt.Printf("  → Executing with %s...\n", toolName)
+	fmt.Printf("  → Executing with %s (timeout %s)...\n", toolName, ctx.Timeout)
 
-	// Execute the tool
-	result, err := tool.Execute(ctx)
+	// Execute the tool without a terminal so the run stays automatic
+	result, err := tool.ExecuteNonInteractive(ctx)
 	if err != nil {
-		return fmt.Errorf("tool execution failed: %w", err)
+		return nil, fmt.Errorf("tool execution failed: %w", err)
 	}
 
 	if !result.Success {
-		return fmt.Errorf("tool returned error: %s", result.Error)
+		return nil, fmt.Errorf("tool returned error: %s", result.Error)
 	}
 
-	return nil
+	// CLI tools do not report usage, so tokens are estimated from the text exchanged
+	execution := &core.PhaseExecution{
+		Output: result.Output,
+		Tokens: core.EstimateTokens(priorContext) + core.EstimateTokens(result.Output),
+	}
+
+	// Persist captured output as a phase artifact
+	if strings.TrimSpace(result.Output) != "" {
+		name := core.NewArtifactName(phase, "auto_"+toolName, time.Now())
+		path, err := core.SaveArtifact(phase, name, result.Output)
+		if err != nil {
+			return nil, fmt.Errorf("save %s output: %w", toolName, err)
+		}
+		fmt.Printf("  → Saved output to %s\n", path)
+		execution.Artifact = path
+	}
+
+	return execution, nil
 }
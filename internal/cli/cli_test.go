@@ -307,7 +307,7 @@ func TestNewPhaseCmd(t *testing.T) {
 	}
 
 	// Check for expected subcommands
-	expectedSubs := []string{"list", "start", "complete", "info"}
+	expectedSubs := []string{"list", "start", "complete", "info", "reopen", "reset"}
 	for _, expected := range expectedSubs {
 		found := false
 		for _, sub := range subcommands {
@@ -332,6 +332,42 @@ func TestNewStatusCmd(t *testing.T) {
 	}
 }
 
+func TestNewHistoryCmd(t *testing.T) {
+	cmd := newHistoryCmd()
+	if cmd == nil {
+		t.Fatal("Expected history command to be non-nil")
+	}
+	if cmd.Use != "history" {
+		t.Errorf("Expected command use to be 'history', got '%s'", cmd.Use)
+	}
+
+	for _, name := range []string{"limit", "phase", "since", "until", "type", "stats"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected '%s' flag to be present", name)
+		}
+	}
+
+	registered := false
+	for _, sub := range NewRootCmd("test").Commands() {
+		if sub.Use == "history" {
+			registered = true
+		}
+	}
+	if !registered {
+		t.Error("Expected history command to be registered on the root command")
+	}
+}
+
+func TestNewReportCmd(t *testing.T) {
+	cmd := newReportCmd()
+	if cmd == nil {
+		t.Fatal("Expected report command to be non-nil")
+	}
+	if !strings.HasPrefix(cmd.Use, "report") {
+		t.Errorf("Expected command use to start with 'report', got '%s'", cmd.Use)
+	}
+}
+
 func TestNewSessionCmd(t *testing.T) {
 	cmd := newSessionCmd()
 	if cmd == nil {
@@ -379,6 +415,42 @@ func TestNewAutoCmd(t *testing.T) {
 	}
 }
 
+func TestNewApproveCmd(t *testing.T) {
+	cmd := newApproveCmd()
+	if cmd == nil {
+		t.Fatal("Expected approve command to be non-nil")
+	}
+	if !strings.HasPrefix(cmd.Use, "approve") {
+		t.Errorf("Expected command use to start with 'approve', got '%s'", cmd.Use)
+	}
+
+	for _, name := range []string{"reject", "feedback"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected '%s' flag to be present", name)
+		}
+	}
+}
+
+func TestNewStateCmd(t *testing.T) {
+	cmd := newStateCmd()
+	if cmd == nil {
+		t.Fatal("Expected state command to be non-nil")
+	}
+	if cmd.Use != "state" {
+		t.Errorf("Expected command use 'state', got '%s'", cmd.Use)
+	}
+
+	subcommands := make(map[string]bool)
+	for _, sub := range cmd.Commands() {
+		subcommands[sub.Use] = true
+	}
+	for _, name := range []string{"check", "restore"} {
+		if !subcommands[name] {
+			t.Errorf("Expected state command to have '%s' subcommand", name)
+		}
+	}
+}
+
 func TestNewRunCmd(t *testing.T) {
 	cmd := newRunCmd()
 	if cmd == nil {
@@ -388,6 +460,22 @@ func TestNewRunCmd(t *testing.T) {
 	if !strings.HasPrefix(cmd.Use, "run") {
 		t.Errorf("Expected command use to start with 'run', got '%s'", cmd.Use)
 	}
+
+	for _, name := range []string{"git-diff", "staged", "git-log", "git-path", "git-max-bytes"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected '%s' flag to be present", name)
+		}
+	}
+	if def := cmd.Flags().Lookup("git-diff").NoOptDefVal; def != "HEAD" {
+		t.Errorf("Expected --git-diff without a value to mean HEAD, got '%s'", def)
+	}
+}
+
+func TestGitInputWithoutSource(t *testing.T) {
+	input, err := gitInput(newRunCmd())
+	if err != nil || input != "" {
+		t.Errorf("Expected no git input without flags, got %q, %v", input, err)
+	}
 }
 
 func TestNewListCmd(t *testing.T) {
@@ -420,3 +508,35 @@ func TestNewVersionCmd(t *testing.T) {
 		t.Errorf("Expected command use to be 'version', got '%s'", cmd.Use)
 	}
 }
+
+func TestNewServeCmd(t *testing.T) {
+	cmd := newServeCmd()
+	if cmd == nil {
+		t.Fatal("Expected serve command to be non-nil")
+	}
+	if cmd.Use != "serve" {
+		t.Errorf("Expected command use to be 'serve', got '%s'", cmd.Use)
+	}
+
+	for _, name := range []string{"addr", "token", "max-body-bytes", "max-concurrent", "shutdown-timeout", "fallback", "patterns-dir"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected '%s' flag to be present", name)
+		}
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:8080": true,
+		"localhost:8080": true,
+		"[::1]:8080":     true,
+		"0.0.0.0:8080":   false,
+		":8080":          false,
+		"10.0.0.5:8080":  false,
+	}
+	for addr, want := range tests {
+		if got := isLoopback(addr); got != want {
+			t.Errorf("Expected isLoopback(%q) to be %v, got %v", addr, want, got)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+	"github.com/rice0649/fabric-lite/internal/executor"
+	"github.com/rice0649/fabric-lite/internal/providers"
+	"github.com/rice0649/fabric-lite/internal/tools"
+	"github.com/spf13/cobra"
+)
+
+// Doctor check status for a passing check; failures use core.IssueError
+// and core.IssueWarning
+const doctorOK = "ok"
+
+func newDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check configuration, providers, tools, patterns and project state",
+		Long: `Check the fabric-lite setup and report problems before a run hits them.
+
+doctor validates ~/.config/fabric-lite/config.yaml and .forge/config.yaml
+(unknown keys, values of the wrong type, unknown phases, tools and
+providers, each with its line number), checks that every configured
+provider is available and every tool's binary is on PATH, loads the
+patterns in each pattern directory and checks .forge/state.yaml.
+
+Errors make doctor exit non-zero; warnings don't.`,
+		// A broken config is reported by doctor rather than stopping it
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+		RunE:              runDoctor,
+	}
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	report := &doctorReport{}
+	mainPath := core.NewConfigManager("").Path()
+
+	var cfg *core.ProjectConfig
+	var pm *core.ProviderManager
+	loadErr := initConfig()
+	names := core.ConfigNames{Tools: tools.ListTools()}
+	if loadErr == nil {
+		cfg = core.GetDefaultConfig()
+		pm = core.GetDefaultProviderManager()
+		names.Providers = providerNames(pm.Configs())
+	}
+
+	report.checkConfigFile(mainPath, names, "not found, using defaults")
+	if loadErr != nil && report.Errors == 0 {
+		report.add("config", mainPath, core.IssueError, "%v", loadErr)
+	}
+	report.checkConfigFile(".forge/config.yaml", names, "not a forge project")
+
+	if cfg != nil {
+		report.checkProviders(pm, pm.Configs())
+		report.checkTools()
+		report.checkPatterns(patternDirs(cfg))
+	}
+	report.checkState(".forge/state.yaml")
+
+	if format != outputText {
+		if err := printOutput(format, report.doctorView); err != nil {
+			return err
+		}
+	} else {
+		printDoctor(os.Stdout, report.doctorView)
+	}
+
+	if report.Errors > 0 {
+		return fmt.Errorf("doctor found %d error(s)", report.Errors)
+	}
+	return nil
+}
+
+// doctorReport collects check results
+type doctorReport struct {
+	doctorView
+}
+
+func (r *doctorReport) add(section, name, status, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, doctorCheckView{
+		Section: section,
+		Name:    name,
+		Status:  status,
+		Message: fmt.Sprintf(format, args...),
+	})
+	switch status {
+	case core.IssueError:
+		r.Errors++
+	case core.IssueWarning:
+		r.Warnings++
+	}
+}
+
+// checkConfigFile validates a config file; missing is reported when the
+// file doesn't exist
+func (r *doctorReport) checkConfigFile(path string, names core.ConfigNames, missing string) {
+	issues, err := core.ValidateConfigFile(path, names)
+	if os.IsNotExist(err) {
+		r.add("config", path, doctorOK, missing)
+		return
+	}
+	if err != nil {
+		r.add("config", path, core.IssueError, "%v", err)
+		return
+	}
+	for _, issue := range issues {
+		r.add("config", path, issue.Severity, "%s", issue)
+	}
+	if len(issues) == 0 {
+		r.add("config", path, doctorOK, "valid")
+	}
+}
+
+// checkProviders reports providers that failed to initialize or are not
+// available
+func (r *doctorReport) checkProviders(pm *core.ProviderManager, configured []providers.ProviderConfig) {
+	if len(configured) == 0 {
+		r.add("providers", "", core.IssueWarning, "no providers enabled (set tools.ollama.enabled or tools.claude.enabled, or add providers.yaml)")
+		return
+	}
+
+	initErrors := pm.InitErrors()
+	for _, pc := range configured {
+		if err, ok := initErrors[pc.Name]; ok {
+			r.add("providers", pc.Name, core.IssueError, "%v", err)
+			continue
+		}
+		p, err := pm.Get(pc.Name)
+		if err != nil {
+			r.add("providers", pc.Name, core.IssueError, "%v", err)
+			continue
+		}
+		if !p.IsAvailable() {
+			r.add("providers", pc.Name, core.IssueWarning, "not available (check the API key or that the server is running)")
+			continue
+		}
+		r.add("providers", pc.Name, doctorOK, "available")
+	}
+}
+
+// checkTools looks up every registered tool's binary on PATH
+func (r *doctorReport) checkTools() {
+	names := tools.ListTools()
+	sort.Strings(names)
+	for _, name := range names {
+		tool, err := tools.GetTool(name)
+		if err != nil {
+			continue
+		}
+		command := tool.GetCommand()
+		if command == "" {
+			// Tools such as codex run through a provider
+			if tool.IsAvailable() {
+				r.add("tools", name, doctorOK, "available")
+			} else {
+				r.add("tools", name, core.IssueWarning, "not available")
+			}
+			continue
+		}
+		path, err := exec.LookPath(command)
+		if err != nil {
+			r.add("tools", name, core.IssueWarning, "%s not found on PATH", command)
+			continue
+		}
+		if !tool.IsAvailable() {
+			r.add("tools", name, core.IssueWarning, "%s found at %s but its availability check failed", command, path)
+			continue
+		}
+		r.add("tools", name, doctorOK, "%s", path)
+	}
+}
+
+// checkPatterns loads every pattern in dirs
+func (r *doctorReport) checkPatterns(dirs []string) {
+	found := false
+	for _, dir := range dirs {
+		info, err := os.Stat(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil || !info.IsDir() {
+			r.add("patterns", dir, core.IssueError, "not a readable directory")
+			continue
+		}
+		found = true
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			r.add("patterns", dir, core.IssueError, "%v", err)
+			continue
+		}
+		patterns := executor.NewPatternExecutor()
+		patterns.SetPatternsDir(dir)
+		count := 0
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			if _, err := patterns.LoadPattern(entry.Name()); err != nil {
+				r.add("patterns", dir, core.IssueError, "%s: %v", entry.Name(), err)
+				continue
+			}
+			count++
+		}
+		r.add("patterns", dir, doctorOK, "%d pattern(s)", count)
+	}
+	if !found {
+		r.add("patterns", "", core.IssueWarning, "no pattern directory found (tried %v)", dirs)
+	}
+}
+
+// checkState reports state file issues, like forge state check
+func (r *doctorReport) checkState(statePath string) {
+	cfg, err := core.LoadProjectConfig(".forge/config.yaml")
+	if err != nil {
+		cfg = nil
+	}
+	result, err := core.CheckProjectState(statePath, cfg)
+	if os.IsNotExist(err) {
+		r.add("state", statePath, doctorOK, "not a forge project")
+		return
+	}
+	if err != nil {
+		r.add("state", statePath, core.IssueError, "cannot read state: %v (try 'forge state restore')", err)
+		return
+	}
+	for _, issue := range result.Issues {
+		r.add("state", statePath, issue.Severity, "%s", issue.Message)
+	}
+	if len(result.Issues) == 0 {
+		r.add("state", statePath, doctorOK, "schema version %d, no issues", result.SchemaVersion)
+	}
+}
+
+// patternDirs returns the directory run uses followed by the configured
+// pattern directories, without duplicates
+func patternDirs(cfg *core.ProjectConfig) []string {
+	dirs := []string{executor.NewPatternExecutor().GetPatternsDir()}
+	for _, dir := range cfg.Patterns.Directories {
+		dup := false
+		for _, d := range dirs {
+			if d == dir {
+				dup = true
+			}
+		}
+		if !dup {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+func providerNames(configs []providers.ProviderConfig) []string {
+	names := make([]string, len(configs))
+	for i, pc := range configs {
+		names[i] = pc.Name
+	}
+	return names
+}
+
+// printDoctor prints check results grouped by section
+func printDoctor(w io.Writer, view doctorView) {
+	section := ""
+	for _, check := range view.Checks {
+		if check.Section != section {
+			if section != "" {
+				fmt.Fprintln(w)
+			}
+			section = check.Section
+			fmt.Fprintf(w, "%s:\n", section)
+		}
+		icon := "✓"
+		switch check.Status {
+		case core.IssueError:
+			icon = "✗"
+		case core.IssueWarning:
+			icon = "!"
+		}
+		if check.Name == "" {
+			fmt.Fprintf(w, "  %s %s\n", icon, check.Message)
+		} else {
+			fmt.Fprintf(w, "  %s %s: %s\n", icon, check.Name, check.Message)
+		}
+	}
+	fmt.Fprintf(w, "\n%d error(s), %d warning(s)\n", view.Errors, view.Warnings)
+}
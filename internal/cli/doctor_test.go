@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+	"github.com/rice0649/fabric-lite/internal/providers"
+)
+
+func TestDoctorConfigAndState(t *testing.T) {
+	cfg, state := seedForgeProject(t)
+	if err := cfg.Save(".forge/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	state.CurrentPhase = "shipping"
+	if err := state.Save(".forge/state.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("config.yaml", []byte("name: demo\nphases:\n  testing: nosuchtool\nsessions:\n  max_histroy: 10\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report := &doctorReport{}
+	names := core.ConfigNames{Tools: []string{"codex", "gemini"}}
+	report.checkConfigFile("config.yaml", names, "")
+	report.checkConfigFile(".forge/config.yaml", names, "")
+	report.checkConfigFile("missing.yaml", names, "not found, using defaults")
+	report.checkState(".forge/state.yaml")
+
+	want := []doctorCheckView{
+		{"config", "config.yaml", core.IssueError, `line 3: phases.testing: unknown tool "nosuchtool" (expected one of codex, gemini)`},
+		{"config", "config.yaml", core.IssueWarning, `line 5: sessions.max_histroy: unknown key "max_histroy"`},
+		{"config", ".forge/config.yaml", doctorOK, "valid"},
+		{"config", "missing.yaml", doctorOK, "not found, using defaults"},
+	}
+	if len(report.Checks) < len(want)+1 {
+		t.Fatalf("Expected config and state checks, got %+v", report.Checks)
+	}
+	for i, w := range want {
+		if report.Checks[i] != w {
+			t.Errorf("Check %d: expected %+v, got %+v", i, w, report.Checks[i])
+		}
+	}
+	stateCheck := report.Checks[len(want)]
+	if stateCheck.Section != "state" || stateCheck.Status != core.IssueError || !strings.Contains(stateCheck.Message, "shipping") {
+		t.Errorf("Expected the unknown current phase to be reported, got %+v", stateCheck)
+	}
+	if report.Errors < 2 || report.Warnings != 1 {
+		t.Errorf("Expected the errors and warnings to be counted, got %d and %d", report.Errors, report.Warnings)
+	}
+}
+
+func TestDoctorProviders(t *testing.T) {
+	t.Setenv("AZURE_OPENAI_ENDPOINT", "")
+	configured := []providers.ProviderConfig{
+		{Name: "broken", Type: "azure", Config: map[string]any{"api_key": "k"}},
+		{Name: "keyless", Type: "openai", Config: map[string]any{"endpoint": "http://127.0.0.1:1/v1/chat/completions"}},
+		{Name: "local", Type: "openai", Config: map[string]any{"endpoint": "http://127.0.0.1:1/v1/chat/completions", "auth": "none"}},
+	}
+	pm := core.NewProviderManager(&providers.Config{Providers: configured})
+	if err := pm.InitializeAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	report := &doctorReport{}
+	report.checkProviders(pm, configured)
+	if len(report.Checks) != 3 {
+		t.Fatalf("Expected a check per provider, got %+v", report.Checks)
+	}
+	if c := report.Checks[0]; c.Status != core.IssueError || !strings.Contains(c.Message, "base_url") {
+		t.Errorf("Expected the initialization error, got %+v", c)
+	}
+	if c := report.Checks[1]; c.Status != core.IssueWarning {
+		t.Errorf("Expected a provider without a key to be unavailable, got %+v", c)
+	}
+	if c := report.Checks[2]; c.Status != doctorOK {
+		t.Errorf("Expected the provider to be available, got %+v", c)
+	}
+
+	report = &doctorReport{}
+	report.checkProviders(pm, nil)
+	if len(report.Checks) != 1 || report.Warnings != 1 {
+		t.Errorf("Expected a warning without providers, got %+v", report.Checks)
+	}
+}
+
+func TestDoctorPatterns(t *testing.T) {
+	dir := t.TempDir()
+	writePattern := func(name, settings string) {
+		t.Helper()
+		patternDir := filepath.Join(dir, name)
+		if err := os.MkdirAll(patternDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(patternDir, "system.md"), []byte("# IDENTITY\nYou summarize.\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if settings != "" {
+			if err := os.WriteFile(filepath.Join(patternDir, "pattern.yaml"), []byte(settings), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	writePattern("summarize", "")
+	writePattern("hot", "temperature: 5\n")
+
+	report := &doctorReport{}
+	report.checkPatterns([]string{dir, filepath.Join(dir, "missing")})
+	if len(report.Checks) != 2 {
+		t.Fatalf("Expected the invalid pattern and a summary, got %+v", report.Checks)
+	}
+	if c := report.Checks[0]; c.Status != core.IssueError || !strings.HasPrefix(c.Message, "hot: ") {
+		t.Errorf("Expected the invalid pattern to be reported, got %+v", c)
+	}
+	if c := report.Checks[1]; c.Status != doctorOK || c.Message != "1 pattern(s)" {
+		t.Errorf("Expected the valid patterns to be counted, got %+v", c)
+	}
+
+	report = &doctorReport{}
+	report.checkPatterns([]string{filepath.Join(dir, "missing")})
+	if report.Warnings != 1 {
+		t.Errorf("Expected a warning without pattern directories, got %+v", report.Checks)
+	}
+}
+
+func TestPrintDoctor(t *testing.T) {
+	report := &doctorReport{}
+	report.add("config", "config.yaml", core.IssueWarning, "line 2: unknown key %q", "nmae")
+	report.add("config", ".forge/config.yaml", doctorOK, "valid")
+	report.add("providers", "ollama", core.IssueError, "unreachable")
+
+	var out bytes.Buffer
+	printDoctor(&out, report.doctorView)
+	want := "config:\n" +
+		"  ! config.yaml: line 2: unknown key \"nmae\"\n" +
+		"  ✓ .forge/config.yaml: valid\n" +
+		"\nproviders:\n" +
+		"  ✗ ollama: unreachable\n" +
+		"\n1 error(s), 1 warning(s)\n"
+	if out.String() != want {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+}
+
+func TestDoctorOnNewProject(t *testing.T) {
+	setupFabricHome(t)
+	for _, args := range [][]string{{"init", "--name", "demo"}, {"doctor", "-o", "json"}} {
+		cmd := NewRootCmd("test")
+		cmd.SetArgs(args)
+		out, err := captureStdout(t, cmd.Execute)
+		if err != nil {
+			t.Fatalf("Expected %s to succeed on a new project, got %v\n%s", args[0], err, out)
+		}
+		if args[0] == "doctor" && (!strings.Contains(out, `"errors": 0`) || strings.Contains(out, "unknown provider type")) {
+			t.Errorf("Expected no errors, got:\n%s", out)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+	"github.com/spf13/cobra"
+)
+
+// historyOptions holds the filters of forge history
+type historyOptions struct {
+	limit int
+	phase string
+	since string
+	until string
+	types []string
+	stats bool
+}
+
+func newHistoryCmd() *cobra.Command {
+	var opts historyOptions
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "View the project timeline",
+		Long: `View the project timeline, newest first.
+
+The timeline merges the activity log from .forge/state.yaml with the phase
+history files in .forge/history, which record each completed phase, its
+validation attempts and any notes given to 'forge phase complete --notes'.
+
+Event types:
+  activity - entry of the activity log
+  phase    - completed (or failed 'forge auto') phase run with its duration and notes
+  attempt  - validation attempt of a completed phase
+
+--since and --until accept a date (2006-01-02), a date and time
+(2006-01-02 15:04 or RFC 3339) or an age such as 90m, 12h or 7d.
+A date-only --until includes the whole day.`,
+		Example: `  # Last 20 events
+  forge history -n 20
+
+  # Everything that happened in the planning phase this week
+  forge history --phase planning --since 7d -n 0
+
+  # Completed phases only, with duration statistics
+  forge history --type phase --stats`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+			return showHistory(format, opts)
+		},
+	}
+
+	cmd.Flags().IntVarP(&opts.limit, "limit", "n", 10, "number of entries to show (0 for all)")
+	cmd.Flags().StringVar(&opts.phase, "phase", "", "only show events of this phase")
+	cmd.Flags().StringVar(&opts.since, "since", "", "only show events at or after this time")
+	cmd.Flags().StringVar(&opts.until, "until", "", "only show events before this time")
+	cmd.Flags().StringSliceVar(&opts.types, "type", nil, "only show these event types (activity, phase, attempt)")
+	cmd.Flags().BoolVar(&opts.stats, "stats", false, "show per-phase duration statistics")
+
+	return cmd
+}
+
+// timelineFilter validates the history options and converts them to a filter
+func (o historyOptions) timelineFilter(now time.Time) (core.TimelineFilter, error) {
+	filter := core.TimelineFilter{Phase: o.phase}
+
+	if o.phase != "" && !core.IsValidPhase(o.phase) {
+		return filter, fmt.Errorf("invalid phase: %s", o.phase)
+	}
+	for _, t := range o.types {
+		if !isTimelineEventType(t) {
+			return filter, fmt.Errorf("invalid event type %q (expected %s)", t, strings.Join(core.TimelineEventTypes, ", "))
+		}
+		filter.Types = append(filter.Types, t)
+	}
+
+	var err error
+	if o.since != "" {
+		if filter.Since, err = parseHistoryTime(o.since, now, false); err != nil {
+			return filter, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if o.until != "" {
+		if filter.Until, err = parseHistoryTime(o.until, now, true); err != nil {
+			return filter, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	return filter, nil
+}
+
+func isTimelineEventType(t string) bool {
+	for _, known := range core.TimelineEventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHistoryTime parses an absolute time or an age relative to now.
+// With endOfDay, a date without a time means the start of the next day.
+func parseHistoryTime(value string, now time.Time, endOfDay bool) (time.Time, error) {
+	if n, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && strings.HasSuffix(value, "d") {
+		return now.AddDate(0, 0, -n), nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as a date, time or age", value)
+}
+
+func showHistory(format string, opts historyOptions) error {
+	state, err := core.LoadProjectState(".forge/state.yaml")
+	if err != nil {
+		return fmt.Errorf("not a forge project (run 'forge init' first)")
+	}
+
+	filter, err := opts.timelineFilter(time.Now())
+	if err != nil {
+		return err
+	}
+
+	histories, err := core.LoadPhaseHistories(core.HistoryDir(".forge/state.yaml"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: skipped unreadable history files: %v\n", err)
+	}
+
+	view := buildHistoryView(state, histories, filter, opts.limit, opts.stats)
+	if format != outputText {
+		return printOutput(format, view)
+	}
+
+	if len(view.Events) == 0 {
+		fmt.Println("No matching history yet.")
+	} else {
+		fmt.Println("Timeline:")
+		fmt.Println()
+		for _, e := range view.Events {
+			phase := e.Phase
+			if phase == "" {
+				phase = "-"
+			}
+			line := e.Message
+			if e.DurationSeconds != nil {
+				line += fmt.Sprintf(" in %s", formatSpan(seconds(*e.DurationSeconds)))
+			}
+			fmt.Printf("  %s  %-8s  %-14s  %s\n", e.Timestamp.Format("2006-01-02 15:04:05"), e.Type, phase, line)
+			if e.Notes != "" {
+				fmt.Printf("  %19s  %-8s  %-14s  Notes: %s\n", "", "", "", e.Notes)
+			}
+			if e.Commit != "" {
+				fmt.Printf("  %19s  %-8s  %-14s  Commit: %s\n", "", "", "", core.ShortSHA(e.Commit))
+			}
+		}
+	}
+
+	if opts.stats {
+		fmt.Println()
+		printPhaseStats(view.Stats)
+	}
+
+	return nil
+}
+
+// buildHistoryView filters the merged timeline and keeps the newest limit
+// events. Statistics cover the phase histories that pass the same filters.
+func buildHistoryView(state *core.ProjectState, histories []core.PhaseHistory, filter core.TimelineFilter, limit int, stats bool) historyView {
+	events := core.FilterTimeline(core.BuildTimeline(state.Activities, histories), filter)
+
+	view := historyView{Events: []timelineEventView{}}
+	for i := len(events) - 1; i >= 0 && (limit <= 0 || len(view.Events) < limit); i-- {
+		view.Events = append(view.Events, newTimelineEventView(events[i]))
+	}
+
+	if stats {
+		// Type filters select what is listed; statistics always come from completions
+		statsFilter := filter
+		statsFilter.Types = nil
+
+		var matched []core.PhaseHistory
+		for _, h := range histories {
+			if statsFilter.Match(core.TimelineEvent{Timestamp: h.CompletedAt, Type: core.EventPhase, Phase: h.Phase}) {
+				matched = append(matched, h)
+			}
+		}
+		view.Stats = []phaseStatsView{}
+		for _, s := range core.ComputePhaseStats(matched) {
+			view.Stats = append(view.Stats, newPhaseStatsView(s))
+		}
+	}
+	return view
+}
+
+func printPhaseStats(stats []phaseStatsView) {
+	fmt.Println("Phase Durations:")
+	fmt.Println()
+	if len(stats) == 0 {
+		fmt.Println("  (no completed phases)")
+		return
+	}
+
+	fmt.Printf("  %-14s  %4s  %8s  %9s  %9s  %9s  %9s\n", "Phase", "Runs", "Attempts", "Average", "Shortest", "Longest", "Total")
+	for _, s := range stats {
+		fmt.Printf("  %-14s  %4d  %8d  %9s  %9s  %9s  %9s\n", s.Phase, s.Completions, s.Attempts,
+			formatSpan(seconds(s.AverageSeconds)), formatSpan(seconds(s.ShortestSeconds)),
+			formatSpan(seconds(s.LongestSeconds)), formatSpan(seconds(s.TotalSeconds)))
+	}
+}
+
+func seconds(n int64) time.Duration {
+	return time.Duration(n) * time.Second
+}
+
+// formatSpan formats a duration compactly, e.g. "45s", "12m", "2h 5m" or "3d 4h"
+func formatSpan(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd %dh", int(d.Hours()/24), int(d.Hours())%24)
+	}
+}
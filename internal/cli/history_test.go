@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+)
+
+func TestParseHistoryTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		value     string
+		endOfDay  bool
+		want      time.Time
+		shouldErr bool
+	}{
+		{value: "7d", want: now.AddDate(0, 0, -7)},
+		{value: "90m", want: now.Add(-90 * time.Minute)},
+		{value: "2026-03-01", want: time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)},
+		{value: "2026-03-01", endOfDay: true, want: time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)},
+		{value: "2026-03-01 15:04", endOfDay: true, want: time.Date(2026, 3, 1, 15, 4, 0, 0, time.Local)},
+		{value: "yesterday", shouldErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseHistoryTime(tt.value, now, tt.endOfDay)
+		if tt.shouldErr {
+			if err == nil {
+				t.Errorf("Expected error for %q", tt.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", tt.value, err)
+		} else if !got.Equal(tt.want) {
+			t.Errorf("Expected %v for %q, got %v", tt.want, tt.value, got)
+		}
+	}
+}
+
+func TestHistoryOptionsValidation(t *testing.T) {
+	now := time.Now()
+
+	if _, err := (historyOptions{phase: "review"}).timelineFilter(now); err == nil {
+		t.Error("Expected unknown phase to be rejected")
+	}
+	if _, err := (historyOptions{types: []string{"commit"}}).timelineFilter(now); err == nil {
+		t.Error("Expected unknown event type to be rejected")
+	}
+
+	filter, err := historyOptions{phase: "planning", since: "1d", types: []string{core.EventPhase}}.timelineFilter(now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if filter.Phase != "planning" || len(filter.Types) != 1 || !filter.Since.Equal(now.AddDate(0, 0, -1)) {
+		t.Errorf("Unexpected filter: %+v", filter)
+	}
+}
+
+func TestBuildHistoryViewLimitAndStats(t *testing.T) {
+	state := core.NewProjectState()
+	base := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	state.Activities = nil
+	for i := 0; i < 5; i++ {
+		state.Activities = append(state.Activities, core.Activity{
+			Timestamp: base.Add(time.Duration(i) * time.Hour),
+			Message:   "step",
+			Phase:     "discovery",
+		})
+	}
+	histories := []core.PhaseHistory{
+		{Phase: "discovery", CompletedAt: base.Add(time.Hour), Duration: time.Hour},
+		{Phase: "discovery", CompletedAt: base.AddDate(0, 1, 0), Duration: 3 * time.Hour},
+	}
+
+	view := buildHistoryView(state, histories, core.TimelineFilter{}, 3, false)
+	if len(view.Events) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(view.Events))
+	}
+	if !view.Events[0].Timestamp.Equal(base.AddDate(0, 1, 0)) {
+		t.Errorf("Expected newest event first, got %v", view.Events[0].Timestamp)
+	}
+	if view.Stats != nil {
+		t.Error("Expected no statistics without --stats")
+	}
+
+	// Date filters apply to statistics too
+	view = buildHistoryView(state, histories, core.TimelineFilter{Until: base.AddDate(0, 0, 1)}, 0, true)
+	if len(view.Stats) != 1 || view.Stats[0].Completions != 1 || view.Stats[0].TotalSeconds != 3600 {
+		t.Errorf("Expected one discovery completion of an hour, got %+v", view.Stats)
+	}
+}
+
+func TestFormatSpan(t *testing.T) {
+	tests := map[time.Duration]string{
+		45 * time.Second:            "45s",
+		12 * time.Minute:            "12m",
+		2*time.Hour + 5*time.Minute: "2h 5m",
+		76 * time.Hour:              "3d 4h",
+	}
+	for d, want := range tests {
+		if got := formatSpan(d); got != want {
+			t.Errorf("Expected %q for %v, got %q", want, d, got)
+		}
+	}
+}
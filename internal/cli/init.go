@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +18,8 @@ func newInitCmd() *cobra.Command {
 		name        string
 		template    string
 		interactive bool
+		dryRun      bool
+		onConflict  string
 	)
 
 	cmd := &cobra.Command{
@@ -25,35 +28,52 @@ func newInitCmd() *cobra.Command {
 		Long: `Initialize a new project with forge configuration.
 
 This creates a .forge directory with project configuration and state tracking.
-Optionally use --template to scaffold from a project template.`,
+Optionally use --template to scaffold from a project template. Templates are
+read from ~/.config/fabric-lite/templates/<name>/template.yaml, falling back
+to the built-in webapp, cli, api and library templates.
+
+Scaffold paths must stay inside the project directory. Existing files are
+skipped by default; use --on-conflict overwrite or rename to replace them or
+write the new version next to them. --dry-run prints the file tree and diffs
+without writing anything. Setup commands suggested by the AI scaffold only
+run after you confirm them.`,
 		Example: `  # Initialize in current directory
   forge init --name myapp
 
   # Initialize with a template
   forge init --name myapi --template api
 
+  # Preview the template without writing files
+  forge init --template cli --dry-run
+
   # Interactive mode
   forge init --interactive`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateConflictPolicy(onConflict); err != nil {
+				return err
+			}
+			opts := scaffoldOptions{DryRun: dryRun, Conflict: onConflict}
 			if interactive {
-				return runInteractiveInit()
+				return runInteractiveInit(opts)
 			}
-			return runInit(name, template)
+			return runInit(name, template, opts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&name, "name", "n", "", "project name")
-	cmd.Flags().StringVarP(&template, "template", "t", "", "project template (webapp, cli, api, library)")
+	cmd.Flags().StringVarP(&template, "template", "t", "", "project template (webapp, cli, api, library, or a user template)")
 	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "interactive initialization")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the scaffold tree and diffs without writing files")
+	cmd.Flags().StringVar(&onConflict, "on-conflict", conflictSkip, "existing files: skip, overwrite or rename")
 
 	return cmd
 }
 
-func runInit(name, template string) error {
-	return runInitWithOptions(name, template, "", false)
+func runInit(name, template string, opts scaffoldOptions) error {
+	return runInitWithOptions(name, template, "", false, opts)
 }
 
-func runInitWithOptions(name, template, description string, skipTemplate bool) error {
+func runInitWithOptions(name, template, description string, skipTemplate bool, opts scaffoldOptions) error {
 	if name == "" {
 		// Use current directory name
 		cwd, err := os.Getwd()
@@ -63,6 +83,19 @@ func runInitWithOptions(name, template, description string, skipTemplate bool) e
 		name = filepath.Base(cwd)
 	}
 
+	if opts.DryRun {
+		fmt.Printf("Dry run - would initialize forge project: %s\n", name)
+		if template != "" && !skipTemplate {
+			cfg := core.NewProjectConfig(name, template)
+			if description != "" {
+				cfg.Description = description
+			}
+			fmt.Println()
+			return applyTemplate(cfg, nil, opts)
+		}
+		return nil
+	}
+
 	fmt.Printf("Initializing forge project: %s\n", name)
 
 	// Create .forge directory structure
@@ -102,7 +135,7 @@ func runInitWithOptions(name, template, description string, skipTemplate bool) e
 
 	// Apply template if specified and not already done via AI
 	if template != "" && !skipTemplate {
-		if err := applyTemplate(template); err != nil {
+		if err := applyTemplate(cfg, nil, opts); err != nil {
 			return fmt.Errorf("failed to apply template: %w", err)
 		}
 	}
@@ -116,9 +149,17 @@ func runInitWithOptions(name, template, description string, skipTemplate bool) e
 	return nil
 }
 
-func runInteractiveInit() error {
+func runInteractiveInit(opts scaffoldOptions) error {
 	reader := bufio.NewReader(os.Stdin)
 
+	// Suggested setup commands only run after an explicit yes
+	opts.Confirm = func(prompt string) bool {
+		fmt.Print(prompt)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes"
+	}
+
 	fmt.Println("=== AI Project Forge - Interactive Setup ===")
 	fmt.Println()
 
@@ -149,29 +190,14 @@ func runInteractiveInit() error {
 	}
 
 	// Get template
-	fmt.Println("\nAvailable templates:")
-	fmt.Println("  1. webapp  - Web application (frontend + backend)")
-	fmt.Println("  2. cli     - Command-line tool")
-	fmt.Println("  3. api     - REST API service")
-	fmt.Println("  4. library - Reusable library/package")
-	fmt.Println("  5. none    - Empty project")
-	fmt.Print("\nSelect template [1-5]: ")
-	choice, _ := reader.ReadString('\n')
-	choice = strings.TrimSpace(choice)
-
-	templates := map[string]string{
-		"1": "webapp",
-		"2": "cli",
-		"3": "api",
-		"4": "library",
-		"5": "",
+	template, err := selectTemplate(reader)
+	if err != nil {
+		return err
 	}
-	template := templates[choice]
 
 	// Ask template-specific questions
 	var templateOpts *TemplateOptions
 	if template != "" {
-		var err error
 		templateOpts, err = askTemplateQuestions(reader, template)
 		if err != nil {
 			return err
@@ -194,7 +220,7 @@ func runInteractiveInit() error {
 			Template:        template,
 			TemplateOptions: templateOpts.ToMap(template),
 		}
-		if err := scaffoldWithFallback(ctx); err != nil {
+		if err := scaffoldWithFallback(ctx, opts); err != nil {
 			// scaffoldWithFallback handles its own fallback, so this is a real error
 			return err
 		}
@@ -202,7 +228,7 @@ func runInteractiveInit() error {
 	}
 
 	// Initialize .forge directory (skip static template if AI scaffold succeeded)
-	return runInitWithOptions(name, template, description, aiScaffoldDone)
+	return runInitWithOptions(name, template, description, aiScaffoldDone, opts)
 }
 
 // offerResumeOrNew detects existing project and offers options
@@ -302,33 +328,54 @@ func handleResume() error {
 	return nil
 }
 
-func applyTemplate(template string) error {
-	// Template application will create standard directories
-	switch template {
-	case "webapp":
-		dirs := []string{"src", "src/components", "src/pages", "public", "tests"}
-		for _, d := range dirs {
-			os.MkdirAll(d, 0755)
-		}
-	case "cli":
-		dirs := []string{"cmd", "internal", "pkg", "tests"}
-		for _, d := range dirs {
-			os.MkdirAll(d, 0755)
-		}
-	case "api":
-		dirs := []string{"cmd", "internal/handlers", "internal/models", "internal/services", "tests"}
-		for _, d := range dirs {
-			os.MkdirAll(d, 0755)
-		}
-	case "library":
-		dirs := []string{"src", "tests", "examples"}
-		for _, d := range dirs {
-			os.MkdirAll(d, 0755)
-		}
-	default:
-		return fmt.Errorf("unknown template: %s", template)
+// selectTemplate lists the available templates and asks for a choice
+func selectTemplate(reader *bufio.Reader) (string, error) {
+	available, err := core.ListTemplates()
+	if err != nil {
+		return "", fmt.Errorf("failed to list templates: %w", err)
 	}
 
-	fmt.Printf("Applied template: %s\n", template)
+	fmt.Println("\nAvailable templates:")
+	for i, t := range available {
+		fmt.Printf("  %d. %-8s - %s\n", i+1, t.Name, t.Description)
+	}
+	none := len(available) + 1
+	fmt.Printf("  %d. %-8s - %s\n", none, "none", "Empty project")
+	fmt.Printf("\nSelect template [1-%d]: ", none)
+	choice, _ := reader.ReadString('\n')
+	choice = strings.TrimSpace(choice)
+
+	n, err := strconv.Atoi(choice)
+	if err != nil || n < 1 || n >= none {
+		return "", nil
+	}
+	return available[n-1].Name, nil
+}
+
+// applyTemplate renders the project template named in cfg with the given
+// option answers and applies it like any other scaffold
+func applyTemplate(cfg *core.ProjectConfig, answers map[string]interface{}, opts scaffoldOptions) error {
+	tmpl, err := core.LoadTemplate(cfg.Template)
+	if err != nil {
+		return err
+	}
+
+	rendered, err := tmpl.Render(core.NewTemplateData(cfg, tmpl, answers))
+	if err != nil {
+		return err
+	}
+
+	output := &ScaffoldOutput{Directories: rendered.Directories}
+	for _, f := range rendered.Files {
+		output.Files = append(output.Files, ScaffoldFile{Path: f.Path, Content: f.Content})
+	}
+
+	if err := applyScaffold(output, opts); err != nil {
+		return err
+	}
+
+	if !opts.DryRun {
+		fmt.Printf("Applied template: %s\n", tmpl.Name)
+	}
 	return nil
 }
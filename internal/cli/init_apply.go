@@ -0,0 +1,433 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Conflict policies for scaffold files that already exist
+const (
+	conflictSkip      = "skip"
+	conflictOverwrite = "overwrite"
+	conflictRename    = "rename"
+)
+
+// scaffoldOptions controls how a scaffold is written to disk
+type scaffoldOptions struct {
+	Root     string                   // Project root all paths must stay inside ("" = current directory)
+	DryRun   bool                     // Print the tree and diffs without writing anything
+	Conflict string                   // Existing files: skip, overwrite or rename
+	Confirm  func(prompt string) bool // Asks before running suggested commands; nil never runs them
+}
+
+// scaffoldAction is the planned outcome for a single scaffold entry
+type scaffoldAction struct {
+	Path    string // Cleaned path relative to the root
+	Target  string // Path actually written (differs from Path when renamed)
+	Dir     bool
+	Action  string // create, overwrite, rename, skip, unchanged or exists
+	Content string
+	Old     string // Existing content, for diffs
+}
+
+func validateConflictPolicy(policy string) error {
+	switch policy {
+	case conflictSkip, conflictOverwrite, conflictRename:
+		return nil
+	default:
+		return fmt.Errorf("invalid conflict policy %q (use skip, overwrite or rename)", policy)
+	}
+}
+
+// resolveScaffoldPath checks that p stays inside root and returns it cleaned.
+// Absolute paths, ".." components and symlinks leading outside root are rejected.
+func resolveScaffoldPath(root, p string) (string, error) {
+	if strings.TrimSpace(p) == "" {
+		return "", fmt.Errorf("empty path")
+	}
+	if filepath.IsAbs(p) || strings.HasPrefix(p, "/") || strings.HasPrefix(p, `\`) || filepath.VolumeName(p) != "" {
+		return "", fmt.Errorf("%s: absolute paths are not allowed", p)
+	}
+	for _, part := range strings.FieldsFunc(p, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if part == ".." {
+			return "", fmt.Errorf("%s: '..' is not allowed", p)
+		}
+	}
+
+	clean := filepath.Clean(filepath.FromSlash(p))
+	if clean == "." {
+		return "", fmt.Errorf("%s: refers to the project root", p)
+	}
+
+	rootReal, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("resolve project root: %w", err)
+	}
+
+	// The deepest part of the path that exists must resolve inside the root
+	existing := filepath.Join(rootReal, clean)
+	for existing != rootReal {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		existing = filepath.Dir(existing)
+	}
+	real, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", p, err)
+	}
+	rel, err := filepath.Rel(rootReal, real)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s: escapes the project root through a symlink", p)
+	}
+
+	return clean, nil
+}
+
+// planScaffold validates every path and decides what happens to each entry.
+// Nothing is planned if any path is unsafe.
+func planScaffold(output *ScaffoldOutput, opts scaffoldOptions) ([]scaffoldAction, error) {
+	root := opts.Root
+	if root == "" {
+		root = "."
+	}
+	policy := opts.Conflict
+	if policy == "" {
+		policy = conflictSkip
+	}
+	if err := validateConflictPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	var (
+		actions []scaffoldAction
+		unsafe  []string
+	)
+
+	for _, dir := range output.Directories {
+		clean, err := resolveScaffoldPath(root, dir)
+		if err != nil {
+			unsafe = append(unsafe, err.Error())
+			continue
+		}
+		action := scaffoldAction{Path: clean, Target: clean, Dir: true, Action: "create"}
+		if info, err := os.Stat(filepath.Join(root, clean)); err == nil {
+			if !info.IsDir() {
+				unsafe = append(unsafe, fmt.Sprintf("%s: exists and is not a directory", dir))
+				continue
+			}
+			action.Action = "exists"
+		}
+		actions = append(actions, action)
+	}
+
+	for _, file := range output.Files {
+		clean, err := resolveScaffoldPath(root, file.Path)
+		if err != nil {
+			unsafe = append(unsafe, err.Error())
+			continue
+		}
+		action := scaffoldAction{Path: clean, Target: clean, Action: "create", Content: file.Content}
+
+		full := filepath.Join(root, clean)
+		if info, err := os.Stat(full); err == nil {
+			if info.IsDir() {
+				unsafe = append(unsafe, fmt.Sprintf("%s: exists and is a directory", file.Path))
+				continue
+			}
+			old, err := os.ReadFile(full)
+			if err != nil {
+				return nil, fmt.Errorf("read %s: %w", file.Path, err)
+			}
+			action.Old = string(old)
+
+			switch {
+			case action.Old == file.Content:
+				action.Action = "unchanged"
+			case policy == conflictOverwrite:
+				action.Action = "overwrite"
+			case policy == conflictRename:
+				action.Action = "rename"
+				action.Target = renamedPath(root, clean)
+			default:
+				action.Action = "skip"
+			}
+		}
+		actions = append(actions, action)
+	}
+
+	if len(unsafe) > 0 {
+		return nil, fmt.Errorf("unsafe scaffold paths:\n  %s", strings.Join(unsafe, "\n  "))
+	}
+
+	return actions, nil
+}
+
+// renamedPath finds a free name next to path, e.g. README.new.md, README.new2.md
+func renamedPath(root, path string) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for i := 1; ; i++ {
+		suffix := ".new"
+		if i > 1 {
+			suffix = fmt.Sprintf(".new%d", i)
+		}
+		candidate := base + suffix + ext
+		if _, err := os.Lstat(filepath.Join(root, candidate)); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
+
+// applyScaffold writes a scaffold inside the project root following the
+// conflict policy, or prints the plan when DryRun is set
+func applyScaffold(output *ScaffoldOutput, opts scaffoldOptions) error {
+	actions, err := planScaffold(output, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		printScaffoldPlan(actions)
+		if len(output.Commands) > 0 {
+			fmt.Println("\nSuggested setup commands (not run):")
+			for _, cmd := range output.Commands {
+				fmt.Printf("  $ %s\n", cmd)
+			}
+		}
+		return nil
+	}
+
+	root := opts.Root
+	if root == "" {
+		root = "."
+	}
+
+	createdDirs, createdFiles, skipped := 0, 0, 0
+	for _, a := range actions {
+		full := filepath.Join(root, a.Target)
+		switch {
+		case a.Dir:
+			if a.Action == "exists" {
+				continue
+			}
+			if err := os.MkdirAll(full, 0755); err != nil {
+				return fmt.Errorf("failed to create directory %s: %w", a.Path, err)
+			}
+			createdDirs++
+		case a.Action == "skip":
+			skipped++
+		case a.Action == "unchanged":
+		default:
+			if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+				return fmt.Errorf("failed to create parent directory for %s: %w", a.Path, err)
+			}
+			if err := os.WriteFile(full, []byte(a.Content), 0644); err != nil {
+				return fmt.Errorf("failed to create file %s: %w", a.Target, err)
+			}
+			createdFiles++
+		}
+	}
+
+	fmt.Printf("Created %d directories and %d files\n", createdDirs, createdFiles)
+
+	// Print file summary
+	var written []string
+	for _, a := range actions {
+		if a.Dir {
+			continue
+		}
+		switch a.Action {
+		case "create":
+			written = append(written, fmt.Sprintf("  - %s", a.Path))
+		case "overwrite":
+			written = append(written, fmt.Sprintf("  - %s (overwritten)", a.Path))
+		case "rename":
+			written = append(written, fmt.Sprintf("  - %s (exists, written to %s)", a.Path, a.Target))
+		case "skip":
+			written = append(written, fmt.Sprintf("  - %s (skipped, already exists)", a.Path))
+		}
+	}
+	if len(written) > 0 {
+		fmt.Println("\nGenerated files:")
+		fmt.Println(strings.Join(written, "\n"))
+	}
+	if skipped > 0 {
+		fmt.Println("\nUse --on-conflict overwrite or rename to replace existing files.")
+	}
+
+	return runScaffoldCommands(output.Commands, root, opts.Confirm)
+}
+
+// runScaffoldCommands lists suggested commands and runs them only after
+// explicit confirmation
+func runScaffoldCommands(commands []string, root string, confirm func(string) bool) error {
+	if len(commands) == 0 {
+		return nil
+	}
+
+	fmt.Println("\nSuggested setup commands:")
+	for _, cmd := range commands {
+		fmt.Printf("  $ %s\n", cmd)
+	}
+
+	if confirm == nil || !confirm(fmt.Sprintf("\nRun these %d command(s) now? [y/N]: ", len(commands))) {
+		fmt.Println("Commands not run. Run them yourself once you have reviewed them.")
+		return nil
+	}
+
+	for _, command := range commands {
+		fmt.Printf("\n$ %s\n", command)
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Dir = root
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("setup command failed (%s): %w", command, err)
+		}
+	}
+
+	return nil
+}
+
+// printScaffoldPlan prints the planned tree followed by diffs of changed files
+func printScaffoldPlan(actions []scaffoldAction) {
+	fmt.Println("Dry run - scaffold would produce:")
+	fmt.Println()
+
+	// Include parent directories so the tree is complete
+	entries := make(map[string]string)
+	for _, a := range actions {
+		label := a.Action
+		if a.Action == "rename" {
+			label = "exists, would write " + filepath.ToSlash(a.Target)
+		}
+		if a.Dir {
+			entries[filepath.ToSlash(a.Path)+"/"] = label
+		} else {
+			entries[filepath.ToSlash(a.Path)] = label
+		}
+		for dir := filepath.Dir(a.Path); dir != "."; dir = filepath.Dir(dir) {
+			key := filepath.ToSlash(dir) + "/"
+			if _, ok := entries[key]; !ok {
+				entries[key] = ""
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(entries))
+	for p := range entries {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return comparePathParts(paths[i], paths[j])
+	})
+
+	for _, p := range paths {
+		trimmed := strings.TrimSuffix(p, "/")
+		depth := strings.Count(trimmed, "/")
+		name := filepath.Base(trimmed)
+		if strings.HasSuffix(p, "/") {
+			name += "/"
+		}
+		line := strings.Repeat("  ", depth+1) + name
+		if label := entries[p]; label != "" {
+			line = fmt.Sprintf("%-40s (%s)", line, label)
+		}
+		fmt.Println(line)
+	}
+
+	for _, a := range actions {
+		if a.Dir || (a.Action != "overwrite" && a.Action != "skip" && a.Action != "rename") {
+			continue
+		}
+		fmt.Printf("\n--- %s (existing)\n+++ %s (scaffold)\n", a.Path, a.Path)
+		fmt.Print(lineDiff(a.Old, a.Content))
+	}
+}
+
+// comparePathParts orders paths component by component so children follow their parent
+func comparePathParts(a, b string) bool {
+	pa := strings.Split(strings.TrimSuffix(a, "/"), "/")
+	pb := strings.Split(strings.TrimSuffix(b, "/"), "/")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		if pa[i] != pb[i] {
+			return pa[i] < pb[i]
+		}
+	}
+	return len(pa) < len(pb)
+}
+
+// lineDiff returns a line-based diff of old and updated, collapsing long unchanged runs
+func lineDiff(old, updated string) string {
+	a := strings.Split(strings.TrimSuffix(old, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(updated, "\n"), "\n")
+
+	// Longest common subsequence table
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, " "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "-"+a[i])
+			i++
+		default:
+			lines = append(lines, "+"+b[j])
+			j++
+		}
+	}
+
+	// Keep two lines of context around changes
+	const context = 2
+	var sb strings.Builder
+	skipped := 0
+	for k, line := range lines {
+		if line[0] == ' ' && !nearChange(lines, k, context) {
+			skipped++
+			continue
+		}
+		if skipped > 0 {
+			fmt.Fprintf(&sb, "@@ %d unchanged line(s) @@\n", skipped)
+			skipped = 0
+		}
+		sb.WriteString(line + "\n")
+	}
+	if skipped > 0 {
+		fmt.Fprintf(&sb, "@@ %d unchanged line(s) @@\n", skipped)
+	}
+	return sb.String()
+}
+
+func nearChange(lines []string, k, context int) bool {
+	for d := -context; d <= context; d++ {
+		if n := k + d; n >= 0 && n < len(lines) && lines[n][0] != ' ' {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveScaffoldPath(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	valid := map[string]string{
+		"README.md":          "README.md",
+		"cmd/app/main.go":    filepath.Join("cmd", "app", "main.go"),
+		"./docs//guide.md":   filepath.Join("docs", "guide.md"),
+		"internal/handlers/": filepath.Join("internal", "handlers"),
+	}
+	for input, want := range valid {
+		got, err := resolveScaffoldPath(root, input)
+		if err != nil {
+			t.Errorf("Expected %q to be allowed, got %v", input, err)
+		} else if got != want {
+			t.Errorf("Expected %q to resolve to %q, got %q", input, want, got)
+		}
+	}
+
+	invalid := []string{
+		"",
+		".",
+		"/etc/passwd",
+		"../outside.txt",
+		"src/../../outside.txt",
+		"src/../README.md",
+		"escape/file.txt",
+		"escape",
+	}
+	for _, input := range invalid {
+		if _, err := resolveScaffoldPath(root, input); err == nil {
+			t.Errorf("Expected %q to be rejected", input)
+		}
+	}
+}
+
+func TestApplyScaffoldRejectsUnsafePaths(t *testing.T) {
+	root := t.TempDir()
+	output := &ScaffoldOutput{
+		Files: []ScaffoldFile{
+			{Path: "README.md", Content: "ok"},
+			{Path: "../evil.sh", Content: "rm -rf /"},
+		},
+	}
+
+	err := applyScaffold(output, scaffoldOptions{Root: root})
+	if err == nil || !strings.Contains(err.Error(), "../evil.sh") {
+		t.Fatalf("Expected unsafe path error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "README.md")); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be written when any path is unsafe")
+	}
+}
+
+func TestApplyScaffoldConflictPolicies(t *testing.T) {
+	tests := []struct {
+		policy     string
+		wantReadme string
+		wantNew    bool
+	}{
+		{conflictSkip, "existing", false},
+		{conflictOverwrite, "generated", false},
+		{conflictRename, "existing", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.WriteFile(filepath.Join(root, "README.md"), []byte("existing"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			output := &ScaffoldOutput{
+				Directories: []string{"src"},
+				Files:       []ScaffoldFile{{Path: "README.md", Content: "generated"}},
+			}
+
+			if err := applyScaffold(output, scaffoldOptions{Root: root, Conflict: tt.policy}); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			data, _ := os.ReadFile(filepath.Join(root, "README.md"))
+			if string(data) != tt.wantReadme {
+				t.Errorf("Expected README.md to contain %q, got %q", tt.wantReadme, string(data))
+			}
+			_, err := os.Stat(filepath.Join(root, "README.new.md"))
+			if (err == nil) != tt.wantNew {
+				t.Errorf("Expected README.new.md to exist: %v", tt.wantNew)
+			}
+			if info, err := os.Stat(filepath.Join(root, "src")); err != nil || !info.IsDir() {
+				t.Error("Expected src directory to be created")
+			}
+		})
+	}
+}
+
+func TestApplyScaffoldDryRun(t *testing.T) {
+	root := t.TempDir()
+	output := &ScaffoldOutput{
+		Directories: []string{"src"},
+		Files:       []ScaffoldFile{{Path: "src/main.go", Content: "package main\n"}},
+		Commands:    []string{"touch ran"},
+	}
+
+	confirmed := false
+	opts := scaffoldOptions{
+		Root:    root,
+		DryRun:  true,
+		Confirm: func(string) bool { confirmed = true; return true },
+	}
+	if err := applyScaffold(output, opts); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	entries, _ := os.ReadDir(root)
+	if len(entries) != 0 {
+		t.Errorf("Expected dry run to write nothing, found %d entries", len(entries))
+	}
+	if confirmed {
+		t.Error("Expected dry run not to ask to run commands")
+	}
+}
+
+func TestApplyScaffoldCommandsNeedConfirmation(t *testing.T) {
+	root := t.TempDir()
+	output := &ScaffoldOutput{Commands: []string{"touch ran"}}
+
+	if err := applyScaffold(output, scaffoldOptions{Root: root}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "ran")); !os.IsNotExist(err) {
+		t.Error("Expected commands not to run without confirmation")
+	}
+
+	declined := scaffoldOptions{Root: root, Confirm: func(string) bool { return false }}
+	if err := applyScaffold(output, declined); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "ran")); !os.IsNotExist(err) {
+		t.Error("Expected declined commands not to run")
+	}
+
+	accepted := scaffoldOptions{Root: root, Confirm: func(string) bool { return true }}
+	if err := applyScaffold(output, accepted); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "ran")); err != nil {
+		t.Error("Expected confirmed commands to run")
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	diff := lineDiff("a\nb\nc\n", "a\nB\nc\n")
+	want := " a\n-b\n+B\n c\n"
+	if diff != want {
+		t.Errorf("Expected diff %q, got %q", want, diff)
+	}
+}
@@ -4,12 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/rice0649/fabric-lite/internal/core"
 	"github.com/rice0649/fabric-lite/internal/tools"
 )
 
@@ -128,58 +127,8 @@ func extractJSON(s string) string {
 	return ""
 }
 
-// createFilesFromScaffold creates directories and files from the scaffold output
-func createFilesFromScaffold(output *ScaffoldOutput) error {
-	createdDirs := 0
-	createdFiles := 0
-
-	// Create directories
-	for _, dir := range output.Directories {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %w", dir, err)
-		}
-		createdDirs++
-	}
-
-	// Create files
-	for _, file := range output.Files {
-		// Ensure parent directory exists
-		dir := filepath.Dir(file.Path)
-		if dir != "." && dir != "" {
-			if err := os.MkdirAll(dir, 0755); err != nil {
-				return fmt.Errorf("failed to create parent directory for %s: %w", file.Path, err)
-			}
-		}
-
-		if err := os.WriteFile(file.Path, []byte(file.Content), 0644); err != nil {
-			return fmt.Errorf("failed to create file %s: %w", file.Path, err)
-		}
-		createdFiles++
-	}
-
-	fmt.Printf("Created %d directories and %d files\n", createdDirs, createdFiles)
-
-	// Print created file summary
-	if len(output.Files) > 0 {
-		fmt.Println("\nGenerated files:")
-		for _, file := range output.Files {
-			fmt.Printf("  - %s\n", file.Path)
-		}
-	}
-
-	// Print suggested commands
-	if len(output.Commands) > 0 {
-		fmt.Println("\nSuggested setup commands:")
-		for _, cmd := range output.Commands {
-			fmt.Printf("  $ %s\n", cmd)
-		}
-	}
-
-	return nil
-}
-
 // scaffoldWithFallback attempts AI scaffold generation with fallback to static templates
-func scaffoldWithFallback(ctx ScaffoldContext) error {
+func scaffoldWithFallback(ctx ScaffoldContext, opts scaffoldOptions) error {
 	fmt.Println("\nGenerating project scaffold with AI...")
 
 	output, err := generateDynamicScaffold(ctx)
@@ -188,10 +137,12 @@ func scaffoldWithFallback(ctx ScaffoldContext) error {
 		fmt.Println("Falling back to static template...")
 
 		if ctx.Template != "" {
-			return applyTemplate(ctx.Template)
+			cfg := core.NewProjectConfig(ctx.Name, ctx.Template)
+			cfg.Description = ctx.Description
+			return applyTemplate(cfg, ctx.TemplateOptions, opts)
 		}
 		return nil
 	}
 
-	return createFilesFromScaffold(output)
+	return applyScaffold(output, opts)
 }
@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+	"github.com/rice0649/fabric-lite/internal/executor"
+	"github.com/rice0649/fabric-lite/internal/mcp"
+	"github.com/rice0649/fabric-lite/internal/server"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// mcpOptions configures the tools published by fabric-lite mcp
+type mcpOptions struct {
+	PatternsDir     string
+	DefaultProvider string
+	Model           string
+}
+
+func newMCPCmd(version string) *cobra.Command {
+	var (
+		opts mcpOptions
+		dir  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Serve patterns and forge commands over the Model Context Protocol",
+		Long: `Serve patterns and forge commands to MCP-capable assistants over stdio.
+
+Tools:
+  <pattern>             - one per pattern, with the / in grouped patterns written
+                          as __ (deployment__create_changelog); arguments are
+                          "input", the pattern's {{name}} variables, and optional
+                          "provider" and "model"
+  forge_phase_start     - start a phase, like 'forge phase start'
+  forge_phase_complete  - validate and complete the current phase
+
+Resources:
+  forge://status          - project status, as 'forge status --detailed -o json'
+  forge://phases/<phase>  - phase details, as 'forge phase info <phase> -o json'
+
+Forge tools and resources work on the project in the working directory
+(or --dir). Register the server with your assistant as the command
+"fabric-lite mcp", for example:
+
+  {"mcpServers": {"fabric-lite": {"command": "fabric-lite", "args": ["mcp", "--dir", "/path/to/project"]}}}`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dir != "" {
+				if err := os.Chdir(dir); err != nil {
+					return fmt.Errorf("change to project directory: %w", err)
+				}
+			}
+
+			opts.DefaultProvider = viper.GetString("provider")
+			if opts.DefaultProvider == "" {
+				opts.DefaultProvider = core.GetDefaultConfig().Tools.Codex.Provider
+			}
+			opts.Model = viper.GetString("model")
+
+			// stdout carries the protocol; anything else goes to stderr
+			s := newMCPServer(version, opts, core.GetDefaultProviderManager())
+			return s.Serve(context.Background(), os.Stdin, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "", "project directory for forge tools and resources (default: working directory)")
+	cmd.Flags().StringVar(&opts.PatternsDir, "patterns-dir", "", "directory to load patterns from (default: ./patterns or ~/.config/fabric-lite/patterns)")
+
+	return cmd
+}
+
+// newMCPServer publishes the patterns, forge tools and forge resources
+func newMCPServer(version string, opts mcpOptions, registry server.ProviderRegistry) *mcp.Server {
+	s := mcp.NewServer("fabric-lite", version)
+
+	ex := executor.NewPatternExecutor()
+	if opts.PatternsDir != "" {
+		ex.SetPatternsDir(opts.PatternsDir)
+	}
+	patterns, err := ex.ListPatterns()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: no patterns published: %v\n", err)
+	}
+	for _, p := range patterns {
+		if strings.Contains(p.Name, "__") {
+			// The tool name couldn't be mapped back to the pattern
+			fmt.Fprintf(os.Stderr, "Warning: pattern %s not published: names can't contain __\n", p.Name)
+			continue
+		}
+		s.AddTool(patternTool(p, opts, registry))
+	}
+
+	// Forge tools are added last so they win over a pattern of the same name
+	addForgeTools(s)
+	addForgeResources(s)
+	return s
+}
+
+// patternTool runs a pattern with its variables as arguments
+func patternTool(p executor.PatternInfo, opts mcpOptions, registry server.ProviderRegistry) mcp.Tool {
+	schema := mcp.Schema{
+		Type: "object",
+		Properties: map[string]mcp.Property{
+			"input":    {Type: "string", Description: "Text to run the pattern on"},
+			"provider": {Type: "string", Description: "Provider to use (default: " + opts.DefaultProvider + ")"},
+			"model":    {Type: "string", Description: "Model to use"},
+		},
+		Required: []string{"input"},
+	}
+	for _, name := range p.Variables {
+		schema.Properties[name] = mcp.Property{Type: "string", Description: "Value for {{" + name + "}}"}
+		schema.Required = append(schema.Required, name)
+	}
+
+	toolName := mcpToolName(p.Name)
+	return mcp.Tool{
+		Name:        toolName,
+		Description: p.Description,
+		InputSchema: schema,
+		Handler: func(ctx context.Context, raw json.RawMessage) (string, error) {
+			args, err := stringArgs(raw)
+			if err != nil {
+				return "", err
+			}
+			for _, name := range schema.Required {
+				if _, ok := args[name]; !ok {
+					return "", fmt.Errorf("missing required argument: %s", name)
+				}
+			}
+
+			providerName := args["provider"]
+			if providerName == "" {
+				providerName = opts.DefaultProvider
+			}
+			provider, err := registry.Get(providerName)
+			if err != nil {
+				return "", err
+			}
+			// The configured model belongs to the default provider; other
+			// providers use their own default
+			model := args["model"]
+			if model == "" && providerName == opts.DefaultProvider {
+				model = opts.Model
+			}
+
+			vars := make(map[string]string, len(p.Variables))
+			for _, name := range p.Variables {
+				vars[name] = args[name]
+			}
+
+			pe := executor.NewPatternExecutor()
+			if opts.PatternsDir != "" {
+				pe.SetPatternsDir(opts.PatternsDir)
+			}
+			pe.LoadProviderDirect(providerName, provider)
+			pe.SetVariables(vars)
+			resp, err := pe.ExecuteWithOptions(ctx, mcpPatternName(toolName), args["input"], providerName, model, false)
+			if err != nil {
+				return "", err
+			}
+			return resp.Content, nil
+		},
+	}
+}
+
+// mcpToolName names the tool for a pattern. Tool names can't contain /,
+// so grouped patterns such as deployment/create_changelog become
+// deployment__create_changelog.
+func mcpToolName(pattern string) string {
+	return strings.ReplaceAll(pattern, "/", "__")
+}
+
+// mcpPatternName returns the pattern a tool named by mcpToolName runs
+func mcpPatternName(tool string) string {
+	return strings.ReplaceAll(tool, "__", "/")
+}
+
+// stringArgs decodes tool arguments that must all be strings
+func stringArgs(raw json.RawMessage) (map[string]string, error) {
+	var values map[string]interface{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("arguments must be an object: %w", err)
+	}
+	args := make(map[string]string, len(values))
+	for name, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("argument %s must be a string", name)
+		}
+		args[name] = s
+	}
+	return args, nil
+}
+
+// addForgeTools publishes forge phase start and complete
+func addForgeTools(s *mcp.Server) {
+	s.AddTool(mcp.Tool{
+		Name:        "forge_phase_start",
+		Description: "Start a forge development phase and list its checkpoint criteria",
+		InputSchema: mcp.Schema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"phase": {Type: "string", Description: "Phase to start", Enum: core.PhaseNames()},
+				"force": {Type: "boolean", Description: "Start even if another phase is active or dependencies are incomplete"},
+			},
+			Required: []string{"phase"},
+		},
+		Handler: func(ctx context.Context, raw json.RawMessage) (string, error) {
+			var args struct {
+				Phase string `json:"phase"`
+				Force bool   `json:"force"`
+			}
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+			var out bytes.Buffer
+			err := startPhase(&out, args.Phase, args.Force)
+			return out.String(), err
+		},
+	})
+
+	s.AddTool(mcp.Tool{
+		Name:        "forge_phase_complete",
+		Description: "Run checkpoint validation and complete the current forge phase",
+		InputSchema: mcp.Schema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"notes":        {Type: "string", Description: "Notes to record in the phase history"},
+				"skip_check":   {Type: "boolean", Description: "Skip checkpoint validation"},
+				"skip_ai":      {Type: "boolean", Description: "Run file checks only, without AI validation"},
+				"force_commit": {Type: "boolean", Description: "With git auto-commit, commit even when other files have uncommitted changes"},
+			},
+		},
+		Handler: func(ctx context.Context, raw json.RawMessage) (string, error) {
+			var args struct {
+				Notes       string `json:"notes"`
+				SkipCheck   bool   `json:"skip_check"`
+				SkipAI      bool   `json:"skip_ai"`
+				ForceCommit bool   `json:"force_commit"`
+			}
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+			var out bytes.Buffer
+			err := completePhase(&out, phaseCompleteOptions{
+				SkipCheck:   args.SkipCheck,
+				SkipAI:      args.SkipAI,
+				Notes:       args.Notes,
+				ForceCommit: args.ForceCommit,
+			})
+			return out.String(), err
+		},
+	})
+}
+
+// addForgeResources publishes the project status and each phase as JSON
+// views, the same as forge status and forge phase info with -o json
+func addForgeResources(s *mcp.Server) {
+	s.AddResource(mcp.Resource{
+		URI:         "forge://status",
+		Name:        "Forge project status",
+		Description: "Current phase, progress, checkpoint and recent activity",
+		MimeType:    "application/json",
+		Read: func() (string, error) {
+			cfg, state, err := loadProject()
+			if err != nil {
+				return "", err
+			}
+			return renderJSON(buildStatusView(cfg, state, true))
+		},
+	})
+
+	for i := range core.AllPhases {
+		phase := &core.AllPhases[i]
+		s.AddResource(mcp.Resource{
+			URI:         "forge://phases/" + phase.Name,
+			Name:        "Forge phase: " + phase.Name,
+			Description: phase.Description,
+			MimeType:    "application/json",
+			Read: func() (string, error) {
+				state, err := core.LoadProjectState(".forge/state.yaml")
+				if err != nil {
+					state = nil
+				}
+				return renderJSON(newPhaseDetailView(phase, state))
+			},
+		})
+	}
+}
+
+func renderJSON(v interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := renderOutput(&buf, outputJSON, v); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+	"github.com/rice0649/fabric-lite/internal/mcp"
+	"github.com/rice0649/fabric-lite/internal/providers"
+)
+
+// echoProvider replies with the prompt it was sent
+type echoProvider struct {
+	last providers.CompletionRequest
+}
+
+func (p *echoProvider) Name() string { return "echo" }
+
+func (p *echoProvider) Execute(ctx context.Context, req providers.CompletionRequest) (*providers.CompletionResponse, error) {
+	p.last = req
+	return &providers.CompletionResponse{Content: "echo: " + req.Prompt, Model: req.Model}, nil
+}
+
+func (p *echoProvider) ExecuteStream(ctx context.Context, req providers.CompletionRequest) (<-chan providers.StreamChunk, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (p *echoProvider) IsAvailable() bool   { return true }
+func (p *echoProvider) GetModels() []string { return nil }
+
+// echoRegistry serves the provider as "echo" and "echo2"
+type echoRegistry struct{ provider *echoProvider }
+
+func (r echoRegistry) Get(name string) (providers.Provider, error) {
+	if name != "echo" && name != "echo2" {
+		return nil, fmt.Errorf("provider not found: %s", name)
+	}
+	return r.provider, nil
+}
+
+func (r echoRegistry) ListAvailable() []string { return []string{"echo"} }
+
+type mcpToolResult struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+	IsError bool `json:"isError"`
+}
+
+func callMCPTool(t *testing.T, c *mcp.Client, name string, args map[string]interface{}) mcpToolResult {
+	t.Helper()
+	var result mcpToolResult
+	if err := c.Call("tools/call", map[string]interface{}{"name": name, "arguments": args}, &result); err != nil {
+		t.Fatalf("Failed to call %s: %v", name, err)
+	}
+	return result
+}
+
+func TestNewMCPCmd(t *testing.T) {
+	cmd := newMCPCmd("1.0.0-test")
+	if cmd == nil {
+		t.Fatal("Expected mcp command to be non-nil")
+	}
+	if cmd.Use != "mcp" {
+		t.Errorf("Expected command use to be 'mcp', got '%s'", cmd.Use)
+	}
+
+	for _, name := range []string{"dir", "patterns-dir"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected '%s' flag to be present", name)
+		}
+	}
+}
+
+func TestMCPServer(t *testing.T) {
+	cfg, _ := seedForgeProject(t)
+	if err := cfg.Save(".forge/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.NewProjectState().Save(".forge/state.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	patternDir := filepath.Join("patterns", "explain_code")
+	if err := os.MkdirAll(patternDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(patternDir, "system.md"), []byte("Explain {{language}} code.\n"), 0644)
+	os.WriteFile(filepath.Join(patternDir, "user.md"), []byte("Code:\n{{input}}\n"), 0644)
+	groupedDir := filepath.Join("patterns", "deployment", "create_changelog")
+	if err := os.MkdirAll(groupedDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(groupedDir, "system.md"), []byte("Write a changelog.\n"), 0644)
+
+	provider := &echoProvider{}
+	s := newMCPServer("1.0.0-test", mcpOptions{PatternsDir: "patterns", DefaultProvider: "echo", Model: "echo-large"}, echoRegistry{provider})
+	c := s.Connect(context.Background())
+	defer c.Close()
+	if err := c.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	var list struct {
+		Tools []mcp.Tool `json:"tools"`
+	}
+	if err := c.Call("tools/list", nil, &list); err != nil {
+		t.Fatal(err)
+	}
+	names := make(map[string]mcp.Tool)
+	for _, tool := range list.Tools {
+		names[tool.Name] = tool
+	}
+	for _, name := range []string{"explain_code", "deployment__create_changelog", "forge_phase_start", "forge_phase_complete"} {
+		if _, ok := names[name]; !ok {
+			t.Errorf("Expected tool '%s', got %v", name, list.Tools)
+		}
+	}
+	schema := names["explain_code"].InputSchema
+	if _, ok := schema.Properties["language"]; !ok || strings.Join(schema.Required, ",") != "input,language" {
+		t.Errorf("Expected the pattern variable in the input schema, got %+v", schema)
+	}
+
+	result := callMCPTool(t, c, "explain_code", map[string]interface{}{"input": "x := 1"})
+	if !result.IsError || !strings.Contains(result.Content[0].Text, "missing required argument: language") {
+		t.Errorf("Expected a missing variable error, got %+v", result)
+	}
+	result = callMCPTool(t, c, "explain_code", map[string]interface{}{"input": "x := 1", "language": "Go"})
+	if result.IsError || strings.TrimSpace(result.Content[0].Text) != "echo: Code:\nx := 1" || strings.TrimSpace(provider.last.System) != "Explain Go code." {
+		t.Errorf("Expected the pattern to run with the variable, got %+v, system %q", result, provider.last.System)
+	}
+
+	// The configured model only applies to the default provider
+	result = callMCPTool(t, c, "deployment__create_changelog", map[string]interface{}{"input": "v1"})
+	if result.IsError || strings.TrimSpace(provider.last.System) != "Write a changelog." || provider.last.Model != "echo-large" {
+		t.Errorf("Expected the grouped pattern to run with the configured model, got %+v, request %+v", result, provider.last)
+	}
+	result = callMCPTool(t, c, "deployment__create_changelog", map[string]interface{}{"input": "v1", "provider": "echo2"})
+	if result.IsError || provider.last.Model == "echo-large" {
+		t.Errorf("Expected the configured model not to be sent to another provider, got %+v, model %q", result, provider.last.Model)
+	}
+
+	result = callMCPTool(t, c, "forge_phase_start", map[string]interface{}{"phase": "discovery"})
+	if result.IsError || !strings.Contains(result.Content[0].Text, "Started phase: discovery") {
+		t.Errorf("Expected the phase to start, got %+v", result)
+	}
+	result = callMCPTool(t, c, "forge_phase_start", map[string]interface{}{"phase": "planning"})
+	if !result.IsError || !strings.Contains(result.Content[0].Text, "already in phase 'discovery'") {
+		t.Errorf("Expected an error while another phase is active, got %+v", result)
+	}
+
+	var read struct {
+		Contents []struct {
+			Text string `json:"text"`
+		} `json:"contents"`
+	}
+	if err := c.Call("resources/read", map[string]string{"uri": "forge://status"}, &read); err != nil {
+		t.Fatal(err)
+	}
+	var status statusView
+	if err := json.Unmarshal([]byte(read.Contents[0].Text), &status); err != nil {
+		t.Fatalf("Expected JSON status, got %v", err)
+	}
+	if status.CurrentPhase == nil || status.CurrentPhase.Name != "discovery" {
+		t.Errorf("Expected discovery as the current phase, got %+v", status.CurrentPhase)
+	}
+	if err := c.Call("resources/read", map[string]string{"uri": "forge://phases/planning"}, &read); err != nil {
+		t.Errorf("Expected the planning phase resource, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+	"github.com/rice0649/fabric-lite/internal/providers"
+	"github.com/rice0649/fabric-lite/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// modelsTimeout bounds model discovery across all providers
+const modelsTimeout = 30 * time.Second
+
+func newModelsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "models",
+		Short: "List the models of each provider",
+		Long: `List the models each configured provider serves, with their context
+windows and capabilities where the provider reports them. Providers come
+from the tools section of config.yaml and from providers.yaml next to it.
+
+OpenAI-compatible providers (including LM Studio and vLLM) are asked via
+their /v1/models endpoint and Anthropic via its models API. Results are
+cached for models_cache_seconds (default one hour). When a provider can't
+be reached, the models declared in its "models" config are shown instead.`,
+		Example: `  # Every provider
+  fabric-lite models
+
+  # One provider, as JSON
+  fabric-lite models --provider anthropic -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			registry := core.GetDefaultProviderManager()
+			names := registry.ListAvailable()
+			if cmd.Flags().Changed("provider") {
+				provider, _ := cmd.Flags().GetString("provider")
+				names = []string{provider}
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), modelsTimeout)
+			defer cancel()
+			views := collectModels(ctx, registry, names)
+
+			if format != outputText {
+				return printOutput(format, views)
+			}
+			return printModels(os.Stdout, views)
+		},
+	}
+}
+
+// collectModels lists the models of the named providers concurrently,
+// sorted by provider name
+func collectModels(ctx context.Context, registry server.ProviderRegistry, names []string) []providerModelsView {
+	names = append([]string(nil), names...)
+	sort.Strings(names)
+
+	views := make([]providerModelsView, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		views[i] = providerModelsView{Provider: name, Models: []providers.ModelInfo{}}
+		provider, err := registry.Get(name)
+		if err != nil {
+			views[i].Error = err.Error()
+			continue
+		}
+		views[i].Available = provider.IsAvailable()
+
+		wg.Add(1)
+		go func(view *providerModelsView, provider providers.Provider) {
+			defer wg.Done()
+			models, err := providers.ListModels(ctx, provider)
+			if err != nil {
+				view.Error = err.Error()
+			}
+			if models != nil {
+				view.Models = models
+			}
+		}(&views[i], provider)
+	}
+	wg.Wait()
+	return views
+}
+
+func printModels(w io.Writer, views []providerModelsView) error {
+	if len(views) == 0 {
+		fmt.Fprintln(w, "No providers configured. Enable tools.ollama or tools.claude in config.yaml, or add providers to providers.yaml.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, view := range views {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		status := ""
+		if !view.Available {
+			status = " (not available)"
+		}
+		fmt.Fprintf(tw, "%s%s\n", view.Provider, status)
+		if view.Error != "" {
+			fmt.Fprintf(tw, "  Warning: %s\n", view.Error)
+		}
+		if len(view.Models) == 0 {
+			fmt.Fprintln(tw, "  No models listed.")
+			continue
+		}
+		fmt.Fprintln(tw, "  MODEL\tCONTEXT\tCAPABILITIES\tSOURCE")
+		for _, m := range view.Models {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", m.ID, contextWindow(m.ContextWindow), orDash(strings.Join(m.Capabilities, ", ")), orDash(m.Source))
+		}
+	}
+	return tw.Flush()
+}
+
+// contextWindow formats a token count as e.g. 128k, or - when unknown
+func contextWindow(tokens int) string {
+	switch {
+	case tokens <= 0:
+		return "-"
+	case tokens%1024 == 0 && tokens >= 1024:
+		return strconv.Itoa(tokens/1024) + "k"
+	case tokens%1000 == 0:
+		return strconv.Itoa(tokens/1000) + "k"
+	default:
+		return strconv.Itoa(tokens)
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rice0649/fabric-lite/internal/providers"
+)
+
+func TestCollectModels(t *testing.T) {
+	views := collectModels(context.Background(), echoRegistry{&echoProvider{}}, []string{"missing", "echo"})
+	if len(views) != 2 || views[0].Provider != "echo" || views[1].Provider != "missing" {
+		t.Fatalf("Expected providers sorted by name, got %+v", views)
+	}
+	if !views[0].Available || views[0].Error != "" {
+		t.Errorf("Expected echo to be listed, got %+v", views[0])
+	}
+	if views[1].Error == "" || views[1].Available {
+		t.Errorf("Expected an error for an unknown provider, got %+v", views[1])
+	}
+
+	views[0].Models = []providers.ModelInfo{{ID: "big", ContextWindow: 131072, Capabilities: []string{"tools", "vision"}, Source: providers.ModelSourceAPI}}
+	var out bytes.Buffer
+	if err := printModels(&out, views); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"echo\n", "big", "128k", "tools, vision", "missing (not available)", "Warning: provider not found: missing"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestContextWindow(t *testing.T) {
+	for tokens, want := range map[int]string{0: "-", 4096: "4k", 200000: "200k", 32000: "32k", 12345: "12345"} {
+		if got := contextWindow(tokens); got != want {
+			t.Errorf("contextWindow(%d) = %q, want %q", tokens, got, want)
+		}
+	}
+}
+
+func TestModelsCommandWithOpenAICompatibleProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"data": [{"id": "qwen2.5-coder"}, {"id": "llama3.1-8b"}]}`))
+	}))
+	defer server.Close()
+
+	configDir := setupFabricHome(t)
+	providersYAML := `providers:
+  - name: lmstudio
+    type: openai
+    config:
+      endpoint: ` + server.URL + `/v1/chat/completions
+      auth: none
+`
+	if err := os.WriteFile(filepath.Join(configDir, "providers.yaml"), []byte(providersYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewRootCmd("test")
+	cmd.SetArgs([]string{"models"})
+	out, err := captureStdout(t, cmd.Execute)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{"lmstudio\n", "llama3.1-8b", "qwen2.5-coder"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "No providers configured") {
+		t.Errorf("Expected the provider from providers.yaml to be listed:\n%s", out)
+	}
+}
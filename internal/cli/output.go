@@ -0,0 +1,486 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+	"github.com/rice0649/fabric-lite/internal/providers"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by the global --output flag
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// outputFormat returns the --output format for cmd, defaulting to text
+// when the flag is not available (e.g. a subcommand run on its own)
+func outputFormat(cmd *cobra.Command) (string, error) {
+	flag := cmd.Flags().Lookup("output")
+	if flag == nil {
+		return outputText, nil
+	}
+	switch format := flag.Value.String(); format {
+	case "", outputText:
+		return outputText, nil
+	case outputJSON, outputYAML:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid output format %q (expected text, json or yaml)", format)
+	}
+}
+
+// renderOutput writes v as JSON or YAML. Field names come from the json and
+// yaml tags of the view structs below and are part of forge's stable output.
+func renderOutput(w io.Writer, format string, v interface{}) error {
+	switch format {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case outputYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("cannot render %s output", format)
+	}
+}
+
+// printOutput renders v to stdout for machine-readable formats
+func printOutput(format string, v interface{}) error {
+	return renderOutput(os.Stdout, format, v)
+}
+
+// projectView describes the project from .forge/config.yaml
+type projectView struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Template    string `json:"template,omitempty" yaml:"template,omitempty"`
+	Version     string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
+// phaseView is a phase with its status in the project
+type phaseView struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description" yaml:"description"`
+	Status      string   `json:"status" yaml:"status"`
+	Current     bool     `json:"current" yaml:"current"`
+	PrimaryTool string   `json:"primary_tool" yaml:"primary_tool"`
+	DependsOn   []string `json:"depends_on" yaml:"depends_on"`
+}
+
+// phaseDetailView is the output of forge phase info
+type phaseDetailView struct {
+	phaseView          `yaml:",inline"`
+	ToolReason         string   `json:"tool_reason" yaml:"tool_reason"`
+	Criteria           []string `json:"criteria" yaml:"criteria"`
+	Artifacts          []string `json:"artifacts" yaml:"artifacts"`
+	GeneratedArtifacts []string `json:"generated_artifacts" yaml:"generated_artifacts"`
+}
+
+// currentPhaseView describes the active phase
+type currentPhaseView struct {
+	Name           string    `json:"name" yaml:"name"`
+	PrimaryTool    string    `json:"primary_tool" yaml:"primary_tool"`
+	StartedAt      time.Time `json:"started_at" yaml:"started_at"`
+	ElapsedSeconds int64     `json:"elapsed_seconds" yaml:"elapsed_seconds"`
+}
+
+// progressView counts completed phases
+type progressView struct {
+	Completed int     `json:"completed" yaml:"completed"`
+	Total     int     `json:"total" yaml:"total"`
+	Percent   float64 `json:"percent" yaml:"percent"`
+}
+
+// checkView is one checkpoint check
+type checkView struct {
+	Name    string `json:"name" yaml:"name"`
+	Passed  bool   `json:"passed" yaml:"passed"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// checkpointView is the checkpoint status of the current phase
+type checkpointView struct {
+	Phase  string      `json:"phase" yaml:"phase"`
+	Passed bool        `json:"passed" yaml:"passed"`
+	Met    int         `json:"met" yaml:"met"`
+	Checks []checkView `json:"checks" yaml:"checks"`
+}
+
+// activityView is one entry of the activity log
+type activityView struct {
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+	Phase     string    `json:"phase,omitempty" yaml:"phase,omitempty"`
+	Message   string    `json:"message" yaml:"message"`
+}
+
+// statusView is the output of forge status
+type statusView struct {
+	Project        projectView         `json:"project" yaml:"project"`
+	CurrentPhase   *currentPhaseView   `json:"current_phase" yaml:"current_phase"`
+	Phases         []phaseView         `json:"phases" yaml:"phases"`
+	Progress       progressView        `json:"progress" yaml:"progress"`
+	ArtifactCount  int                 `json:"artifact_count" yaml:"artifact_count"`
+	Artifacts      map[string][]string `json:"artifacts,omitempty" yaml:"artifacts,omitempty"` // Only with --detailed
+	Checkpoint     *checkpointView     `json:"checkpoint" yaml:"checkpoint"`
+	RecentActivity []activityView      `json:"recent_activity" yaml:"recent_activity"`
+	NextSteps      []string            `json:"next_steps" yaml:"next_steps"`
+}
+
+// timelineEventView is one event of forge history. Duration, verdict and
+// notes are only set for the event types that carry them.
+type timelineEventView struct {
+	Timestamp       time.Time `json:"timestamp" yaml:"timestamp"`
+	Type            string    `json:"type" yaml:"type"`
+	Phase           string    `json:"phase" yaml:"phase"`
+	Message         string    `json:"message" yaml:"message"`
+	DurationSeconds *int64    `json:"duration_seconds,omitempty" yaml:"duration_seconds,omitempty"`
+	Verdict         string    `json:"verdict,omitempty" yaml:"verdict,omitempty"`
+	Notes           string    `json:"notes,omitempty" yaml:"notes,omitempty"`
+	Commit          string    `json:"commit,omitempty" yaml:"commit,omitempty"`
+}
+
+// phaseStatsView summarizes the durations of a phase's completions
+type phaseStatsView struct {
+	Phase           string `json:"phase" yaml:"phase"`
+	Completions     int    `json:"completions" yaml:"completions"`
+	Attempts        int    `json:"attempts" yaml:"attempts"`
+	TotalSeconds    int64  `json:"total_seconds" yaml:"total_seconds"`
+	AverageSeconds  int64  `json:"average_seconds" yaml:"average_seconds"`
+	ShortestSeconds int64  `json:"shortest_seconds" yaml:"shortest_seconds"`
+	LongestSeconds  int64  `json:"longest_seconds" yaml:"longest_seconds"`
+}
+
+// historyView is the output of forge history
+type historyView struct {
+	Events []timelineEventView `json:"events" yaml:"events"`
+	Stats  []phaseStatsView    `json:"stats,omitempty" yaml:"stats,omitempty"` // Only with --stats
+}
+
+// sessionView is the output of forge session show
+type sessionView struct {
+	GeneratedAt    time.Time           `json:"generated_at" yaml:"generated_at"`
+	Project        projectView         `json:"project" yaml:"project"`
+	CurrentPhase   *currentPhaseView   `json:"current_phase" yaml:"current_phase"`
+	Phases         []phaseView         `json:"phases" yaml:"phases"`
+	Progress       progressView        `json:"progress" yaml:"progress"`
+	Artifacts      map[string][]string `json:"artifacts" yaml:"artifacts"`
+	Checkpoint     *checkpointView     `json:"checkpoint" yaml:"checkpoint"`
+	RecentActivity []activityView      `json:"recent_activity" yaml:"recent_activity"`
+	NextSteps      []string            `json:"next_steps" yaml:"next_steps"`
+	ResumePrompt   string              `json:"resume_prompt" yaml:"resume_prompt"`
+}
+
+// phaseMetricsView is one row of forge report. pass_rate is null when no
+// attempt was validated.
+type phaseMetricsView struct {
+	Phase              string   `json:"phase" yaml:"phase"`
+	Status             string   `json:"status,omitempty" yaml:"status,omitempty"`
+	TimeInPhaseSeconds int64    `json:"time_in_phase_seconds" yaml:"time_in_phase_seconds"`
+	Completions        int      `json:"completions" yaml:"completions"`
+	Reopens            int      `json:"reopens" yaml:"reopens"`
+	ValidationFailures int      `json:"validation_failures" yaml:"validation_failures"`
+	Rework             int      `json:"rework" yaml:"rework"`
+	ValidatedAttempts  int      `json:"validated_attempts" yaml:"validated_attempts"`
+	PassedAttempts     int      `json:"passed_attempts" yaml:"passed_attempts"`
+	PassRate           *float64 `json:"pass_rate" yaml:"pass_rate"`
+	Tokens             int      `json:"tokens" yaml:"tokens"`
+	Cost               float64  `json:"cost" yaml:"cost"`
+}
+
+// projectReportView is the report of one project
+type projectReportView struct {
+	Name     string             `json:"name" yaml:"name"`
+	Dir      string             `json:"dir" yaml:"dir"`
+	Phases   []phaseMetricsView `json:"phases" yaml:"phases"`
+	Total    phaseMetricsView   `json:"total" yaml:"total"`
+	Warnings []string           `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// aggregateReportView sums the reports of several projects
+type aggregateReportView struct {
+	Projects int                `json:"projects" yaml:"projects"`
+	Phases   []phaseMetricsView `json:"phases" yaml:"phases"`
+	Total    phaseMetricsView   `json:"total" yaml:"total"`
+}
+
+// reportView is the output of forge report
+type reportView struct {
+	GeneratedAt time.Time            `json:"generated_at" yaml:"generated_at"`
+	Projects    []projectReportView  `json:"projects" yaml:"projects"`
+	Aggregate   *aggregateReportView `json:"aggregate,omitempty" yaml:"aggregate,omitempty"` // Only for several projects
+}
+
+func newProjectView(cfg *core.ProjectConfig) projectView {
+	return projectView{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		Template:    cfg.Template,
+		Version:     cfg.Version,
+	}
+}
+
+// newPhaseView describes p; state may be nil outside a forge project
+func newPhaseView(p *core.Phase, state *core.ProjectState) phaseView {
+	view := phaseView{
+		Name:        p.Name,
+		Description: p.Description,
+		Status:      "pending",
+		PrimaryTool: p.PrimaryTool,
+		DependsOn:   append([]string{}, p.DependsOn...),
+	}
+	if state != nil {
+		view.Status = state.GetPhaseStatus(p.Name)
+		view.Current = p.Name == state.CurrentPhase
+	}
+	return view
+}
+
+func newPhaseViews(state *core.ProjectState) []phaseView {
+	views := make([]phaseView, 0, len(core.AllPhases))
+	for i := range core.AllPhases {
+		views = append(views, newPhaseView(&core.AllPhases[i], state))
+	}
+	return views
+}
+
+func newPhaseDetailView(p *core.Phase, state *core.ProjectState) phaseDetailView {
+	return phaseDetailView{
+		phaseView:          newPhaseView(p, state),
+		ToolReason:         p.ToolReason,
+		Criteria:           append([]string{}, p.Checkpoint.Criteria...),
+		Artifacts:          append([]string{}, p.Artifacts...),
+		GeneratedArtifacts: phaseArtifacts(p.Name),
+	}
+}
+
+func newCurrentPhaseView(state *core.ProjectState) *currentPhaseView {
+	if state.CurrentPhase == "" {
+		return nil
+	}
+	view := &currentPhaseView{
+		Name:      state.CurrentPhase,
+		StartedAt: state.PhaseStartedAt,
+	}
+	if phase := core.GetPhase(state.CurrentPhase); phase != nil {
+		view.PrimaryTool = phase.PrimaryTool
+	}
+	if !state.PhaseStartedAt.IsZero() {
+		view.ElapsedSeconds = int64(time.Since(state.PhaseStartedAt).Seconds())
+	}
+	return view
+}
+
+func newProgressView(state *core.ProjectState) progressView {
+	view := progressView{Total: len(core.AllPhases)}
+	for _, p := range core.AllPhases {
+		if state.GetPhaseStatus(p.Name) == "completed" {
+			view.Completed++
+		}
+	}
+	if view.Total > 0 {
+		view.Percent = float64(view.Completed) / float64(view.Total) * 100
+	}
+	return view
+}
+
+// newCheckpointView validates the current phase's checkpoint, if any
+func newCheckpointView(state *core.ProjectState) *checkpointView {
+	if state.CurrentPhase == "" {
+		return nil
+	}
+	result := core.ValidateCheckpoint(state.CurrentPhase)
+	view := &checkpointView{Phase: result.Phase, Passed: result.Passed, Checks: []checkView{}}
+	for _, check := range result.Checks {
+		if check.Passed {
+			view.Met++
+		}
+		view.Checks = append(view.Checks, checkView{Name: check.Name, Passed: check.Passed, Message: check.Message})
+	}
+	return view
+}
+
+// newActivityViews returns up to limit activities, newest first
+func newActivityViews(activities []core.Activity, limit int) []activityView {
+	views := []activityView{}
+	for i := len(activities) - 1; i >= 0 && len(views) < limit; i-- {
+		a := activities[i]
+		views = append(views, activityView{Timestamp: a.Timestamp, Phase: a.Phase, Message: a.Message})
+	}
+	return views
+}
+
+func newTimelineEventView(e core.TimelineEvent) timelineEventView {
+	view := timelineEventView{
+		Timestamp: e.Timestamp,
+		Type:      e.Type,
+		Phase:     e.Phase,
+		Message:   e.Message,
+		Verdict:   e.Verdict,
+		Notes:     e.Notes,
+		Commit:    e.Commit,
+	}
+	if e.Type == core.EventPhase {
+		seconds := int64(e.Duration.Seconds())
+		view.DurationSeconds = &seconds
+	}
+	return view
+}
+
+func newPhaseStatsView(s core.PhaseStats) phaseStatsView {
+	return phaseStatsView{
+		Phase:           s.Phase,
+		Completions:     s.Completions,
+		Attempts:        s.Attempts,
+		TotalSeconds:    int64(s.Total.Seconds()),
+		AverageSeconds:  int64(s.Average.Seconds()),
+		ShortestSeconds: int64(s.Shortest.Seconds()),
+		LongestSeconds:  int64(s.Longest.Seconds()),
+	}
+}
+
+func newPhaseMetricsView(m core.PhaseMetrics) phaseMetricsView {
+	view := phaseMetricsView{
+		Phase:              m.Phase,
+		Status:             m.Status,
+		TimeInPhaseSeconds: int64(m.TimeInPhase.Seconds()),
+		Completions:        m.Completions,
+		Reopens:            m.Reopens,
+		ValidationFailures: m.ValidationFailures,
+		Rework:             m.Rework(),
+		ValidatedAttempts:  m.ValidatedAttempts,
+		PassedAttempts:     m.PassedAttempts,
+		Tokens:             m.Tokens,
+		Cost:               m.Cost,
+	}
+	if rate, ok := m.PassRate(); ok {
+		view.PassRate = &rate
+	}
+	return view
+}
+
+func newPhaseMetricsViews(metrics []core.PhaseMetrics) []phaseMetricsView {
+	views := make([]phaseMetricsView, 0, len(metrics))
+	for _, m := range metrics {
+		views = append(views, newPhaseMetricsView(m))
+	}
+	return views
+}
+
+func buildReportView(reports []*core.ProjectReport, generatedAt time.Time) reportView {
+	view := reportView{GeneratedAt: generatedAt, Projects: []projectReportView{}}
+	for _, r := range reports {
+		view.Projects = append(view.Projects, projectReportView{
+			Name:     r.Name,
+			Dir:      r.Dir,
+			Phases:   newPhaseMetricsViews(r.Phases),
+			Total:    newPhaseMetricsView(r.Total),
+			Warnings: r.Warnings,
+		})
+	}
+	if len(reports) > 1 {
+		phases, total := core.AggregateReports(reports)
+		view.Aggregate = &aggregateReportView{
+			Projects: len(reports),
+			Phases:   newPhaseMetricsViews(phases),
+			Total:    newPhaseMetricsView(total),
+		}
+	}
+	return view
+}
+
+// phaseArtifacts lists the files generated for a phase
+func phaseArtifacts(phase string) []string {
+	files := []string{}
+	entries, err := os.ReadDir(filepath.Join(".forge", "artifacts", phase))
+	if err != nil {
+		return files
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			files = append(files, e.Name())
+		}
+	}
+	return files
+}
+
+// artifactsByPhase lists generated files for every phase that has any
+func artifactsByPhase() map[string][]string {
+	artifacts := make(map[string][]string)
+	for _, p := range core.AllPhases {
+		if files := phaseArtifacts(p.Name); len(files) > 0 {
+			artifacts[p.Name] = files
+		}
+	}
+	return artifacts
+}
+
+func buildStatusView(cfg *core.ProjectConfig, state *core.ProjectState, detailed bool) statusView {
+	view := statusView{
+		Project:        newProjectView(cfg),
+		CurrentPhase:   newCurrentPhaseView(state),
+		Phases:         newPhaseViews(state),
+		Progress:       newProgressView(state),
+		ArtifactCount:  countArtifacts(),
+		Checkpoint:     newCheckpointView(state),
+		RecentActivity: newActivityViews(state.Activities, 3),
+		NextSteps:      getNextSteps(state),
+	}
+	if detailed {
+		view.Artifacts = artifactsByPhase()
+	}
+	return view
+}
+
+func buildSessionView(cfg *core.ProjectConfig, state *core.ProjectState) sessionView {
+	return sessionView{
+		GeneratedAt:    time.Now(),
+		Project:        newProjectView(cfg),
+		CurrentPhase:   newCurrentPhaseView(state),
+		Phases:         newPhaseViews(state),
+		Progress:       newProgressView(state),
+		Artifacts:      artifactsByPhase(),
+		Checkpoint:     newCheckpointView(state),
+		RecentActivity: newActivityViews(state.Activities, 10),
+		NextSteps:      getNextSteps(state),
+		ResumePrompt:   generateResumePrompt(cfg, state),
+	}
+}
+
+// providerModelsView is one provider in the output of fabric-lite models.
+// Error is set when discovery failed and the models are the configured ones.
+type providerModelsView struct {
+	Provider  string                `json:"provider" yaml:"provider"`
+	Available bool                  `json:"available" yaml:"available"`
+	Error     string                `json:"error,omitempty" yaml:"error,omitempty"`
+	Models    []providers.ModelInfo `json:"models" yaml:"models"`
+}
+
+// doctorCheckView is one result of fabric-lite doctor. Status is ok,
+// warning or error.
+type doctorCheckView struct {
+	Section string `json:"section" yaml:"section"`
+	Name    string `json:"name" yaml:"name"`
+	Status  string `json:"status" yaml:"status"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// doctorView is the output of fabric-lite doctor
+type doctorView struct {
+	Checks   []doctorCheckView `json:"checks" yaml:"checks"`
+	Errors   int               `json:"errors" yaml:"errors"`
+	Warnings int               `json:"warnings" yaml:"warnings"`
+}
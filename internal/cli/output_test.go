@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// fieldNames renders v in format and returns the sorted keys of the resulting object
+func fieldNames(t *testing.T, format string, v interface{}) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := renderOutput(&buf, format, v); err != nil {
+		t.Fatalf("Failed to render %s: %v", format, err)
+	}
+
+	var obj map[string]interface{}
+	var err error
+	if format == outputJSON {
+		err = json.Unmarshal(buf.Bytes(), &obj)
+	} else {
+		err = yaml.Unmarshal(buf.Bytes(), &obj)
+	}
+	if err != nil {
+		t.Fatalf("Failed to parse %s output: %v\n%s", format, err, buf.String())
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func assertFieldNames(t *testing.T, name string, v interface{}, want []string) {
+	t.Helper()
+	sort.Strings(want)
+	for _, format := range []string{outputJSON, outputYAML} {
+		if got := fieldNames(t, format, v); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s %s fields changed:\n  got  %v\n  want %v", name, format, got, want)
+		}
+	}
+}
+
+// seedForgeProject creates a forge project in a temporary working directory
+func seedForgeProject(t *testing.T) (*core.ProjectConfig, *core.ProjectState) {
+	t.Helper()
+	dir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(oldWd) })
+
+	artifactDir := filepath.Join(".forge", "artifacts", "discovery")
+	if err := os.MkdirAll(artifactDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(artifactDir, "requirements.md"), []byte("# Requirements\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := core.NewProjectConfig("demo", "cli")
+	state := core.NewProjectState()
+	state.CurrentPhase = "discovery"
+	state.PhaseStartedAt = time.Now().Add(-time.Hour)
+	state.SetPhaseStatus("discovery", "in_progress")
+	state.AddActivity("Started phase: discovery")
+	return cfg, state
+}
+
+func TestOutputFormat(t *testing.T) {
+	if format, err := outputFormat(&cobra.Command{}); err != nil || format != outputText {
+		t.Errorf("Expected text without an output flag, got %q, %v", format, err)
+	}
+
+	for value, want := range map[string]string{"": outputText, "text": outputText, "json": outputJSON, "yaml": outputYAML} {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("output", value, "")
+		if got, err := outputFormat(cmd); err != nil || got != want {
+			t.Errorf("Expected %q for --output=%q, got %q, %v", want, value, got, err)
+		}
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("output", "xml", "")
+	if _, err := outputFormat(cmd); err == nil || !strings.Contains(err.Error(), "invalid output format") {
+		t.Errorf("Expected invalid output format error, got %v", err)
+	}
+}
+
+func TestRootCmdOutputFlag(t *testing.T) {
+	flag := NewRootCmd("test").PersistentFlags().Lookup("output")
+	if flag == nil {
+		t.Fatal("Expected 'output' flag to be present")
+	}
+	if flag.Shorthand != "o" || flag.DefValue != outputText {
+		t.Errorf("Expected -o with default text, got -%s with default %q", flag.Shorthand, flag.DefValue)
+	}
+}
+
+// The field names below are a stable interface for scripts and dashboards.
+// Renaming or removing one is a breaking change.
+func TestStatusViewFieldNames(t *testing.T) {
+	cfg, state := seedForgeProject(t)
+	view := buildStatusView(cfg, state, true)
+
+	assertFieldNames(t, "status", view, []string{
+		"project", "current_phase", "phases", "progress", "artifact_count",
+		"artifacts", "checkpoint", "recent_activity", "next_steps",
+	})
+	assertFieldNames(t, "project", view.Project, []string{"name", "template", "version"})
+	assertFieldNames(t, "current_phase", view.CurrentPhase, []string{"name", "primary_tool", "started_at", "elapsed_seconds"})
+	assertFieldNames(t, "phase", view.Phases[0], []string{"name", "description", "status", "current", "primary_tool", "depends_on"})
+	assertFieldNames(t, "progress", view.Progress, []string{"completed", "total", "percent"})
+	assertFieldNames(t, "checkpoint", view.Checkpoint, []string{"phase", "passed", "met", "checks"})
+	assertFieldNames(t, "check", view.Checkpoint.Checks[0], []string{"name", "passed", "message"})
+	assertFieldNames(t, "activity", view.RecentActivity[0], []string{"timestamp", "phase", "message"})
+
+	if view.ArtifactCount != 1 || len(view.Artifacts["discovery"]) != 1 {
+		t.Errorf("Expected one discovery artifact, got %d: %v", view.ArtifactCount, view.Artifacts)
+	}
+	if view.CurrentPhase.ElapsedSeconds < 3600 {
+		t.Errorf("Expected at least an hour elapsed, got %d seconds", view.CurrentPhase.ElapsedSeconds)
+	}
+
+	// Without a current phase, current_phase and checkpoint are null rather than omitted
+	state.CurrentPhase = ""
+	idle := buildStatusView(cfg, state, false)
+	assertFieldNames(t, "idle status", idle, []string{
+		"project", "current_phase", "phases", "progress", "artifact_count",
+		"checkpoint", "recent_activity", "next_steps",
+	})
+}
+
+func TestPhaseDetailViewFieldNames(t *testing.T) {
+	_, state := seedForgeProject(t)
+	view := newPhaseDetailView(core.GetPhase("discovery"), state)
+
+	assertFieldNames(t, "phase info", view, []string{
+		"name", "description", "status", "current", "primary_tool", "depends_on",
+		"tool_reason", "criteria", "artifacts", "generated_artifacts",
+	})
+	if !reflect.DeepEqual(view.GeneratedArtifacts, []string{"requirements.md"}) {
+		t.Errorf("Expected generated artifact requirements.md, got %v", view.GeneratedArtifacts)
+	}
+
+	// Outside a project every phase is pending
+	if v := newPhaseView(core.GetPhase("planning"), nil); v.Status != "pending" || v.Current {
+		t.Errorf("Expected pending phase without state, got %+v", v)
+	}
+}
+
+func TestSessionViewFieldNames(t *testing.T) {
+	cfg, state := seedForgeProject(t)
+
+	assertFieldNames(t, "session", buildSessionView(cfg, state), []string{
+		"generated_at", "project", "current_phase", "phases", "progress",
+		"artifacts", "checkpoint", "recent_activity", "next_steps", "resume_prompt",
+	})
+}
+
+func TestHistoryViewFieldNames(t *testing.T) {
+	_, state := seedForgeProject(t)
+	started := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	histories := []core.PhaseHistory{{
+		Phase:       "discovery",
+		StartedAt:   started,
+		CompletedAt: started.Add(2 * time.Hour),
+		Duration:    2 * time.Hour,
+		Notes:       "scope agreed",
+		Attempts:    []core.AttemptRecord{{Attempt: 1, StartedAt: started, Verdict: "passed"}},
+	}}
+
+	view := buildHistoryView(state, histories, core.TimelineFilter{Types: []string{core.EventPhase}}, 0, true)
+	assertFieldNames(t, "history", view, []string{"events", "stats"})
+	assertFieldNames(t, "phase event", view.Events[0], []string{
+		"timestamp", "type", "phase", "message", "duration_seconds", "notes",
+	})
+	assertFieldNames(t, "phase stats", view.Stats[0], []string{
+		"phase", "completions", "attempts", "total_seconds", "average_seconds", "shortest_seconds", "longest_seconds",
+	})
+
+	view = buildHistoryView(state, histories, core.TimelineFilter{Types: []string{core.EventAttempt}}, 0, false)
+	assertFieldNames(t, "history without stats", view, []string{"events"})
+	assertFieldNames(t, "attempt event", view.Events[0], []string{"timestamp", "type", "phase", "message", "verdict"})
+}
+
+func TestNewActivityViewsNewestFirst(t *testing.T) {
+	activities := []core.Activity{{Message: "first"}, {Message: "second"}, {Message: "third"}}
+
+	views := newActivityViews(activities, 2)
+	if len(views) != 2 || views[0].Message != "third" || views[1].Message != "second" {
+		t.Errorf("Expected the two newest activities, got %+v", views)
+	}
+	if views := newActivityViews(nil, 5); views == nil || len(views) != 0 {
+		t.Errorf("Expected an empty, non-nil list, got %#v", views)
+	}
+}
+
+func TestReportViewFieldNames(t *testing.T) {
+	report := &core.ProjectReport{
+		Name: "demo",
+		Dir:  "/work/demo",
+		Phases: []core.PhaseMetrics{
+			{Phase: "discovery", Status: "completed", Completions: 1, ValidatedAttempts: 1, PassedAttempts: 1},
+		},
+		Total: core.PhaseMetrics{Phase: "total", Completions: 1},
+	}
+
+	single := buildReportView([]*core.ProjectReport{report}, time.Now())
+	assertFieldNames(t, "report", single, []string{"generated_at", "projects"})
+	assertFieldNames(t, "project report", single.Projects[0], []string{"name", "dir", "phases", "total"})
+	assertFieldNames(t, "phase metrics", single.Projects[0].Phases[0], []string{
+		"phase", "status", "time_in_phase_seconds", "completions", "reopens", "validation_failures",
+		"rework", "validated_attempts", "passed_attempts", "pass_rate", "tokens", "cost",
+	})
+
+	multi := buildReportView([]*core.ProjectReport{report, report}, time.Now())
+	assertFieldNames(t, "aggregated report", multi, []string{"generated_at", "projects", "aggregate"})
+	assertFieldNames(t, "aggregate", multi.Aggregate, []string{"projects", "phases", "total"})
+}
@@ -1,9 +1,10 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -30,6 +31,8 @@ Phases represent structured stages in the development lifecycle:
 	cmd.AddCommand(newPhaseStartCmd())
 	cmd.AddCommand(newPhaseCompleteCmd())
 	cmd.AddCommand(newPhaseInfoCmd())
+	cmd.AddCommand(newPhaseReopenCmd())
+	cmd.AddCommand(newPhaseResetCmd())
 
 	return cmd
 }
@@ -39,24 +42,31 @@ func newPhaseListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List all phases with status",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			// Show phases without status if not initialized
 			state, err := core.LoadProjectState(".forge/state.yaml")
 			if err != nil {
-				// Show phases without status if not initialized
-				fmt.Println("Development Phases:")
-				fmt.Println()
-				for _, p := range core.AllPhases {
-					fmt.Printf("  ○ %s - %s\n", p.Name, p.Description)
-				}
-				return nil
+				state = nil
+			}
+
+			phases := newPhaseViews(state)
+			if format != outputText {
+				return printOutput(format, phases)
 			}
 
 			fmt.Println("Development Phases:")
 			fmt.Println()
-			for _, p := range core.AllPhases {
-				status := state.GetPhaseStatus(p.Name)
-				icon := getStatusIcon(status, p.Name == state.CurrentPhase)
+			for _, p := range phases {
+				icon := "○"
+				if state != nil {
+					icon = getStatusIcon(p.Status, p.Current)
+				}
 				fmt.Printf("  %s %s - %s\n", icon, p.Name, p.Description)
-				if p.Name == state.CurrentPhase {
+				if p.Current {
 					fmt.Printf("      └─ Current phase (started %s)\n",
 						formatDuration(time.Since(state.PhaseStartedAt)))
 				}
@@ -71,79 +81,68 @@ func newPhaseStartCmd() *cobra.Command {
 	var force bool
 
 	cmd := &cobra.Command{
-		Use:   "start <phase>",
-		Short: "Start a development phase",
-		Args:  cobra.ExactArgs(1),
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			var completions []string
-			for _, p := range core.AllPhases {
-				if strings.HasPrefix(p.Name, toComplete) {
-					completions = append(completions, p.Name)
-				}
-			}
-			return completions, cobra.ShellCompDirectiveNoFileComp
-		},
+		Use:               "start <phase>",
+		Short:             "Start a development phase",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completePhaseName,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			phaseName := args[0]
-
-			// Validate phase name
-			if !core.IsValidPhase(phaseName) {
-				return fmt.Errorf("invalid phase: %s. Valid phases: %s",
-					phaseName, strings.Join(core.PhaseNames(), ", "))
-			}
-
-			state, err := core.LoadProjectState(".forge/state.yaml")
-			if err != nil {
-				return fmt.Errorf("not a forge project (run 'forge init' first)")
-			}
+			return startPhase(cmd.OutOrStdout(), args[0], force)
+		},
+	}
 
-			// Check if already in a phase
-			if state.CurrentPhase != "" && !force {
-				return fmt.Errorf("already in phase '%s'. Use 'forge phase complete' first or --force to switch",
-					state.CurrentPhase)
-			}
+	cmd.Flags().BoolVar(&force, "force", false, "force start even if another phase is active")
 
-			// Check phase order (unless forcing)
-			if !force {
-				if err := validatePhaseOrder(state, phaseName); err != nil {
-					return err
-				}
-			}
+	return cmd
+}
 
-			// Start the phase
-			state.CurrentPhase = phaseName
-			state.PhaseStartedAt = time.Now()
-			state.SetPhaseStatus(phaseName, "in_progress")
-			state.AddActivity(fmt.Sprintf("Started phase: %s", phaseName))
+// startPhase makes phaseName the current phase and describes it on out
+func startPhase(out io.Writer, phaseName string, force bool) error {
+	// Validate phase name
+	if !core.IsValidPhase(phaseName) {
+		return fmt.Errorf("invalid phase: %s. Valid phases: %s",
+			phaseName, strings.Join(core.PhaseNames(), ", "))
+	}
 
-			if err := state.Save(".forge/state.yaml"); err != nil {
-				return fmt.Errorf("failed to save state: %w", err)
-			}
+	err := updateState(func(state *core.ProjectState) error {
+		// Check if already in a phase
+		if state.CurrentPhase != "" && !force {
+			return fmt.Errorf("already in phase '%s'. Use 'forge phase complete' first or --force to switch",
+				state.CurrentPhase)
+		}
 
-			phase := core.GetPhase(phaseName)
-			fmt.Printf("Started phase: %s\n", phaseName)
-			fmt.Printf("Description: %s\n", phase.Description)
-			fmt.Printf("Primary tool: %s\n", phase.PrimaryTool)
-			fmt.Println("\nCheckpoint criteria:")
-			for _, c := range phase.Checkpoint.Criteria {
-				fmt.Printf("  • %s\n", c)
+		// Check phase order (unless forcing)
+		if !force {
+			if err := validatePhaseOrder(state, phaseName); err != nil {
+				return err
 			}
-			fmt.Println("\nRun 'forge run' to start working with the AI assistant.")
+		}
 
-			return nil
-		},
+		// Start the phase
+		state.CurrentPhase = phaseName
+		state.PhaseStartedAt = time.Now()
+		state.SetPhaseStatus(phaseName, "in_progress")
+		state.AddActivity(fmt.Sprintf("Started phase: %s", phaseName))
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	cmd.Flags().BoolVar(&force, "force", false, "force start even if another phase is active")
+	phase := core.GetPhase(phaseName)
+	fmt.Fprintf(out, "Started phase: %s\n", phaseName)
+	fmt.Fprintf(out, "Description: %s\n", phase.Description)
+	fmt.Fprintf(out, "Primary tool: %s\n", phase.PrimaryTool)
+	fmt.Fprintln(out, "\nCheckpoint criteria:")
+	for _, c := range phase.Checkpoint.Criteria {
+		fmt.Fprintf(out, "  • %s\n", c)
+	}
+	fmt.Fprintln(out, "\nRun 'forge run' to start working with the AI assistant.")
 
-	return cmd
+	return nil
 }
 
 func newPhaseCompleteCmd() *cobra.Command {
-	var skipCheck bool
+	var opts phaseCompleteOptions
 
 	cmd := &cobra.Command{
 		Use:   "complete",
@@ -151,84 +150,173 @@ func newPhaseCompleteCmd() *cobra.Command {
 		Long: `Complete the current development phase.
 
 This will run checkpoint validation to ensure phase criteria are met
-before marking the phase as complete.`,
+before marking the phase as complete. File checks run first; when they
+pass, the phase artifacts are reviewed by the AI validator, which judges
+each checkpoint criterion. Use --skip-ai to run the file checks only.
+
+The completion is recorded in .forge/history together with any --notes;
+see 'forge history'.
+
+With integrations.git.auto_commit, the changes under .forge are committed
+using integrations.git.commit_template, tagged forge/<phase> when
+integrations.git.tag is set. The history file is part of the commit; its
+SHA is then added to the history, which the next completion commits.
+Uncommitted changes to other files stop the completion unless
+--force-commit is given; they are never included in the commit.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			state, err := core.LoadProjectState(".forge/state.yaml")
-			if err != nil {
-				return fmt.Errorf("not a forge project (run 'forge init' first)")
-			}
+			return completePhase(cmd.OutOrStdout(), opts)
+		},
+	}
 
-			if state.CurrentPhase == "" {
-				return fmt.Errorf("no active phase to complete")
-			}
+	cmd.Flags().BoolVar(&opts.SkipCheck, "skip-check", false, "skip checkpoint validation")
+	cmd.Flags().BoolVar(&opts.SkipAI, "skip-ai", false, "run file checks only, without AI validation")
+	cmd.Flags().StringVar(&opts.Notes, "notes", "", "notes to record in the phase history")
+	cmd.Flags().BoolVar(&opts.ForceCommit, "force-commit", false, "with integrations.git.auto_commit, commit even when other files have uncommitted changes")
 
-			_ = core.GetPhase(state.CurrentPhase) // validate phase exists
+	return cmd
+}
 
-			// Run checkpoint validation
-			if !skipCheck {
-				fmt.Printf("Running checkpoint validation for phase: %s\n\n", state.CurrentPhase)
+// phaseCompleteOptions are the flags of forge phase complete
+type phaseCompleteOptions struct {
+	SkipCheck   bool
+	SkipAI      bool
+	Notes       string
+	ForceCommit bool
+}
 
-				result := core.ValidateCheckpoint(state.CurrentPhase)
+// completePhase validates and completes the current phase, reporting on out
+func completePhase(out io.Writer, opts phaseCompleteOptions) error {
+	state, err := core.LoadProjectState(".forge/state.yaml")
+	if err != nil {
+		return fmt.Errorf("not a forge project (run 'forge init' first)")
+	}
 
-				for _, check := range result.Checks {
-					icon := "✓"
-					if !check.Passed {
-						icon = "✗"
-					}
-					fmt.Printf("  %s %s\n", icon, check.Name)
-					if check.Message != "" {
-						fmt.Printf("      %s\n", check.Message)
-					}
-				}
+	if state.CurrentPhase == "" {
+		return fmt.Errorf("no active phase to complete")
+	}
 
-				if !result.Passed {
-					fmt.Println("\nCheckpoint validation failed. Address the issues above or use --skip-check.")
-					return fmt.Errorf("checkpoint validation failed")
-				}
+	_ = core.GetPhase(state.CurrentPhase) // validate phase exists
 
-				fmt.Println("\nAll checkpoints passed!")
-			}
+	// Check for unrelated changes before validating, not after completing
+	git, err := projectGit(opts.ForceCommit)
+	if err != nil {
+		return err
+	}
+
+	var attempts []core.AttemptRecord
 
-			// Save phase history
-			historyPath := filepath.Join(".forge", "history", fmt.Sprintf("%s_%s.yaml",
-				state.CurrentPhase, time.Now().Format("20060102_150405")))
-			history := core.PhaseHistory{
+	// Run checkpoint validation
+	if !opts.SkipCheck {
+		fmt.Fprintf(out, "Running checkpoint validation for phase: %s\n\n", state.CurrentPhase)
+
+		var validator *core.PhaseValidator
+		if !opts.SkipAI {
+			validator = createValidator()
+		}
+
+		startedAt := time.Now()
+		verdict, err := core.ValidatePhase(state.CurrentPhase, validator)
+		if err != nil {
+			return fmt.Errorf("validate phase: %w", err)
+		}
+
+		printVerdict(out, verdict)
+
+		if !verdict.Passed {
+			// Record the failed attempt so forge history and forge report see the rework
+			failedAt := time.Now()
+			savePhaseHistory(out, core.PhaseHistory{
 				Phase:       state.CurrentPhase,
 				StartedAt:   state.PhaseStartedAt,
-				CompletedAt: time.Now(),
-				Duration:    time.Since(state.PhaseStartedAt),
-			}
-			if err := history.Save(historyPath); err != nil {
-				fmt.Printf("Warning: failed to save history: %v\n", err)
-			}
+				CompletedAt: failedAt,
+				Duration:    failedAt.Sub(state.PhaseStartedAt),
+				Outcome:     core.HistoryFailed,
+				Notes:       opts.Notes,
+				Attempts: []core.AttemptRecord{{
+					Attempt:     1,
+					StartedAt:   startedAt,
+					CompletedAt: failedAt,
+					Feedback:    verdict.Feedback,
+					Verdict:     "failed",
+					Criteria:    verdict.Criteria,
+				}},
+			})
+
+			fmt.Fprintln(out, "\nCheckpoint validation failed. Address the issues above or use --skip-check.")
+			return fmt.Errorf("checkpoint validation failed")
+		}
 
-			// Update state
-			state.SetPhaseStatus(state.CurrentPhase, "completed")
-			state.AddActivity(fmt.Sprintf("Completed phase: %s", state.CurrentPhase))
-			completedPhase := state.CurrentPhase
-			state.CurrentPhase = ""
-			state.PhaseStartedAt = time.Time{}
+		fmt.Fprintln(out, "\nAll checkpoints passed!")
+		attempts = append(attempts, core.AttemptRecord{
+			Attempt:     1,
+			StartedAt:   startedAt,
+			CompletedAt: time.Now(),
+			Feedback:    verdict.Feedback,
+			Verdict:     "passed",
+			Criteria:    verdict.Criteria,
+		})
+	}
 
-			if err := state.Save(".forge/state.yaml"); err != nil {
-				return fmt.Errorf("failed to save state: %w", err)
-			}
+	graph, err := loadPhaseGraph()
+	if err != nil {
+		return err
+	}
 
-			fmt.Printf("\nPhase '%s' completed successfully!\n", completedPhase)
+	// Update state; the phase may have changed while validation ran
+	completedPhase := state.CurrentPhase
+	var startedAt time.Time
+	var ready []string
+	err = updateState(func(state *core.ProjectState) error {
+		if state.CurrentPhase != completedPhase {
+			return fmt.Errorf("active phase changed to '%s' during validation", state.CurrentPhase)
+		}
+		startedAt = state.PhaseStartedAt
+		state.SetPhaseStatus(completedPhase, "completed")
+		state.AddActivity(fmt.Sprintf("Completed phase: %s", completedPhase))
+		state.CurrentPhase = ""
+		state.PhaseStartedAt = time.Time{}
+		ready = graph.Ready(state)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-			// Suggest next phase
-			if next := core.NextPhase(completedPhase); next != "" {
-				fmt.Printf("\nNext: forge phase start %s\n", next)
-			} else {
-				fmt.Println("\nAll phases complete! Project ready for release.")
-			}
+	// Save phase history before committing so it is part of the commit
+	completedAt := time.Now()
+	history := core.PhaseHistory{
+		Phase:       completedPhase,
+		StartedAt:   startedAt,
+		CompletedAt: completedAt,
+		Duration:    completedAt.Sub(startedAt),
+		Outcome:     core.HistoryCompleted,
+		Notes:       opts.Notes,
+		Attempts:    attempts,
+	}
+	historyPath := savePhaseHistory(out, history)
+	commit := commitPhase(git, completedPhase, opts.Notes)
+	if commit != "" && historyPath != "" {
+		// A commit cannot contain its own SHA, so it is recorded in the
+		// working copy and committed with the next phase
+		history.Commit = commit
+		if err := history.Save(historyPath); err != nil {
+			fmt.Fprintf(out, "Warning: failed to record commit in history: %v\n", err)
+		}
+	}
 
-			return nil
-		},
+	fmt.Fprintf(out, "\nPhase '%s' completed successfully!\n", completedPhase)
+	if commit != "" {
+		fmt.Fprintf(out, "Committed forge changes: %s\n", core.ShortSHA(commit))
 	}
 
-	cmd.Flags().BoolVar(&skipCheck, "skip-check", false, "skip checkpoint validation")
+	// Suggest a phase whose dependencies are now all completed
+	if len(ready) > 0 {
+		fmt.Fprintf(out, "\nNext: forge phase start %s\n", ready[0])
+	} else {
+		fmt.Fprintln(out, "\nAll phases complete! Project ready for release.")
+	}
 
-	return cmd
+	return nil
 }
 
 func newPhaseInfoCmd() *cobra.Command {
@@ -237,13 +325,21 @@ func newPhaseInfoCmd() *cobra.Command {
 		Short: "Show detailed phase information",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
 			var phaseName string
+			state, err := core.LoadProjectState(".forge/state.yaml")
+			if err != nil {
+				state = nil
+			}
 
 			if len(args) > 0 {
 				phaseName = args[0]
 			} else {
-				state, err := core.LoadProjectState(".forge/state.yaml")
-				if err != nil || state.CurrentPhase == "" {
+				if state == nil || state.CurrentPhase == "" {
 					return fmt.Errorf("specify a phase or start one with 'forge phase start'")
 				}
 				phaseName = state.CurrentPhase
@@ -253,27 +349,31 @@ func newPhaseInfoCmd() *cobra.Command {
 				return fmt.Errorf("invalid phase: %s", phaseName)
 			}
 
-			phase := core.GetPhase(phaseName)
+			info := newPhaseDetailView(core.GetPhase(phaseName), state)
+			if format != outputText {
+				return printOutput(format, info)
+			}
 
-			fmt.Printf("Phase: %s\n", phase.Name)
-			fmt.Printf("Description: %s\n", phase.Description)
-			fmt.Printf("Primary Tool: %s\n", phase.PrimaryTool)
-			fmt.Printf("Tool Reason: %s\n", phase.ToolReason)
+			fmt.Printf("Phase: %s\n", info.Name)
+			fmt.Printf("Description: %s\n", info.Description)
+			fmt.Printf("Primary Tool: %s\n", info.PrimaryTool)
+			fmt.Printf("Tool Reason: %s\n", info.ToolReason)
+			if len(info.DependsOn) > 0 {
+				fmt.Printf("Depends On: %s\n", strings.Join(info.DependsOn, ", "))
+			}
 			fmt.Println("\nCheckpoint Criteria:")
-			for _, c := range phase.Checkpoint.Criteria {
+			for _, c := range info.Criteria {
 				fmt.Printf("  • %s\n", c)
 			}
 			fmt.Println("\nArtifacts:")
-			for _, a := range phase.Artifacts {
+			for _, a := range info.Artifacts {
 				fmt.Printf("  • %s\n", a)
 			}
 
-			// Check artifact directory
-			artifactDir := filepath.Join(".forge", "artifacts", phaseName)
-			if entries, err := os.ReadDir(artifactDir); err == nil && len(entries) > 0 {
+			if len(info.GeneratedArtifacts) > 0 {
 				fmt.Println("\nGenerated Artifacts:")
-				for _, e := range entries {
-					fmt.Printf("  • %s\n", e.Name())
+				for _, name := range info.GeneratedArtifacts {
+					fmt.Printf("  • %s\n", name)
 				}
 			}
 
@@ -282,27 +382,261 @@ func newPhaseInfoCmd() *cobra.Command {
 	}
 }
 
-func validatePhaseOrder(state *core.ProjectState, targetPhase string) error {
-	// Allow starting from discovery without prerequisites
-	if targetPhase == "discovery" {
-		return nil
+func newPhaseReopenCmd() *cobra.Command {
+	var opts core.RollbackOptions
+
+	cmd := &cobra.Command{
+		Use:   "reopen <phase>",
+		Short: "Reopen a completed phase",
+		Long: `Make a completed or failed phase the current phase again.
+
+The phase keeps its artifacts. Phases that depend on it stay completed unless
+--downstream is given, which returns them to pending and archives their
+artifacts in .forge/archive/<phase>/<timestamp>.`,
+		Example: `  # Revise the design after implementation found a problem
+  forge phase reopen design --downstream`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completePhaseName,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return rollbackPhase(args[0], opts, core.ReopenPhase)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Downstream, "downstream", false, "also reset the phases that depend on this one")
+	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "reopen even while another phase is in progress, returning it to pending")
+
+	return cmd
+}
+
+func newPhaseResetCmd() *cobra.Command {
+	var opts core.RollbackOptions
+
+	cmd := &cobra.Command{
+		Use:   "reset <phase>",
+		Short: "Reset a phase to pending",
+		Long: `Return a phase to pending so it is started (or run by 'forge auto') from scratch.
+
+Its artifacts are moved to .forge/archive/<phase>/<timestamp>. With
+--downstream, the phases that depend on it are reset the same way.`,
+		Example: `  # Redo planning and everything after it
+  forge phase reset planning --downstream`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completePhaseName,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return rollbackPhase(args[0], opts, core.ResetPhase)
+		},
 	}
 
-	// Check if previous phases are completed
+	cmd.Flags().BoolVar(&opts.Downstream, "downstream", false, "also reset the phases that depend on this one")
+
+	return cmd
+}
+
+// rollbackPhase applies a reopen or reset to the project state and archives
+// the artifacts of the phases it returned to pending
+func rollbackPhase(phaseName string, opts core.RollbackOptions,
+	rollback func(*core.ProjectState, *core.PhaseGraph, string, core.RollbackOptions, time.Time) (*core.RollbackResult, error)) error {
+	if !core.IsValidPhase(phaseName) {
+		return fmt.Errorf("invalid phase: %s. Valid phases: %s",
+			phaseName, strings.Join(core.PhaseNames(), ", "))
+	}
+
+	graph, err := loadPhaseGraph()
+	if err != nil {
+		return err
+	}
+
+	var result *core.RollbackResult
+	now := time.Now()
+	err = updateState(func(state *core.ProjectState) error {
+		var err error
+		result, err = rollback(state, graph, phaseName, opts, now)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Artifacts are only moved once the rolled back state is saved
+	archived, archiveErr := result.ArchiveArtifacts(now)
+	if len(archived) > 0 {
+		err := updateState(func(state *core.ProjectState) error {
+			result.LogArchives(state, archived, now)
+			return nil
+		})
+		if err != nil {
+			fmt.Printf("Warning: failed to log archived artifacts: %v\n", err)
+		}
+	}
+
+	if len(result.Reset) > 0 && result.Reset[0] == phaseName {
+		fmt.Printf("Reset phase: %s\n", phaseName)
+	} else {
+		fmt.Printf("Reopened phase: %s\n", phaseName)
+	}
+	if result.Displaced != "" {
+		fmt.Printf("Returned unfinished phase %s to pending\n", result.Displaced)
+	}
+	for _, p := range result.Reset {
+		if p != phaseName {
+			fmt.Printf("Reset dependent phase: %s\n", p)
+		}
+	}
+	for _, p := range result.Archive {
+		if dir, ok := archived[p]; ok {
+			fmt.Printf("Archived %s artifacts to %s\n", p, dir)
+		}
+	}
+	if archiveErr != nil {
+		return fmt.Errorf("archive artifacts: %w", archiveErr)
+	}
+	if len(result.Stale) > 0 {
+		fmt.Printf("\nStill completed but depend on %s: %s\n", phaseName, strings.Join(result.Stale, ", "))
+		fmt.Println("Use --downstream to reset them as well.")
+	}
+
+	return nil
+}
+
+// completePhaseName completes the phase argument of phase subcommands
+func completePhaseName(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []string
 	for _, p := range core.AllPhases {
-		if p.Name == targetPhase {
-			break
+		if strings.HasPrefix(p.Name, toComplete) {
+			completions = append(completions, p.Name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// projectGit returns the git integration of the project in the working
+// directory, or nil when auto-commit is off. It fails when unrelated files
+// have uncommitted changes, unless force is set.
+func projectGit(force bool) (*core.GitIntegration, error) {
+	cfg, err := core.LoadProjectConfig(".forge/config.yaml")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
 		}
-		status := state.GetPhaseStatus(p.Name)
+		return nil, err
+	}
+	git := core.NewGitIntegration(cfg, ".", force)
+	if git == nil {
+		return nil, nil
+	}
+	if err := git.CheckClean(); err != nil {
+		if errors.Is(err, core.ErrDirtyWorktree) {
+			return nil, fmt.Errorf("%w\nCommit or stash them, or use --force-commit", err)
+		}
+		return nil, err
+	}
+	return git, nil
+}
+
+// commitPhase commits .forge for a completed phase and returns the commit
+// SHA. Failures only warn: the phase is already completed.
+func commitPhase(git *core.GitIntegration, phase, notes string) string {
+	if git == nil {
+		return ""
+	}
+	sha, err := git.CommitPhase(phase, notes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to commit forge changes: %v\n", err)
+	}
+	return sha
+}
+
+// savePhaseHistory writes a history file to .forge/history and returns its
+// path, warning and returning "" on failure
+func savePhaseHistory(out io.Writer, history core.PhaseHistory) string {
+	statePath := ".forge/state.yaml"
+	if err := os.MkdirAll(core.HistoryDir(statePath), 0755); err != nil {
+		fmt.Fprintf(out, "Warning: failed to create history directory: %v\n", err)
+		return ""
+	}
+	path := core.NewHistoryPath(statePath, history.Phase, history.CompletedAt)
+	if err := history.Save(path); err != nil {
+		fmt.Fprintf(out, "Warning: failed to save history: %v\n", err)
+		return ""
+	}
+	return path
+}
+
+// updateState applies fn to the project state under the state lock
+func updateState(fn func(*core.ProjectState) error) error {
+	_, err := core.UpdateProjectState(".forge/state.yaml", fn)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("not a forge project (run 'forge init' first)")
+	}
+	return err
+}
+
+// loadPhaseGraph returns the phase dependencies from the project config,
+// falling back to the defaults
+func loadPhaseGraph() (*core.PhaseGraph, error) {
+	cfg, err := core.LoadProjectConfig(".forge/config.yaml")
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return cfg.PhaseGraph()
+}
+
+func validatePhaseOrder(state *core.ProjectState, targetPhase string) error {
+	graph, err := loadPhaseGraph()
+	if err != nil {
+		return err
+	}
+
+	// Check if the phases this one depends on are completed
+	for _, dep := range graph.DependsOn(targetPhase) {
+		status := state.GetPhaseStatus(dep)
 		if status != "completed" {
 			return fmt.Errorf("phase '%s' must be completed before starting '%s'. Use --force to override",
-				p.Name, targetPhase)
+				dep, targetPhase)
 		}
 	}
 
 	return nil
 }
 
+// printVerdict shows the checkpoint checks and per-criterion AI results
+func printVerdict(out io.Writer, verdict *core.PhaseVerdict) {
+	for _, check := range verdict.Checkpoint.Checks {
+		fmt.Fprintf(out, "  %s %s\n", checkIcon(check.Passed), check.Name)
+		if check.Message != "" {
+			fmt.Fprintf(out, "      %s\n", check.Message)
+		}
+	}
+
+	if verdict.Warning != "" {
+		fmt.Fprintf(out, "\nWarning: %s\n", verdict.Warning)
+	}
+	if verdict.AI == nil {
+		return
+	}
+
+	fmt.Fprintln(out, "\nAI validation:")
+	for _, c := range verdict.Criteria {
+		fmt.Fprintf(out, "  %s %s\n", checkIcon(c.Passed), c.Criterion)
+		if c.Evidence != "" {
+			fmt.Fprintf(out, "      %s\n", c.Evidence)
+		}
+	}
+	if verdict.AI.Feedback != "" {
+		fmt.Fprintf(out, "\n  %s\n", verdict.AI.Feedback)
+	}
+}
+
+func checkIcon(passed bool) string {
+	if passed {
+		return "✓"
+	}
+	return "✗"
+}
+
 func getStatusIcon(status string, current bool) string {
 	if current {
 		return "▶"
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func newReportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "report [project-dir...]",
+		Short: "Report phase metrics and velocity",
+		Long: `Report how much effort each phase took, from .forge/state.yaml, the phase
+history files in .forge/history and the recorded validation attempts.
+
+For every phase the report shows:
+  - time in phase (completed runs plus the elapsed time of the active phase)
+  - completions and reopens (completions after the first)
+  - validation failures and the checkpoint pass rate of validated attempts
+  - tokens used by phase tools and their cost
+
+CLI tools do not report usage, so forge auto estimates tokens from the text
+exchanged with them. Set advanced.cost_per_1k_tokens in .forge/config.yaml
+to price them.
+
+The report is markdown by default; use --output json or yaml for data.
+With several project directories, an aggregate across all of them is added.`,
+		Example: `  # Report for the current project
+  forge report > REPORT.md
+
+  # Aggregate several projects as JSON
+  forge report ~/projects/api ~/projects/cli -o json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				args = []string{"."}
+			}
+			return showReport(format, args)
+		},
+	}
+}
+
+func showReport(format string, dirs []string) error {
+	now := time.Now()
+
+	var reports []*core.ProjectReport
+	for _, dir := range dirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		report, err := core.BuildProjectReport(abs, now)
+		if err != nil {
+			return err
+		}
+		for _, w := range report.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", report.Name, w)
+		}
+		reports = append(reports, report)
+	}
+
+	view := buildReportView(reports, now)
+	if format != outputText {
+		return printOutput(format, view)
+	}
+	writeReportMarkdown(os.Stdout, view)
+	return nil
+}
+
+// writeReportMarkdown renders the report as markdown tables
+func writeReportMarkdown(w io.Writer, view reportView) {
+	fmt.Fprintln(w, "# Forge Report")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "**Generated:** %s\n", view.GeneratedAt.Format("2006-01-02 15:04"))
+
+	for _, p := range view.Projects {
+		fmt.Fprintf(w, "\n## %s\n\n", p.Name)
+		fmt.Fprintf(w, "**Directory:** `%s`\n\n", p.Dir)
+		writeMetricsTable(w, p.Phases, p.Total, true)
+		for _, warning := range p.Warnings {
+			fmt.Fprintf(w, "\n> Warning: %s\n", warning)
+		}
+	}
+
+	if agg := view.Aggregate; agg != nil {
+		fmt.Fprintf(w, "\n## All Projects (%d)\n\n", agg.Projects)
+		writeMetricsTable(w, agg.Phases, agg.Total, false)
+	}
+}
+
+func writeMetricsTable(w io.Writer, phases []phaseMetricsView, total phaseMetricsView, withStatus bool) {
+	headers := []string{"Phase", "Time in phase", "Completions", "Reopens", "Failed validations", "Pass rate", "Tokens", "Cost"}
+	if withStatus {
+		headers = append([]string{headers[0], "Status"}, headers[1:]...)
+	}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(headers, " | "))
+	fmt.Fprintf(w, "|%s\n", strings.Repeat("---|", len(headers)))
+
+	row := func(m phaseMetricsView, name, status string) {
+		cells := []string{
+			name,
+			formatSpan(seconds(m.TimeInPhaseSeconds)),
+			fmt.Sprint(m.Completions),
+			fmt.Sprint(m.Reopens),
+			fmt.Sprint(m.ValidationFailures),
+			formatPassRate(m.PassRate),
+			fmt.Sprint(m.Tokens),
+			fmt.Sprintf("$%.2f", m.Cost),
+		}
+		if withStatus {
+			cells = append([]string{cells[0], status}, cells[1:]...)
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+	}
+
+	for _, m := range phases {
+		row(m, m.Phase, m.Status)
+	}
+	row(total, "**Total**", "")
+}
+
+func formatPassRate(rate *float64) string {
+	if rate == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", *rate*100)
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+)
+
+func TestWriteReportMarkdown(t *testing.T) {
+	report := &core.ProjectReport{
+		Name: "demo",
+		Dir:  "/work/demo",
+		Phases: []core.PhaseMetrics{
+			{Phase: "discovery", Status: "completed", TimeInPhase: 2*time.Hour + 5*time.Minute, Completions: 2,
+				Reopens: 1, ValidationFailures: 1, ValidatedAttempts: 2, PassedAttempts: 1, Tokens: 4000, Cost: 2},
+			{Phase: "planning", Status: "pending"},
+		},
+		Total:    core.PhaseMetrics{Phase: "total", TimeInPhase: 2*time.Hour + 5*time.Minute, Completions: 2, Tokens: 4000, Cost: 2},
+		Warnings: []string{"history: broken.yaml"},
+	}
+
+	var buf bytes.Buffer
+	writeReportMarkdown(&buf, buildReportView([]*core.ProjectReport{report, report}, time.Now()))
+	out := buf.String()
+
+	for _, want := range []string{
+		"# Forge Report",
+		"## demo",
+		"| Phase | Status | Time in phase | Completions | Reopens | Failed validations | Pass rate | Tokens | Cost |",
+		"| discovery | completed | 2h 5m | 2 | 1 | 1 | 50% | 4000 | $2.00 |",
+		"| planning | pending | 0s | 0 | 0 | 0 | - | 0 | $0.00 |",
+		"> Warning: history: broken.yaml",
+		"## All Projects (2)",
+		"| discovery | 4h 10m | 4 | 2 | 2 | 50% | 8000 | $4.00 |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+}
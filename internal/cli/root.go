@@ -7,6 +7,7 @@ import (
 
 	"github.com/rice0649/fabric-lite/internal/core"
 	"github.com/rice0649/fabric-lite/internal/executor"
+	"github.com/rice0649/fabric-lite/internal/providers"
 	"github.com/rice0649/fabric-lite/internal/tools"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -29,6 +30,7 @@ func NewRootCmd(version string) *cobra.Command {
 	rootCmd.PersistentFlags().StringP("provider", "v", "", "AI provider to use (openai, anthropic, ollama)")
 	rootCmd.PersistentFlags().BoolP("stream", "s", false, "Enable streaming responses")
 	rootCmd.PersistentFlags().BoolP("verbose", "V", false, "Verbose output")
+	rootCmd.PersistentFlags().StringP("output", "o", outputText, "Output format for forge commands (text, json, yaml)")
 
 	viper.BindPFlag("pattern", rootCmd.PersistentFlags().Lookup("pattern"))
 	viper.BindPFlag("model", rootCmd.PersistentFlags().Lookup("model"))
@@ -41,21 +43,28 @@ func NewRootCmd(version string) *cobra.Command {
 	rootCmd.AddCommand(newListCmd())
 	rootCmd.AddCommand(newConfigCmd())
 	rootCmd.AddCommand(newVersionCmd(version))
+	rootCmd.AddCommand(newServeCmd())
+	rootCmd.AddCommand(newMCPCmd(version))
+	rootCmd.AddCommand(newModelsCmd())
+	rootCmd.AddCommand(newDoctorCmd())
 
 	// Add forge workflow commands
 	rootCmd.AddCommand(newInitCmd())
 	rootCmd.AddCommand(newPhaseCmd())
 	rootCmd.AddCommand(newStatusCmd())
+	rootCmd.AddCommand(newHistoryCmd())
+	rootCmd.AddCommand(newReportCmd())
 	rootCmd.AddCommand(newSessionCmd())
 	rootCmd.AddCommand(newAutoCmd())
+	rootCmd.AddCommand(newApproveCmd())
+	rootCmd.AddCommand(newStateCmd())
 
 	return rootCmd
 }
 
 func initConfig() error {
 	cm := core.NewConfigManager("")
-	cfg, err := cm.Load()
-	if err != nil {
+	if _, err := cm.Load(); err != nil {
 		// If config file not found, it's not an error, we'll use defaults.
 		// Other errors should be returned.
 		if _, ok := err.(*os.PathError); !ok && err.Error() != "config file not found" {
@@ -72,6 +81,20 @@ func initConfig() error {
 	defaultModel := loadedConfig.Tools.Codex.Model // Use Codex's model as default
 	viper.SetDefault("model", defaultModel)
 
+	// Codex runs through a configured provider rather than a binary
+	tools.RegisterConfiguredTools(loadedConfig.Tools.Codex, core.GetDefaultProviderManager())
+
+	// Tools declared in tools.yaml add to or override the built-in tools
+	for _, path := range core.ToolDefinitionPaths() {
+		defs, err := core.LoadToolDefinitions(path)
+		if err != nil {
+			return fmt.Errorf("failed to load tools: %w", err)
+		}
+		if err := tools.RegisterDefinitions(defs); err != nil {
+			return fmt.Errorf("invalid tools in %s: %w", path, err)
+		}
+	}
+
 	viper.AutomaticEnv()
 	viper.SetEnvPrefix("FABRIC_LITE")
 
@@ -82,9 +105,38 @@ func newRunCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "run [input_file|tool_name]",
 		Short: "Execute a pattern against input or run a tool",
-		Long:  `Execute a fabric-lite pattern against input text or run a specific tool.`,
-		Args:  cobra.MaximumNArgs(2),
-		RunE:  runCommand,
+		Long: `Execute a fabric-lite pattern against input text or run a specific tool.
+
+Instead of a file or stdin, input can be collected from the local git
+repository: --git-diff (uncommitted changes, or --git-diff=<range>),
+--staged and --git-log <range>. Combined sources become sections of one
+input. --git-path limits them to paths and --git-max-bytes bounds the size.
+For tools, the git content is passed as context.
+
+Patterns may use {{name}} placeholders in system.md and user.md; --var
+sets their values. {{input}} in user.md places the input itself instead
+of appending it.
+
+Sampling flags (--temperature, --top-p, --top-k, --stop, --seed,
+--presence-penalty, --frequency-penalty) override the defaults in the
+pattern's pattern.yaml, which uses the API names (e.g. "temperature: 0.2",
+"top_p", "stop"). Providers ignore parameters their API lacks.`,
+		Example: `  # Explain uncommitted changes
+  fabric-lite run --pattern explain_code --git-diff
+
+  # Explain a branch, ignoring vendored code
+  fabric-lite run --pattern explain_code --git-diff=main...HEAD --git-path . --git-path ':(exclude)vendor'
+
+  # Draft a changelog since the last release
+  fabric-lite run --pattern deployment/create_changelog --git-log v1.2.0..HEAD
+
+  # Fill in pattern variables
+  fabric-lite run --pattern explain_code --var language=Go main.go
+
+  # Reproducible, focused output
+  fabric-lite run --pattern summarize --temperature 0 --seed 42 notes.md`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: runCommand,
 	}
 
 	cmd.Flags().String("pattern", "", "Pattern name (required)")
@@ -93,6 +145,14 @@ func newRunCmd() *cobra.Command {
 	cmd.Flags().String("provider", "", "Provider to use")
 	cmd.Flags().Bool("stream", false, "Stream response")
 	cmd.Flags().Bool("save-session", false, "Save conversation to session")
+	cmd.Flags().StringToString("var", nil, "Value for a {{name}} pattern variable, e.g. --var language=Go (repeatable)")
+	cmd.Flags().String("git-diff", "", "Use the diff against a commit or range as input (HEAD when no value is given)")
+	cmd.Flags().Lookup("git-diff").NoOptDefVal = "HEAD"
+	cmd.Flags().Bool("staged", false, "Use staged changes as input")
+	cmd.Flags().String("git-log", "", "Use the commit log of a range as input, e.g. v1.2.0..HEAD")
+	cmd.Flags().StringSlice("git-path", nil, "Limit git input to these paths")
+	cmd.Flags().Int("git-max-bytes", core.DefaultGitInputLimit, "Truncate git input to this many bytes (0 for no limit)")
+	addSamplingFlags(cmd)
 
 	return cmd
 }
@@ -127,9 +187,15 @@ func executeTool(cmd *cobra.Command, args []string, tool tools.Tool) error {
 		return fmt.Errorf("prompt is required for tool execution (use -p \"prompt\")")
 	}
 
+	gitContent, err := gitInput(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Create execution context
 	executionContext := tools.ExecutionContext{
 		Prompt:  prompt,
+		Context: gitContent,
 		Args:    args[1:], // Skip tool name
 		Env:     make(map[string]string),
 		WorkDir: ".",
@@ -157,8 +223,15 @@ func executePattern(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get input
-	var input string
-	if len(args) > 0 {
+	input, err := gitInput(cmd)
+	if err != nil {
+		return err
+	}
+	if input != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("use either an input file or git input, not both")
+		}
+	} else if len(args) > 0 {
 		data, err := os.ReadFile(args[0])
 		if err != nil {
 			return fmt.Errorf("failed to read input file: %w", err)
@@ -208,6 +281,13 @@ func executePattern(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get provider %s: %w", providerName, err)
 	}
 	patternExecutor.LoadProviderDirect(providerName, provider)
+	vars, _ := cmd.Flags().GetStringToString("var")
+	patternExecutor.SetVariables(vars)
+	sampling, err := samplingFlags(cmd)
+	if err != nil {
+		return err
+	}
+	patternExecutor.SetSampling(sampling)
 
 	// Get model from flags
 	model := cmd.Flag("model").Value.String()
@@ -236,7 +316,7 @@ func executePattern(cmd *cobra.Command, args []string) error {
 	}
 
 	// Non-streaming execution
-	response, err := patternExecutor.Execute(cmd.Context(), patternName, input, providerName)
+	response, err := patternExecutor.ExecuteWithOptions(cmd.Context(), patternName, input, providerName, model, false)
 	if err != nil {
 		return fmt.Errorf("failed to execute pattern: %w", err)
 	}
@@ -245,6 +325,72 @@ func executePattern(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// addSamplingFlags adds the flags read by samplingFlags
+func addSamplingFlags(cmd *cobra.Command) {
+	cmd.Flags().Float64("temperature", 0, "Sampling temperature (0-2)")
+	cmd.Flags().Float64("top-p", 0, "Nucleus sampling probability mass (0-1)")
+	cmd.Flags().Int("top-k", 0, "Sample from the k most likely tokens")
+	cmd.Flags().StringArray("stop", nil, "Stop sequence (repeatable)")
+	cmd.Flags().Int("seed", 0, "Seed for reproducible sampling")
+	cmd.Flags().Float64("presence-penalty", 0, "Penalty for tokens already present (-2 to 2)")
+	cmd.Flags().Float64("frequency-penalty", 0, "Penalty proportional to token frequency (-2 to 2)")
+}
+
+// samplingFlags returns the sampling parameters set on the command line;
+// flags that weren't given leave the pattern's defaults
+func samplingFlags(cmd *cobra.Command) (providers.Sampling, error) {
+	var s providers.Sampling
+	flags := cmd.Flags()
+	float := func(name string) *float64 {
+		if !flags.Changed(name) {
+			return nil
+		}
+		v, _ := flags.GetFloat64(name)
+		return &v
+	}
+	integer := func(name string) *int {
+		if !flags.Changed(name) {
+			return nil
+		}
+		v, _ := flags.GetInt(name)
+		return &v
+	}
+
+	s.Temperature = float("temperature")
+	s.TopP = float("top-p")
+	s.TopK = integer("top-k")
+	if flags.Changed("stop") {
+		s.Stop, _ = flags.GetStringArray("stop")
+	}
+	s.Seed = integer("seed")
+	s.PresencePenalty = float("presence-penalty")
+	s.FrequencyPenalty = float("frequency-penalty")
+	return s, s.Validate()
+}
+
+// gitInput collects the git content selected by the run flags. It returns
+// "" when no git source is selected and fails when a selected source is empty.
+func gitInput(cmd *cobra.Command) (string, error) {
+	src := core.GitSource{}
+	src.Diff, _ = cmd.Flags().GetString("git-diff")
+	src.Staged, _ = cmd.Flags().GetBool("staged")
+	src.Log, _ = cmd.Flags().GetString("git-log")
+	src.Paths, _ = cmd.Flags().GetStringSlice("git-path")
+	src.MaxBytes, _ = cmd.Flags().GetInt("git-max-bytes")
+	if src.IsZero() {
+		return "", nil
+	}
+
+	content, err := src.Collect(".")
+	if err != nil {
+		return "", fmt.Errorf("collect git input: %w", err)
+	}
+	if content == "" {
+		return "", fmt.Errorf("no git changes found for the selected input")
+	}
+	return content, nil
+}
+
 func newListCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "list",
@@ -284,10 +430,9 @@ func showConfig(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Patterns Dir: %s\n", executor.NewPatternExecutor().GetPatternsDir())
 
 	// Show loaded providers
-	config := core.GetDefaultConfig()
 	fmt.Println("  Available Providers:")
-	for _, provider := range config.Providers {
-		fmt.Printf("    - %s (%s)\n", provider.Name, provider.Type)
+	for _, name := range core.GetDefaultProviderManager().ListAvailable() {
+		fmt.Printf("    - %s\n", name)
 	}
 
 	return nil
@@ -1,6 +1,12 @@
 package cli
 
 import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -81,3 +87,160 @@ func TestRootCmdVersion(t *testing.T) {
 		t.Errorf("Expected version to be set correctly even with execution error")
 	}
 }
+
+func TestSamplingFlags(t *testing.T) {
+	cmd := newRunCmd()
+	if err := cmd.ParseFlags([]string{"--temperature", "0", "--top-k", "40", "--stop", "END", "--stop", "a,b"}); err != nil {
+		t.Fatal(err)
+	}
+	s, err := samplingFlags(cmd)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if s.Temperature == nil || *s.Temperature != 0 || *s.TopK != 40 || len(s.Stop) != 2 || s.Stop[1] != "a,b" {
+		t.Errorf("Expected the given flags, got %+v", s)
+	}
+	if s.TopP != nil || s.Seed != nil {
+		t.Errorf("Expected flags that weren't given to stay unset, got %+v", s)
+	}
+
+	cmd = newRunCmd()
+	cmd.ParseFlags([]string{"--top-p", "1.5"})
+	if _, err := samplingFlags(cmd); err == nil {
+		t.Error("Expected an error for an out-of-range top_p")
+	}
+}
+
+// fakePluginScript answers the plugin protocol with a fixed reply
+const fakePluginScript = `#!/bin/sh
+read -r request
+case "$request" in
+*'"describe"'*) echo '{"type": "describe", "protocol": 1, "name": "my-llm", "models": ["large"]}' ;;
+*) echo '{"type": "chunk", "content": "hello from my-llm"}'; echo '{"type": "done", "model": "large"}' ;;
+esac
+`
+
+// setupFabricHome points HOME at a temporary directory, changes into a
+// second one and returns the fabric-lite config directory
+func setupFabricHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configDir := filepath.Join(home, ".config", "fabric-lite")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	return configDir
+}
+
+// writeRunInput writes an echo pattern and a notes.txt input to the
+// working directory
+func writeRunInput(t *testing.T) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join("patterns", "echo"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("patterns", "echo", "system.md"), []byte("Repeat the input."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("notes.txt", []byte("some notes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// captureStdout returns what fn writes to os.Stdout
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	fnErr := fn()
+	os.Stdout = stdout
+	w.Close()
+	return <-done, fnErr
+}
+
+func TestRunWithProviderFromProvidersFile(t *testing.T) {
+	configDir := setupFabricHome(t)
+	plugin := filepath.Join(configDir, "my-llm-plugin")
+	if err := os.WriteFile(plugin, []byte(fakePluginScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	providersYAML := "providers:\n  - name: my-llm\n    type: plugin\n    config:\n      executable: " + plugin + "\n"
+	if err := os.WriteFile(filepath.Join(configDir, "providers.yaml"), []byte(providersYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeRunInput(t)
+
+	cmd := NewRootCmd("test")
+	cmd.SetArgs([]string{"run", "--pattern", "echo", "--provider", "my-llm", "notes.txt"})
+	out, err := captureStdout(t, cmd.Execute)
+	if err != nil {
+		t.Fatalf("Expected the plugin from providers.yaml to run, got %v", err)
+	}
+	if !strings.Contains(out, "hello from my-llm") {
+		t.Errorf("Expected the plugin's reply, got %q", out)
+	}
+}
+
+func TestRunWithAzureProviderFromProvidersFile(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(`{"model": "gpt-4o", "choices": [{"message": {"content": "hello from azure"}}]}`))
+	}))
+	defer server.Close()
+
+	configDir := setupFabricHome(t)
+	t.Setenv("TEST_AZURE_KEY", "secret")
+	t.Setenv("TEST_TEAM", "search")
+	providersYAML := `providers:
+  - name: azure
+    type: azure
+    config:
+      base_url: ` + server.URL + `
+      api_key_env: TEST_AZURE_KEY
+      model: gpt-4o
+      deployments:
+        gpt-4o: prod-4o
+      headers:
+        X-Team: "${TEST_TEAM}"
+`
+	if err := os.WriteFile(filepath.Join(configDir, "providers.yaml"), []byte(providersYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeRunInput(t)
+
+	cmd := NewRootCmd("test")
+	cmd.SetArgs([]string{"run", "--pattern", "echo", "--provider", "azure", "--model", "gpt-4o", "notes.txt"})
+	out, err := captureStdout(t, cmd.Execute)
+	if err != nil {
+		t.Fatalf("Expected the azure provider from providers.yaml to run, got %v", err)
+	}
+	if !strings.Contains(out, "hello from azure") {
+		t.Errorf("Expected the provider's reply, got %q", out)
+	}
+	if got == nil || got.URL.Path != "/openai/deployments/prod-4o/chat/completions" {
+		t.Fatalf("Expected the deployment URL, got %v", got)
+	}
+	if got.Header.Get("api-key") != "secret" || got.Header.Get("X-Team") != "search" {
+		t.Errorf("Expected the api-key and extra headers, got %v", got.Header)
+	}
+}
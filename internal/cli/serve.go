@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+	"github.com/rice0649/fabric-lite/internal/server"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// serveTokenEnv supplies the bearer token without putting it on the command line
+const serveTokenEnv = "FABRIC_LITE_SERVE_TOKEN"
+
+func newServeCmd() *cobra.Command {
+	opts := server.Options{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve patterns over an HTTP API",
+		Long: `Serve patterns and providers as a REST API so other services and editors
+can run patterns without shelling out.
+
+Endpoints:
+  GET  /health         - liveness check (no auth)
+  GET  /api/patterns   - list patterns
+  POST /api/execute    - run a pattern: {"pattern", "input", "variables", "provider", "model", "stream",
+                         and sampling such as "temperature", "top_p" or "stop"}
+  GET  /api/providers  - list providers and their models
+
+OpenAI-compatible gateway:
+  POST /v1/chat/completions - chat completions, streamed when "stream" is true
+  GET  /v1/models           - model aliases and "provider/model" ids
+  GET  /v1/usage            - requests, errors, fallbacks and tokens per route
+
+The "model" of a chat completion is routed to a provider by the serve.models
+aliases in the config file, then a "provider/model" prefix, then providers
+listing the model, then the default provider. When the provider fails
+before producing output, the serve.fallback routes are tried in order.
+
+Executions stream as server-sent events ("chunk", then "done" or "error")
+when "stream" is true or the request accepts text/event-stream.
+
+With --token (or ` + serveTokenEnv + `), /api and /v1 routes require
+"Authorization: Bearer <token>". Each client address may have
+--max-concurrent executions in flight; further requests get 429.
+On SIGINT or SIGTERM the server stops accepting connections and lets
+running requests finish for up to --shutdown-timeout.`,
+		Example: `  # Serve on localhost:8080
+  fabric-lite serve
+
+  # Share with the team behind a token
+  FABRIC_LITE_SERVE_TOKEN=secret fabric-lite serve --addr 0.0.0.0:8080
+
+  # Run a pattern
+  curl -s localhost:8080/api/execute -d '{"pattern": "summarize", "input": "..."}'
+
+  # Point an OpenAI client at the gateway
+  OPENAI_BASE_URL=http://localhost:8080/v1 OPENAI_API_KEY=secret some-openai-tool`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Token == "" {
+				opts.Token = os.Getenv(serveTokenEnv)
+			}
+			if opts.Token == "" && !isLoopback(opts.Addr) {
+				fmt.Fprintf(os.Stderr, "Warning: serving on %s without a token; anyone who can reach it can run patterns\n", opts.Addr)
+			}
+
+			config := core.GetDefaultConfig()
+			opts.DefaultProvider = viper.GetString("provider")
+			if opts.DefaultProvider == "" {
+				opts.DefaultProvider = config.Tools.Codex.Provider
+			}
+			opts.ModelMap = config.Serve.Models
+			if !cmd.Flags().Changed("fallback") {
+				opts.Fallback = config.Serve.Fallback
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			fmt.Fprintf(os.Stderr, "Serving fabric-lite API on http://%s\n", opts.Addr)
+			return server.New(opts, core.GetDefaultProviderManager()).ListenAndServe(ctx)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Addr, "addr", server.DefaultAddr, "address to listen on")
+	cmd.Flags().StringVar(&opts.Token, "token", "", "bearer token required on /api and /v1 routes (default $"+serveTokenEnv+")")
+	cmd.Flags().Int64Var(&opts.MaxBodyBytes, "max-body-bytes", server.DefaultMaxBodyBytes, "largest accepted request body")
+	cmd.Flags().IntVar(&opts.MaxConcurrent, "max-concurrent", server.DefaultMaxConcurrent, "executions in flight per client address")
+	cmd.Flags().DurationVar(&opts.ShutdownTimeout, "shutdown-timeout", server.DefaultShutdownTimeout, "how long running requests may take to finish on shutdown")
+	cmd.Flags().StringSliceVar(&opts.Fallback, "fallback", nil, "providers (or provider/model) tried in order when a /v1 route fails (default: serve.fallback)")
+	cmd.Flags().StringVar(&opts.PatternsDir, "patterns-dir", "", "directory to load patterns from (default: ./patterns or ~/.config/fabric-lite/patterns)")
+
+	return cmd
+}
+
+// isLoopback reports whether addr only listens on the local machine
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -57,6 +57,7 @@ This creates a markdown file with:
 		},
 	}
 
+	// Shadows the global --output format flag: session save always writes markdown
 	cmd.Flags().StringVarP(&outputPath, "output", "o", ".forge/session.md", "output file path")
 	cmd.Flags().BoolVar(&includeContext, "context", false, "include extended context for AI assistants")
 
@@ -87,8 +88,23 @@ func newSessionShowCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "show",
 		Short: "Show current session state without saving",
-		Long:  `Display current session state to stdout without saving to a file.`,
+		Long: `Display current session state to stdout without saving to a file.
+
+With --output json or yaml the session is printed as structured data
+instead of markdown.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+			if format != outputText {
+				cfg, state, err := loadProject()
+				if err != nil {
+					return err
+				}
+				return printOutput(format, buildSessionView(cfg, state))
+			}
+
 			content, err := generateSessionContent(true)
 			if err != nil {
 				return err
@@ -142,14 +158,9 @@ func generateSessionContent(includeContext bool) (string, error) {
 	var sb strings.Builder
 
 	// Load project config and state
-	cfg, err := core.LoadProjectConfig(".forge/config.yaml")
-	if err != nil {
-		return "", fmt.Errorf("not a forge project (run 'forge init' first)")
-	}
-
-	state, err := core.LoadProjectState(".forge/state.yaml")
+	cfg, state, err := loadProject()
 	if err != nil {
-		return "", fmt.Errorf("failed to load project state: %w", err)
+		return "", err
 	}
 
 	// Header
@@ -316,6 +327,21 @@ func generateSessionContent(includeContext bool) (string, error) {
 	return sb.String(), nil
 }
 
+// loadProject loads the forge config and state of the current directory
+func loadProject() (*core.ProjectConfig, *core.ProjectState, error) {
+	cfg, err := core.LoadProjectConfig(".forge/config.yaml")
+	if err != nil {
+		return nil, nil, fmt.Errorf("not a forge project (run 'forge init' first)")
+	}
+
+	state, err := core.LoadProjectState(".forge/state.yaml")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load project state: %w", err)
+	}
+
+	return cfg, state, nil
+}
+
 func generateNextSteps(state *core.ProjectState) []string {
 	steps := []string{}
 
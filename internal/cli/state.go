@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func newStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Inspect and repair project state",
+		Long: `Inspect and repair .forge/state.yaml.
+
+State is written atomically under an advisory lock (.forge/state.yaml.lock),
+and the previous version is kept in .forge/state.yaml.bak. State and config
+files carry a schema_version; older files are upgraded when they are loaded.`,
+	}
+
+	cmd.AddCommand(newStateCheckCmd())
+	cmd.AddCommand(newStateRestoreCmd())
+
+	return cmd
+}
+
+func newStateRestoreCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore",
+		Short: "Restore state from the backup of the previous version",
+		Long: `Replace .forge/state.yaml with .forge/state.yaml.bak.
+
+Every state save keeps the previous version as a backup, so this undoes
+the last change or recovers from a corrupted state file.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			statePath := ".forge/state.yaml"
+			state, err := core.RestoreProjectState(statePath)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Restored state from %s\n", core.StateBackupPath(statePath))
+			if state.CurrentPhase != "" {
+				fmt.Printf("Current phase: %s\n", state.CurrentPhase)
+			}
+			if !state.UpdatedAt.IsZero() {
+				fmt.Printf("Last updated: %s\n", state.UpdatedAt.Format("2006-01-02 15:04:05"))
+			}
+			return nil
+		},
+	}
+}
+
+func newStateCheckCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check",
+		Short: "Report invalid or inconsistent project state",
+		Long: `Check .forge/state.yaml and .forge/config.yaml against the phase registry.
+
+Errors (unknown phases or statuses) stop other forge commands from loading
+the state. Warnings point at inconsistencies, e.g. a current phase that is
+already completed or a completed phase whose dependencies are not.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			statePath := ".forge/state.yaml"
+
+			var configIssue error
+			cfg, err := core.LoadProjectConfig(".forge/config.yaml")
+			if err != nil && !os.IsNotExist(err) {
+				configIssue = err
+				cfg = nil
+			}
+
+			result, err := core.CheckProjectState(statePath, cfg)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return fmt.Errorf("not a forge project (run 'forge init' first)")
+				}
+				return fmt.Errorf("cannot read state: %w (try 'forge state restore')", err)
+			}
+
+			fmt.Printf("State: %s (schema version %d)\n\n", statePath, result.SchemaVersion)
+
+			errorCount := 0
+			if configIssue != nil {
+				errorCount++
+				fmt.Printf("  ✗ config: %v\n", configIssue)
+			}
+			for _, issue := range result.Issues {
+				icon := "!"
+				if issue.Severity == core.IssueError {
+					icon = "✗"
+					errorCount++
+				}
+				fmt.Printf("  %s %s\n", icon, issue.Message)
+			}
+
+			if configIssue == nil && len(result.Issues) == 0 {
+				fmt.Println("  ✓ No issues found")
+				return nil
+			}
+			if errorCount > 0 {
+				return fmt.Errorf("state check found %d error(s)", errorCount)
+			}
+			return nil
+		},
+	}
+}
@@ -20,9 +20,14 @@ func newStatusCmd() *cobra.Command {
 		Long: `Display a dashboard view of the project status.
 
 Shows current phase, progress, checkpoint status, and suggested next steps.
-Use --detailed for additional information including artifact list and full activity log.`,
+Use --detailed for additional information including artifact list and full activity log.
+Use --output json or --output yaml for machine-readable output.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return showStatus(detailed)
+			format, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+			return showStatus(format, detailed)
 		},
 	}
 
@@ -31,44 +36,27 @@ Use --detailed for additional information including artifact list and full activ
 	return cmd
 }
 
-func newHistoryCmd() *cobra.Command {
-	var limit int
-
-	cmd := &cobra.Command{
-		Use:   "history",
-		Short: "View activity history",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return showHistory(limit)
-		},
-	}
-
-	cmd.Flags().IntVarP(&limit, "limit", "n", 10, "number of entries to show")
-
-	return cmd
-}
-
-func showStatus(detailed bool) error {
-	cfg, err := core.LoadProjectConfig(".forge/config.yaml")
+func showStatus(format string, detailed bool) error {
+	cfg, state, err := loadProject()
 	if err != nil {
-		return fmt.Errorf("not a forge project (run 'forge init' first)")
+		return err
 	}
 
-	state, err := core.LoadProjectState(".forge/state.yaml")
-	if err != nil {
-		return fmt.Errorf("failed to load project state: %w", err)
+	view := buildStatusView(cfg, state, detailed)
+	if format != outputText {
+		return printOutput(format, view)
 	}
 
 	// Header
 	fmt.Println("╔════════════════════════════════════════════════════════════╗")
-	fmt.Printf("║  AI Project Forge - %s\n", padRight(cfg.Name, 38)+"║")
+	fmt.Printf("║  AI Project Forge - %s\n", padRight(view.Project.Name, 38)+"║")
 	fmt.Println("╠════════════════════════════════════════════════════════════╣")
 
 	// Current Phase
-	if state.CurrentPhase != "" {
-		phase := core.GetPhase(state.CurrentPhase)
-		fmt.Printf("║  Current Phase: %-42s ║\n", state.CurrentPhase)
-		fmt.Printf("║  Primary Tool:  %-42s ║\n", phase.PrimaryTool)
-		fmt.Printf("║  Duration:      %-42s ║\n", formatDuration(time.Since(state.PhaseStartedAt)))
+	if current := view.CurrentPhase; current != nil {
+		fmt.Printf("║  Current Phase: %-42s ║\n", current.Name)
+		fmt.Printf("║  Primary Tool:  %-42s ║\n", current.PrimaryTool)
+		fmt.Printf("║  Duration:      %-42s ║\n", formatDuration(time.Since(current.StartedAt)))
 	} else {
 		fmt.Printf("║  %-58s ║\n", "No active phase")
 	}
@@ -77,42 +65,32 @@ func showStatus(detailed bool) error {
 
 	// Phase Progress
 	fmt.Println("║  Progress:                                                 ║")
-	completed := 0
-	for _, p := range core.AllPhases {
-		status := state.GetPhaseStatus(p.Name)
-		icon := getStatusIcon(status, p.Name == state.CurrentPhase)
-		if status == "completed" {
-			completed++
-		}
+	for _, p := range view.Phases {
+		icon := getStatusIcon(p.Status, p.Current)
 		fmt.Printf("║    %s %-54s ║\n", icon, p.Name)
 	}
 
 	fmt.Println("╠════════════════════════════════════════════════════════════╣")
 
 	// Progress bar
-	progress := float64(completed) / float64(len(core.AllPhases)) * 100
 	barLen := 50
-	filled := int(float64(barLen) * float64(completed) / float64(len(core.AllPhases)))
+	filled := int(float64(barLen) * float64(view.Progress.Completed) / float64(view.Progress.Total))
 	bar := strings.Repeat("█", filled) + strings.Repeat("░", barLen-filled)
-	fmt.Printf("║  [%s] %3.0f%% ║\n", bar, progress)
+	fmt.Printf("║  [%s] %3.0f%% ║\n", bar, view.Progress.Percent)
 
 	fmt.Println("╠════════════════════════════════════════════════════════════╣")
 
 	// Artifacts count
-	artifactCount := countArtifacts()
-	fmt.Printf("║  Artifacts Generated: %-36d ║\n", artifactCount)
+	fmt.Printf("║  Artifacts Generated: %-36d ║\n", view.ArtifactCount)
 
 	// Checkpoint status (if in a phase)
-	if state.CurrentPhase != "" {
+	if checkpoint := view.Checkpoint; checkpoint != nil {
 		fmt.Println("╠════════════════════════════════════════════════════════════╣")
 		fmt.Println("║  Checkpoint Status:                                        ║")
-		result := core.ValidateCheckpoint(state.CurrentPhase)
-		passed := 0
-		for _, check := range result.Checks {
+		for _, check := range checkpoint.Checks {
 			icon := "✗"
 			if check.Passed {
 				icon = "✓"
-				passed++
 			}
 			name := check.Name
 			if len(name) > 52 {
@@ -120,19 +98,14 @@ func showStatus(detailed bool) error {
 			}
 			fmt.Printf("║    %s %-54s ║\n", icon, name)
 		}
-		fmt.Printf("║    Progress: %d/%d criteria met%-28s║\n", passed, len(result.Checks), "")
+		fmt.Printf("║    Progress: %d/%d criteria met%-28s║\n", checkpoint.Met, len(checkpoint.Checks), "")
 	}
 
 	// Recent activity
-	if len(state.Activities) > 0 {
+	if len(view.RecentActivity) > 0 {
 		fmt.Println("╠════════════════════════════════════════════════════════════╣")
 		fmt.Println("║  Recent Activity:                                          ║")
-		limit := 3
-		if len(state.Activities) < limit {
-			limit = len(state.Activities)
-		}
-		for i := len(state.Activities) - 1; i >= len(state.Activities)-limit; i-- {
-			a := state.Activities[i]
+		for _, a := range view.RecentActivity {
 			timeStr := a.Timestamp.Format("15:04")
 			line := fmt.Sprintf("%s %s", timeStr, a.Message)
 			if len(line) > 56 {
@@ -146,8 +119,7 @@ func showStatus(detailed bool) error {
 
 	// Next Steps
 	fmt.Println("║  Suggested Next Steps:                                     ║")
-	nextSteps := getNextSteps(state)
-	for _, step := range nextSteps {
+	for _, step := range view.NextSteps {
 		if len(step) > 56 {
 			step = step[:53] + "..."
 		}
@@ -158,7 +130,7 @@ func showStatus(detailed bool) error {
 
 	// Detailed view
 	if detailed {
-		showDetailedStatus(state)
+		showDetailedStatus(view.Artifacts)
 	}
 
 	return nil
@@ -190,29 +162,24 @@ func getNextSteps(state *core.ProjectState) []string {
 	return steps
 }
 
-func showDetailedStatus(state *core.ProjectState) {
+func showDetailedStatus(artifacts map[string][]string) {
 	fmt.Println()
 	fmt.Println("═══ Detailed Information ═══")
 	fmt.Println()
 
 	// List artifacts
 	fmt.Println("Artifacts:")
-	hasArtifacts := false
 	for _, p := range core.AllPhases {
-		artifactDir := filepath.Join(".forge", "artifacts", p.Name)
-		entries, err := os.ReadDir(artifactDir)
-		if err != nil || len(entries) == 0 {
+		files := artifacts[p.Name]
+		if len(files) == 0 {
 			continue
 		}
-		hasArtifacts = true
 		fmt.Printf("  %s/\n", p.Name)
-		for _, e := range entries {
-			if !e.IsDir() {
-				fmt.Printf("    - %s\n", e.Name())
-			}
+		for _, name := range files {
+			fmt.Printf("    - %s\n", name)
 		}
 	}
-	if !hasArtifacts {
+	if len(artifacts) == 0 {
 		fmt.Println("  (none)")
 	}
 
@@ -220,33 +187,6 @@ func showDetailedStatus(state *core.ProjectState) {
 	fmt.Println("Tip: Run 'forge session save' to save full state for resuming later.")
 }
 
-func showHistory(limit int) error {
-	state, err := core.LoadProjectState(".forge/state.yaml")
-	if err != nil {
-		return fmt.Errorf("not a forge project (run 'forge init' first)")
-	}
-
-	if len(state.Activities) == 0 {
-		fmt.Println("No activity history yet.")
-		return nil
-	}
-
-	fmt.Println("Activity History:")
-	fmt.Println()
-
-	start := 0
-	if len(state.Activities) > limit {
-		start = len(state.Activities) - limit
-	}
-
-	for i := len(state.Activities) - 1; i >= start; i-- {
-		a := state.Activities[i]
-		fmt.Printf("  %s  %s\n", a.Timestamp.Format("2006-01-02 15:04:05"), a.Message)
-	}
-
-	return nil
-}
-
 func countArtifacts() int {
 	count := 0
 	artifactDir := ".forge/artifacts"
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArtifactsDir is the root directory for generated phase artifacts
+const ArtifactsDir = ".forge/artifacts"
+
+// ArchiveDir holds artifacts of phases that were reset. It lives outside
+// ArtifactsDir so archived files are not counted or passed to tools.
+const ArchiveDir = ".forge/archive"
+
+// DefaultArtifactContextLimit bounds how much artifact content is passed to tools
+const DefaultArtifactContextLimit = 64 * 1024
+
+// Artifact is a generated file belonging to a phase
+type Artifact struct {
+	Phase     string
+	Name      string
+	Path      string
+	Content   string
+	Truncated bool
+}
+
+// ArtifactDir returns the artifact directory for a phase
+func ArtifactDir(phase string) string {
+	return filepath.Join(ArtifactsDir, phase)
+}
+
+// ReadArtifacts reads the artifacts of a phase, sorted by name.
+// Contents are truncated so the total does not exceed maxBytes (0 = no limit).
+func ReadArtifacts(phase string, maxBytes int) ([]Artifact, error) {
+	dir := ArtifactDir(phase)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	artifacts := make([]Artifact, 0, len(names))
+	remaining := maxBytes
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read artifact %s: %w", path, err)
+		}
+
+		a := Artifact{Phase: phase, Name: name, Path: path, Content: string(data)}
+		if maxBytes > 0 {
+			if remaining <= 0 {
+				a.Content = ""
+				a.Truncated = true
+			} else if len(a.Content) > remaining {
+				a.Content = a.Content[:remaining]
+				a.Truncated = true
+			}
+			remaining -= len(a.Content)
+		}
+		artifacts = append(artifacts, a)
+	}
+
+	return artifacts, nil
+}
+
+// PriorArtifactsContext formats the artifacts of the phases the given one
+// depends on, directly or transitively, for use as tool context, bounded by
+// maxBytes. Phases that may still be running alongside it are left out.
+func PriorArtifactsContext(graph *PhaseGraph, phase string, maxBytes int) (string, error) {
+	var sb strings.Builder
+	remaining := maxBytes
+
+	for _, dep := range graph.Dependencies(phase) {
+		if maxBytes > 0 && remaining <= 0 {
+			break
+		}
+
+		artifacts, err := ReadArtifacts(dep, remaining)
+		if err != nil {
+			return "", err
+		}
+		for _, a := range artifacts {
+			if a.Content == "" {
+				continue
+			}
+			sb.WriteString(formatArtifact(a))
+			remaining -= len(a.Content)
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// SaveArtifact writes content to the phase artifact directory and returns its path
+func SaveArtifact(phase, name, content string) (string, error) {
+	dir := ArtifactDir(phase)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create artifact directory: %w", err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("write artifact %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// NewArtifactName returns "<prefix>_<timestamp>.md" for a phase artifact,
+// adding a counter when an artifact of that name already exists so outputs
+// saved within the same second don't overwrite each other
+func NewArtifactName(phase, prefix string, t time.Time) string {
+	base := fmt.Sprintf("%s_%s", prefix, t.Format("20060102_150405"))
+	name := base + ".md"
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(ArtifactDir(phase), name)); os.IsNotExist(err) {
+			return name
+		}
+		name = fmt.Sprintf("%s_%d.md", base, i)
+	}
+}
+
+// AttemptArchiveDir holds the outputs of superseded forge auto attempts
+func AttemptArchiveDir(phase string) string {
+	return filepath.Join(ArchiveDir, phase, "attempts")
+}
+
+// ArchiveAttemptArtifact moves the artifact at path, the output of a
+// superseded attempt, to AttemptArchiveDir and returns its new path
+func ArchiveAttemptArtifact(phase, path string) (string, error) {
+	dir := AttemptArchiveDir(phase)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create archive directory: %w", err)
+	}
+	archived := filepath.Join(dir, filepath.Base(path))
+	if err := os.Rename(path, archived); err != nil {
+		return "", fmt.Errorf("archive %s: %w", path, err)
+	}
+	return archived, nil
+}
+
+// ArtifactArchiveDir returns where the artifacts of a phase reset at t are
+// archived, adding a counter suffix when that directory already exists
+func ArtifactArchiveDir(phase string, t time.Time) string {
+	base := filepath.Join(ArchiveDir, phase, t.Format("20060102_150405"))
+	dir := base
+	for i := 2; ; i++ {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return dir
+		}
+		dir = fmt.Sprintf("%s_%d", base, i)
+	}
+}
+
+// ArchiveArtifacts moves the artifacts of a phase to ArtifactArchiveDir and
+// leaves an empty artifact directory behind. It returns the archive
+// directory, or "" when the phase has no artifacts.
+func ArchiveArtifacts(phase string, t time.Time) (string, error) {
+	dir := ArtifactDir(phase)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", nil
+	}
+
+	archive := ArtifactArchiveDir(phase, t)
+	if err := os.MkdirAll(filepath.Dir(archive), 0755); err != nil {
+		return "", fmt.Errorf("create archive directory: %w", err)
+	}
+	if err := os.Rename(dir, archive); err != nil {
+		return "", fmt.Errorf("archive artifacts of %s: %w", phase, err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return archive, fmt.Errorf("recreate artifact directory: %w", err)
+	}
+	return archive, nil
+}
+
+func formatArtifact(a Artifact) string {
+	header := fmt.Sprintf("### %s/%s", a.Phase, a.Name)
+	if a.Truncated {
+		header += " (truncated)"
+	}
+	return header + "\n\n" + strings.TrimRight(a.Content, "\n") + "\n\n"
+}
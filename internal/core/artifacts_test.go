@@ -0,0 +1,127 @@
+package core
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// chdirTemp switches to a fresh temporary directory for the duration of the test
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	return dir
+}
+
+func TestSaveAndReadArtifacts(t *testing.T) {
+	chdirTemp(t)
+
+	if _, err := SaveArtifact("discovery", "b.md", "second"); err != nil {
+		t.Fatalf("Expected no error saving artifact, got %v", err)
+	}
+	if _, err := SaveArtifact("discovery", "a.md", "first"); err != nil {
+		t.Fatalf("Expected no error saving artifact, got %v", err)
+	}
+
+	artifacts, err := ReadArtifacts("discovery", 0)
+	if err != nil {
+		t.Fatalf("Expected no error reading artifacts, got %v", err)
+	}
+	if len(artifacts) != 2 {
+		t.Fatalf("Expected 2 artifacts, got %d", len(artifacts))
+	}
+	if artifacts[0].Name != "a.md" || artifacts[0].Content != "first" {
+		t.Errorf("Expected a.md with 'first', got %s with '%s'", artifacts[0].Name, artifacts[0].Content)
+	}
+
+	// Missing phase directory is not an error
+	artifacts, err = ReadArtifacts("planning", 0)
+	if err != nil || len(artifacts) != 0 {
+		t.Errorf("Expected no artifacts and no error, got %d, %v", len(artifacts), err)
+	}
+}
+
+func TestReadArtifactsTruncates(t *testing.T) {
+	chdirTemp(t)
+
+	SaveArtifact("design", "a.md", "0123456789")
+	SaveArtifact("design", "b.md", "abcdef")
+
+	artifacts, err := ReadArtifacts("design", 12)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if artifacts[0].Truncated {
+		t.Error("Expected first artifact to fit within the limit")
+	}
+	if !artifacts[1].Truncated || artifacts[1].Content != "ab" {
+		t.Errorf("Expected second artifact truncated to 'ab', got '%s'", artifacts[1].Content)
+	}
+}
+
+func TestPriorArtifactsContext(t *testing.T) {
+	chdirTemp(t)
+
+	SaveArtifact("discovery", "requirements.md", "Must be fast")
+	SaveArtifact("planning", "architecture.md", "Monolith")
+	SaveArtifact("design", "api_spec.md", "GET /items")
+
+	graph, err := NewPhaseGraph(nil)
+	if err != nil {
+		t.Fatalf("Failed to build phase graph: %v", err)
+	}
+	context, err := PriorArtifactsContext(graph, "design", 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(context, "discovery/requirements.md") || !strings.Contains(context, "Monolith") {
+		t.Errorf("Expected context to include earlier phases, got:\n%s", context)
+	}
+	if strings.Contains(context, "GET /items") {
+		t.Error("Expected context to exclude the current phase's artifacts")
+	}
+}
+
+func TestPriorArtifactsContextFollowsDependencies(t *testing.T) {
+	chdirTemp(t)
+
+	SaveArtifact("discovery", "requirements.md", "Must be fast")
+	SaveArtifact("design", "api_spec.md", "GET /items")
+	SaveArtifact("implementation", "notes.md", "Built it")
+	SaveArtifact("testing", "results.md", "Half written")
+
+	// deployment runs in the same wave as testing here
+	graph, err := NewPhaseGraph(map[string][]string{"deployment": {"implementation"}})
+	if err != nil {
+		t.Fatalf("Failed to build phase graph: %v", err)
+	}
+	context, err := PriorArtifactsContext(graph, "deployment", 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{"Must be fast", "GET /items", "Built it"} {
+		if !strings.Contains(context, want) {
+			t.Errorf("Expected context to include transitive dependency output %q, got:\n%s", want, context)
+		}
+	}
+	if strings.Contains(context, "Half written") {
+		t.Error("Expected context to exclude phases that are not dependencies")
+	}
+
+	// Phases before discovery in list order but not depended on are skipped too
+	graph, err = NewPhaseGraph(map[string][]string{"design": nil})
+	if err != nil {
+		t.Fatalf("Failed to build phase graph: %v", err)
+	}
+	if context, _ := PriorArtifactsContext(graph, "design", 0); context != "" {
+		t.Errorf("Expected no context for a phase without dependencies, got:\n%s", context)
+	}
+}
@@ -1,46 +1,97 @@
 package core
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"regexp"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
 // AutoRunner orchestrates automated phase execution
 type AutoRunner struct {
-	Config    *ProjectConfig
-	State     *ProjectState
-	StatePath string
-	Validator *PhaseValidator
-	Executor  PhaseExecutor
-	Verbose   bool
+	Config     *ProjectConfig
+	State      *ProjectState
+	StatePath  string
+	Validator  *PhaseValidator
+	Executor   PhaseExecutor
+	Approver   Approver        // Asks for gate decisions interactively; nil when there is no terminal
+	Git        *GitIntegration // Commits .forge after each completed phase; nil disables
+	Verbose    bool
+	MaxRetries int // Extra attempts per phase when validation fails
+
+	mu sync.Mutex // Guards State while phases of a wave run concurrently
 }
 
-// PhaseExecutor interface for executing phases (allows mocking in tests)
+// update applies fn to the state file under the state lock and makes the
+// result the runner's State, so changes other forge commands make while a
+// run is in progress are kept. Callers must hold mu.
+func (r *AutoRunner) update(fn func(*ProjectState)) error {
+	apply := func(s *ProjectState) error {
+		if s.Auto == nil {
+			s.Auto = &AutoState{StartedAt: time.Now()}
+		}
+		fn(s)
+		return nil
+	}
+
+	state, err := UpdateProjectState(r.StatePath, apply)
+	if os.IsNotExist(err) {
+		// Nothing saved yet; the runner's state becomes the first version
+		apply(r.State)
+		return r.State.Save(r.StatePath)
+	}
+	if err != nil {
+		return err
+	}
+	r.State = state
+	return nil
+}
+
+// PhaseExecutor interface for executing phases (allows mocking in tests).
+// feedback is empty on the first attempt and explains why the previous
+// attempt was not accepted on retries.
 type PhaseExecutor interface {
-	Execute(phase string) error
+	Execute(phase string, feedback PhaseFeedback) (*PhaseExecution, error)
+}
+
+// Sources of feedback on a previous attempt
+const (
+	FeedbackValidation = "validation" // The validator rejected the output
+	FeedbackReviewer   = "reviewer"   // A reviewer requested changes at an approval gate
+)
+
+// PhaseFeedback explains why the previous attempt at a phase was not accepted
+type PhaseFeedback struct {
+	Source string
+	Text   string
 }
 
-// PhaseValidator validates phase outputs using AI
-type PhaseValidator struct {
-	ExecuteFunc func(pattern, input string) (string, error)
+// IsZero reports whether there is no feedback, as on a first attempt
+func (f PhaseFeedback) IsZero() bool {
+	return f.Text == ""
 }
 
-// AIValidationResult holds the result of AI validation
-type AIValidationResult struct {
-	Valid    bool   `json:"valid"`
-	Feedback string `json:"feedback"`
+// PhaseExecution holds the outcome of a single phase execution
+type PhaseExecution struct {
+	Output   string
+	Artifact string // Path of the saved output, if any
+	Tokens   int    // Tokens used by the tool, estimated when the tool does not report usage
 }
 
 // NewAutoRunner creates a new AutoRunner with validation
 func NewAutoRunner(config *ProjectConfig, state *ProjectState, statePath string) *AutoRunner {
-	return &AutoRunner{
+	runner := &AutoRunner{
 		Config:    config,
 		State:     state,
 		StatePath: statePath,
 	}
+	if config != nil {
+		runner.MaxRetries = config.Advanced.MaxRetries
+	}
+	return runner
 }
 
 // Run executes phases automatically from `from` to `until`
@@ -50,32 +101,45 @@ func (r *AutoRunner) Run(from, until string, skipValidation bool) error {
 		return fmt.Errorf("auto runner not initialized")
 	}
 
-	// Initialize auto state if needed
-	if r.State.Auto == nil {
-		r.State.Auto = &AutoState{
-			StartedAt: time.Now(),
-		}
-	}
-
-	// Get phase range
-	phases, err := r.getPhaseRange(from, until)
+	// Plan the phase range as waves of independent phases
+	waves, err := r.Plan(from, until)
 	if err != nil {
 		return fmt.Errorf("get phases: %w", err)
 	}
-	if len(phases) == 0 {
+	if len(waves) == 0 {
 		return fmt.Errorf("no phases to run")
 	}
+	for _, wave := range waves {
+		for _, phase := range wave {
+			if err := r.Config.GateFor(phase).Validate(); err != nil {
+				return fmt.Errorf("gate for %s: %w", phase, err)
+			}
+		}
+	}
+
+	// Check before any phase runs: the phases edit files outside .forge,
+	// which are never part of the phase commits
+	if r.Git != nil {
+		if err := r.Git.CheckClean(); err != nil {
+			return err
+		}
+	}
 
 	// Store configuration in state for resume
-	r.State.Auto.FromPhase = from
-	r.State.Auto.UntilPhase = until
-	r.State.Auto.SkipValidation = skipValidation
-	r.State.Auto.StartedAt = time.Now()
+	err = r.update(func(s *ProjectState) {
+		s.Auto.FromPhase = from
+		s.Auto.UntilPhase = until
+		s.Auto.SkipValidation = skipValidation
+		s.Auto.StartedAt = time.Now()
+	})
+	if err != nil {
+		return fmt.Errorf("save state: %w", err)
+	}
 
-	fmt.Printf("Running phases: %s\n", strings.Join(phases, " → "))
+	fmt.Printf("Running phases: %s\n", FormatWaves(waves))
 
-	for _, phase := range phases {
-		if err := r.runPhase(phase, skipValidation); err != nil {
+	for _, wave := range waves {
+		if err := r.runWave(wave, skipValidation); err != nil {
 			return err
 		}
 	}
@@ -84,212 +148,520 @@ func (r *AutoRunner) Run(from, until string, skipValidation bool) error {
 	return nil
 }
 
-// runPhase executes a single phase with checkpointing and optional validation
+// Plan returns the phases between from and until grouped into waves;
+// phases within a wave have no dependencies on each other
+func (r *AutoRunner) Plan(from, until string) ([][]string, error) {
+	phases, err := r.getPhaseRange(from, until)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := r.Config.PhaseGraph()
+	if err != nil {
+		return nil, err
+	}
+	return graph.Waves(phases), nil
+}
+
+// FormatWaves renders waves as "a → b → c + d"
+func FormatWaves(waves [][]string) string {
+	parts := make([]string, len(waves))
+	for i, wave := range waves {
+		parts[i] = strings.Join(wave, " + ")
+	}
+	return strings.Join(parts, " → ")
+}
+
+// runWave runs the phases of a wave concurrently and waits for all of them
+func (r *AutoRunner) runWave(wave []string, skipValidation bool) error {
+	if len(wave) == 1 {
+		return r.runPhase(wave[0], skipValidation)
+	}
+
+	errs := make([]error, len(wave))
+	var wg sync.WaitGroup
+	for i, phase := range wave {
+		wg.Add(1)
+		go func(i int, phase string) {
+			defer wg.Done()
+			errs[i] = r.runPhase(phase, skipValidation)
+		}(i, phase)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// runPhase executes a single phase with checkpointing, optional validation
+// and the phase's approval gate. It holds the runner lock except while the
+// phase executes or is validated, so phases of a wave can run concurrently.
 func (r *AutoRunner) runPhase(phase string, skipValidation bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	fmt.Printf("\n─── Phase: %s ───\n", phase)
 
-	// Save state BEFORE execution (crash recovery)
-	r.State.CurrentPhase = phase
-	r.State.PhaseStartedAt = time.Now()
-	r.State.Auto.CurrentPhaseStatus = "running"
-	r.State.SetPhaseStatus(phase, "in_progress")
-	if err := r.State.Save(r.StatePath); err != nil {
-		return fmt.Errorf("save state before %s: %w", phase, err)
+	gate := r.Config.GateFor(phase)
+	var feedback PhaseFeedback
+	resumed := false
+	skipExecution := false
+	beforeApproved := false
+
+	// Apply decisions made with `forge approve` while the run was stopped
+	if approval := r.approvalFor(phase); approval != nil {
+		resumed = true
+		if approval.Status != ApprovalPending {
+			if err := r.clearApproval(phase); err != nil {
+				return fmt.Errorf("clear approval for %s: %w", phase, err)
+			}
+		}
+		switch approval.Status {
+		case ApprovalPending:
+			// Still undecided; ask again at the same gate without re-running the phase
+			skipExecution = approval.When == GateAfter
+		case ApprovalApproved:
+			if approval.When == GateAfter {
+				return r.completePhase(phase, nil)
+			}
+			beforeApproved = true
+		case ApprovalRejected:
+			return r.rejectPhase(phase)
+		case ApprovalChangesRequested:
+			feedback = PhaseFeedback{Source: FeedbackReviewer, Text: approval.Feedback}
+			beforeApproved = approval.When == GateBefore
+		}
 	}
 
-	// Execute the phase
-	if r.Executor != nil {
-		if err := r.Executor.Execute(phase); err != nil {
-			r.State.Auto.CurrentPhaseStatus = "failed"
-			r.State.Auto.Feedback = err.Error()
-			r.State.Save(r.StatePath) // Best effort save
-			return fmt.Errorf("phase %s failed: %w", phase, err)
+	if gate.Mode != GateAuto && gate.When == GateBefore && !beforeApproved {
+		decision, err := r.requestApproval(phase, gate)
+		if err != nil {
+			return err
+		}
+		switch decision.Status {
+		case ApprovalRejected:
+			return r.rejectPhase(phase)
+		case ApprovalChangesRequested:
+			feedback = PhaseFeedback{Source: FeedbackReviewer, Text: decision.Feedback}
 		}
-	} else {
-		// Default execution: just mark as needing manual run
-		fmt.Printf("  → Run: forge run (tool: %s)\n", GetDefaultTool(phase))
-		fmt.Printf("  → Artifacts: %v\n", GetPhase(phase).Artifacts)
 	}
 
-	// Save state AFTER execution
-	r.State.Auto.LastCompletedPhase = phase
-	r.State.Auto.CurrentPhaseStatus = "completed"
-	r.State.SetPhaseStatus(phase, "completed")
-	r.State.AddActivity(fmt.Sprintf("Auto: completed phase %s", phase))
-	if err := r.State.Save(r.StatePath); err != nil {
-		return fmt.Errorf("save state after %s: %w", phase, err)
+	if !skipExecution {
+		// Save state BEFORE execution (crash recovery)
+		err := r.update(func(s *ProjectState) {
+			s.CurrentPhase = phase
+			s.PhaseStartedAt = time.Now()
+			s.Auto.CurrentPhaseStatus = "running"
+			s.Auto.Feedback = ""
+			if !resumed {
+				delete(s.Auto.Attempts, phase)
+			}
+			s.SetPhaseStatus(phase, "in_progress")
+		})
+		if err != nil {
+			return fmt.Errorf("save state before %s: %w", phase, err)
+		}
 	}
 
-	// Validate if enabled
-	if !skipValidation && r.Validator != nil {
-		fmt.Printf("  → Validating phase output...\n")
-		result, err := r.validatePhase(phase)
-		if err != nil {
-			r.State.Auto.CurrentPhaseStatus = "validation_error"
-			r.State.Auto.Feedback = err.Error()
-			r.State.Save(r.StatePath)
-			return fmt.Errorf("validate %s: %w", phase, err)
-		}
-		if !result.Valid {
-			r.State.Auto.CurrentPhaseStatus = "validation_failed"
-			r.State.Auto.Feedback = result.Feedback
-			r.State.SetPhaseStatus(phase, "validation_failed")
-			if err := r.State.Save(r.StatePath); err != nil {
-				return fmt.Errorf("save validation feedback: %w", err)
+	var attempts []AttemptRecord
+	for {
+		if !skipExecution {
+			var err error
+			attempts, err = r.attemptPhase(phase, feedback, skipValidation, attempts)
+			if err != nil {
+				return err
 			}
-			return fmt.Errorf("validation failed for %s: %s", phase, result.Feedback)
 		}
-		fmt.Printf("  ✓ Validation passed\n")
+		skipExecution = false
+
+		if gate.Mode == GateAuto || gate.When != GateAfter {
+			break
+		}
+		decision, err := r.requestApproval(phase, gate)
+		if err != nil {
+			return err
+		}
+		if decision.Status == ApprovalRejected {
+			return r.rejectPhase(phase)
+		}
+		if decision.Status != ApprovalChangesRequested {
+			break
+		}
+		feedback = PhaseFeedback{Source: FeedbackReviewer, Text: decision.Feedback}
+		fmt.Printf("  → Re-running %s with reviewer feedback...\n", phase)
 	}
 
-	fmt.Printf("  ✓ Phase %s completed\n", phase)
-	return nil
+	return r.completePhase(phase, attempts)
 }
 
-// validatePhase runs AI validation on phase output
-func (r *AutoRunner) validatePhase(phase string) (*AIValidationResult, error) {
-	if r.Validator == nil || r.Validator.ExecuteFunc == nil {
-		return &AIValidationResult{Valid: true, Feedback: "Validation skipped (no validator)"}, nil
+// attemptPhase executes and validates a phase, retrying with validation
+// feedback up to MaxRetries extra times. It returns all attempts so far
+// once the phase output is accepted.
+func (r *AutoRunner) attemptPhase(phase string, feedback PhaseFeedback, skipValidation bool, attempts []AttemptRecord) ([]AttemptRecord, error) {
+	maxAttempts := r.MaxRetries + 1
+	if maxAttempts < 1 || r.Executor == nil {
+		maxAttempts = 1
 	}
 
-	phaseInfo := GetPhase(phase)
-	if phaseInfo == nil {
-		return nil, fmt.Errorf("unknown phase: %s", phase)
-	}
+	first := len(attempts) + 1
+	for try := 1; try <= maxAttempts; try++ {
+		attempt := first + try - 1
+		if try > 1 {
+			fmt.Printf("  → Retrying with validation feedback (attempt %d/%d)...\n", try, maxAttempts)
+		}
 
-	// Build validation input
-	input := fmt.Sprintf(`Phase: %s
-Description: %s
-Expected Artifacts: %v
-Checkpoint Criteria: %v
+		record := AttemptRecord{Attempt: attempt, StartedAt: time.Now()}
 
-Please validate that this phase has been completed successfully.`,
-		phase, phaseInfo.Description, phaseInfo.Artifacts, phaseInfo.Checkpoint.Criteria)
+		// Execute the phase
+		r.mu.Unlock()
+		execution, err := r.execute(phase, feedback)
+		r.mu.Lock()
+		if err != nil {
+			record.CompletedAt = time.Now()
+			record.Verdict = "error"
+			record.Feedback = err.Error()
+			attempts = append(attempts, record)
+			r.update(func(s *ProjectState) { // Best effort save
+				recordAttempt(s, phase, record)
+				s.Auto.CurrentPhaseStatus = "failed"
+				s.Auto.Feedback = err.Error()
+			})
+			r.saveHistory(phase, attempts, HistoryFailed)
+			return nil, fmt.Errorf("phase %s failed: %w", phase, err)
+		}
+		if execution != nil {
+			record.Output = execution.Output
+			record.Artifact = execution.Artifact
+			record.Tokens = execution.Tokens
+		}
 
-	// Execute validation pattern
-	output, err := r.Validator.ExecuteFunc("validation/validate_phase_output", input)
-	if err != nil {
-		return nil, fmt.Errorf("validation execution failed: %w", err)
+		// Only the latest attempt's output is validated and passed on
+		r.archiveAttempts(phase, attempts)
+
+		// Validate if enabled; checkpoint checks run even without an AI validator
+		if skipValidation {
+			record.CompletedAt = time.Now()
+			record.Verdict = "unvalidated"
+			attempts = append(attempts, record)
+			if err := r.update(func(s *ProjectState) { recordAttempt(s, phase, record) }); err != nil {
+				return nil, fmt.Errorf("save attempt: %w", err)
+			}
+			return attempts, nil
+		}
+
+		fmt.Printf("  → Validating phase output...\n")
+		r.mu.Unlock()
+		verdict, err := ValidatePhase(phase, r.Validator)
+		r.mu.Lock()
+		record.CompletedAt = time.Now()
+		if err != nil {
+			record.Verdict = "error"
+			record.Feedback = err.Error()
+			attempts = append(attempts, record)
+			r.update(func(s *ProjectState) { // Best effort save
+				recordAttempt(s, phase, record)
+				s.Auto.CurrentPhaseStatus = "validation_error"
+				s.Auto.Feedback = err.Error()
+			})
+			r.saveHistory(phase, attempts, HistoryFailed)
+			return nil, fmt.Errorf("validate %s: %w", phase, err)
+		}
+
+		record.Feedback = verdict.Feedback
+		record.Criteria = verdict.Criteria
+		if verdict.Warning != "" {
+			fmt.Printf("  → Warning: %s\n", verdict.Warning)
+		}
+		if verdict.Passed {
+			record.Verdict = "passed"
+			attempts = append(attempts, record)
+			if err := r.update(func(s *ProjectState) { recordAttempt(s, phase, record) }); err != nil {
+				return nil, fmt.Errorf("save attempt: %w", err)
+			}
+			fmt.Printf("  ✓ Validation passed\n")
+			return attempts, nil
+		}
+
+		record.Verdict = "failed"
+		attempts = append(attempts, record)
+		err = r.update(func(s *ProjectState) {
+			recordAttempt(s, phase, record)
+			s.Auto.Feedback = verdict.Feedback
+			s.AddActivity(fmt.Sprintf("Auto: validation failed for %s (attempt %d/%d)", phase, try, maxAttempts))
+		})
+		if err != nil {
+			return nil, fmt.Errorf("save validation feedback: %w", err)
+		}
+		fmt.Printf("  ✗ Validation failed: %s\n", verdict.Feedback)
+		feedback = PhaseFeedback{Source: FeedbackValidation, Text: verdict.Feedback}
 	}
 
-	// Parse JSON response
-	result, err := parseValidationResult(output)
+	err := r.update(func(s *ProjectState) {
+		s.Auto.CurrentPhaseStatus = "validation_failed"
+		s.SetPhaseStatus(phase, "validation_failed")
+	})
 	if err != nil {
-		return nil, fmt.Errorf("parse validation result: %w", err)
+		return nil, fmt.Errorf("save validation feedback: %w", err)
 	}
-
-	return result, nil
+	r.saveHistory(phase, attempts, HistoryFailed)
+	return nil, fmt.Errorf("validation failed for %s after %d attempt(s): %s", phase, maxAttempts, feedback.Text)
 }
 
-// getPhaseRange returns phases between from and until (inclusive)
-func (r *AutoRunner) getPhaseRange(from, until string) ([]string, error) {
-	allPhases := PhaseNames()
-
-	// Determine start phase
-	startIdx := 0
-	if from != "" {
-		if !IsValidPhase(from) {
-			return nil, fmt.Errorf("invalid start phase: %s", from)
-		}
-		for i, p := range allPhases {
-			if p == from {
-				startIdx = i
-				break
-			}
-		}
-	} else if r.State.Auto != nil && r.State.Auto.LastCompletedPhase != "" {
-		// Resume from last completed phase
-		for i, p := range allPhases {
-			if p == r.State.Auto.LastCompletedPhase {
-				startIdx = i + 1 // Start from next phase
-				break
-			}
+// requestApproval asks the Approver to decide on a gate. Without an
+// interactive approver, or for manual gates, it records a pending approval
+// and returns ErrApprovalPending so the run can be resumed after `forge approve`.
+func (r *AutoRunner) requestApproval(phase string, gate GateConfig) (ApprovalDecision, error) {
+	summary := GateSummary(phase)
+	fmt.Printf("  ⏸ Approval required %s phase %s\n", gate.When, phase)
+
+	if gate.Mode == GateConfirm && r.Approver != nil {
+		decision, err := r.Approver.RequestApproval(ApprovalRequest{
+			Phase:   phase,
+			When:    gate.When,
+			Summary: summary,
+		})
+		if err != nil {
+			return decision, fmt.Errorf("request approval for %s: %w", phase, err)
 		}
-		if startIdx >= len(allPhases) {
-			return nil, fmt.Errorf("all phases already completed")
+		err = r.update(func(s *ProjectState) {
+			s.AddActivity(fmt.Sprintf("Approval for %s (%s): %s", phase, gate.When, decision.Status))
+		})
+		if err != nil {
+			return decision, fmt.Errorf("save approval: %w", err)
 		}
+		return decision, nil
 	}
 
-	// Determine end phase
-	endIdx := len(allPhases) - 1
-	if until != "" {
-		if !IsValidPhase(until) {
-			return nil, fmt.Errorf("invalid end phase: %s", until)
+	fmt.Print(summary)
+
+	err := r.update(func(s *ProjectState) {
+		if s.Auto.Approvals == nil {
+			s.Auto.Approvals = make(map[string]*ApprovalState)
 		}
-		for i, p := range allPhases {
-			if p == until {
-				endIdx = i
-				break
+		if existing := s.Auto.Approvals[phase]; existing == nil || existing.Status != ApprovalPending {
+			s.Auto.Approvals[phase] = &ApprovalState{
+				When:        gate.When,
+				Status:      ApprovalPending,
+				RequestedAt: time.Now(),
 			}
+			s.AddActivity(fmt.Sprintf("Auto: waiting for approval %s phase %s", gate.When, phase))
 		}
+		s.Auto.CurrentPhaseStatus = "awaiting_approval"
+		s.SetPhaseStatus(phase, "awaiting_approval")
+	})
+	if err != nil {
+		return ApprovalDecision{}, fmt.Errorf("save pending approval: %w", err)
 	}
 
-	// Validate range
-	if startIdx > endIdx {
-		return nil, fmt.Errorf("start phase '%s' comes after end phase '%s'",
-			allPhases[startIdx], allPhases[endIdx])
+	return ApprovalDecision{}, fmt.Errorf("phase %s is %w: run 'forge approve %s', then 'forge auto' to continue",
+		phase, ErrApprovalPending, phase)
+}
+
+// rejectPhase stops the run after a reviewer rejected the phase
+func (r *AutoRunner) rejectPhase(phase string) error {
+	err := r.update(func(s *ProjectState) {
+		s.Auto.CurrentPhaseStatus = "rejected"
+		s.SetPhaseStatus(phase, "rejected")
+		s.AddActivity(fmt.Sprintf("Auto: phase %s rejected", phase))
+	})
+	if err != nil {
+		return fmt.Errorf("save rejection: %w", err)
 	}
+	return fmt.Errorf("phase %s was rejected", phase)
+}
 
-	return allPhases[startIdx : endIdx+1], nil
+// approvalFor returns the recorded approval for a phase, if any
+func (r *AutoRunner) approvalFor(phase string) *ApprovalState {
+	if r.State.Auto.Approvals == nil {
+		return nil
+	}
+	return r.State.Auto.Approvals[phase]
 }
 
-// GetResumeInfo returns information about resumable auto run
-func (r *AutoRunner) GetResumeInfo() (canResume bool, lastPhase string, nextPhase string) {
-	if r.State.Auto == nil || r.State.Auto.LastCompletedPhase == "" {
-		return false, "", PhaseNames()[0]
+// clearApproval removes a consumed approval decision
+func (r *AutoRunner) clearApproval(phase string) error {
+	return r.update(func(s *ProjectState) { delete(s.Auto.Approvals, phase) })
+}
+
+// execute runs the phase through the executor, if one is configured
+func (r *AutoRunner) execute(phase string, feedback PhaseFeedback) (*PhaseExecution, error) {
+	if r.Executor == nil {
+		// Default execution: just mark as needing manual run
+		fmt.Printf("  → Run: forge run (tool: %s)\n", GetDefaultTool(phase))
+		fmt.Printf("  → Artifacts: %v\n", GetPhase(phase).Artifacts)
+		return nil, nil
 	}
+	return r.Executor.Execute(phase, feedback)
+}
 
-	lastPhase = r.State.Auto.LastCompletedPhase
-	nextPhase = NextPhase(lastPhase)
-	canResume = nextPhase != ""
+// completePhase marks a phase as completed and records its history
+func (r *AutoRunner) completePhase(phase string, attempts []AttemptRecord) error {
+	err := r.update(func(s *ProjectState) {
+		s.Auto.LastCompletedPhase = phase
+		s.Auto.CurrentPhaseStatus = "completed"
+		s.SetPhaseStatus(phase, "completed")
+		s.AddActivity(fmt.Sprintf("Auto: completed phase %s", phase))
+	})
+	if err != nil {
+		return fmt.Errorf("save state after %s: %w", phase, err)
+	}
+	if attempts == nil {
+		attempts = r.State.Auto.Attempts[phase]
+	}
 
-	return canResume, lastPhase, nextPhase
+	// Write history first so it is part of the phase commit
+	var history *PhaseHistory
+	var historyPath string
+	if len(attempts) > 0 {
+		history, historyPath = r.saveHistory(phase, attempts, HistoryCompleted)
+	}
+	var commit string
+	if r.Git != nil {
+		sha, err := r.Git.CommitPhase(phase, "forge auto")
+		if err != nil {
+			fmt.Printf("Warning: failed to commit %s: %v\n", phase, err)
+		}
+		commit = sha
+	}
+	if commit != "" && history != nil {
+		// A commit cannot contain its own SHA, so it is recorded in the
+		// working copy and committed with the next phase
+		history.Commit = commit
+		if err := history.Save(historyPath); err != nil {
+			fmt.Printf("Warning: failed to record commit in history: %v\n", err)
+		}
+	}
+
+	fmt.Printf("  ✓ Phase %s completed\n", phase)
+	if commit != "" {
+		fmt.Printf("  ✓ Committed %s\n", ShortSHA(commit))
+	}
+	return nil
+}
+
+// archiveAttempts moves the artifacts of earlier attempts out of the
+// artifact directory and points their records at the archived copies
+func (r *AutoRunner) archiveAttempts(phase string, attempts []AttemptRecord) {
+	archived := make(map[int]string)
+	for i := range attempts {
+		artifact := attempts[i].Artifact
+		if artifact == "" || filepath.Dir(artifact) != ArtifactDir(phase) {
+			continue
+		}
+		path, err := ArchiveAttemptArtifact(phase, artifact)
+		if err != nil {
+			fmt.Printf("Warning: failed to archive %s: %v\n", artifact, err)
+			continue
+		}
+		attempts[i].Artifact = path
+		archived[attempts[i].Attempt] = path
+	}
+	if len(archived) == 0 {
+		return
+	}
+
+	err := r.update(func(s *ProjectState) {
+		for j, recorded := range s.Auto.Attempts[phase] {
+			if path, ok := archived[recorded.Attempt]; ok {
+				s.Auto.Attempts[phase][j].Artifact = path
+			}
+		}
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to record archived attempts: %v\n", err)
+	}
 }
 
-// parseValidationResult extracts AIValidationResult from AI output
-func parseValidationResult(output string) (*AIValidationResult, error) {
-	// Try to find JSON in the output (may be wrapped in markdown)
-	jsonStr := extractJSONFromOutput(output)
-	if jsonStr == "" {
-		// If no JSON found, treat as invalid with the output as feedback
-		return &AIValidationResult{
-			Valid:    false,
-			Feedback: "Could not parse validation response: " + output,
-		}, nil
+// recordAttempt stores an attempt in state; output is kept in history only
+func recordAttempt(s *ProjectState, phase string, record AttemptRecord) {
+	if s.Auto.Attempts == nil {
+		s.Auto.Attempts = make(map[string][]AttemptRecord)
 	}
+	record.Output = ""
+	s.Auto.Attempts[phase] = append(s.Auto.Attempts[phase], record)
+}
 
-	var result AIValidationResult
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-		return nil, fmt.Errorf("invalid JSON in validation response: %w", err)
+// saveHistory writes the phase history, including every attempt, next to the
+// state file. It returns the history and its path, or nil when saving failed.
+func (r *AutoRunner) saveHistory(phase string, attempts []AttemptRecord, outcome string) (*PhaseHistory, string) {
+	if err := os.MkdirAll(HistoryDir(r.StatePath), 0755); err != nil {
+		fmt.Printf("Warning: failed to create history directory: %v\n", err)
+		return nil, ""
 	}
 
-	return &result, nil
+	now := time.Now()
+	startedAt := r.State.PhaseStartedAt
+	if len(attempts) > 0 {
+		startedAt = attempts[0].StartedAt
+	}
+	history := PhaseHistory{
+		Phase:       phase,
+		StartedAt:   startedAt,
+		CompletedAt: now,
+		Duration:    now.Sub(startedAt),
+		Outcome:     outcome,
+		Notes:       "forge auto",
+		Attempts:    attempts,
+	}
+	path := NewHistoryPath(r.StatePath, phase, now)
+	if err := history.Save(path); err != nil {
+		fmt.Printf("Warning: failed to save history: %v\n", err)
+		return nil, ""
+	}
+	return &history, path
 }
 
-// extractJSONFromOutput extracts JSON from potentially markdown-wrapped output
-func extractJSONFromOutput(s string) string {
-	// Try to find JSON in code blocks first
-	codeBlockRegex := regexp.MustCompile("```(?:json)?\\s*([\\s\\S]*?)```")
-	matches := codeBlockRegex.FindStringSubmatch(s)
-	if len(matches) > 1 {
-		return strings.TrimSpace(matches[1])
-	}
-
-	// Try to find raw JSON object
-	s = strings.TrimSpace(s)
-	if strings.HasPrefix(s, "{") {
-		depth := 0
-		for i, c := range s {
-			if c == '{' {
-				depth++
-			} else if c == '}' {
-				depth--
-				if depth == 0 {
-					return s[:i+1]
-				}
+// getPhaseRange returns the phases between from and until (inclusive)
+// following the dependency graph rather than the AllPhases order, so a
+// range never pulls in phases that neither depend on from nor feed until
+func (r *AutoRunner) getPhaseRange(from, until string) ([]string, error) {
+	if from != "" && !IsValidPhase(from) {
+		return nil, fmt.Errorf("invalid start phase: %s", from)
+	}
+	if until != "" && !IsValidPhase(until) {
+		return nil, fmt.Errorf("invalid end phase: %s", until)
+	}
+
+	graph, err := r.Config.PhaseGraph()
+	if err != nil {
+		return nil, err
+	}
+	phases := graph.Range(from, until)
+	if len(phases) == 0 {
+		return nil, fmt.Errorf("end phase '%s' does not depend on start phase '%s'", until, from)
+	}
+
+	// Without an explicit start, resume by skipping completed phases. With
+	// concurrent waves, a later phase can finish before an earlier one fails.
+	if from == "" {
+		var remaining []string
+		for _, p := range phases {
+			if r.State.GetPhaseStatus(p) != "completed" {
+				remaining = append(remaining, p)
 			}
 		}
+		if len(remaining) == 0 {
+			return nil, fmt.Errorf("all phases already completed")
+		}
+		phases = remaining
 	}
 
-	return ""
+	return phases, nil
+}
+
+// GetResumeInfo returns information about resumable auto run
+func (r *AutoRunner) GetResumeInfo() (canResume bool, lastPhase string, nextPhase string) {
+	if r.State.Auto == nil || r.State.Auto.LastCompletedPhase == "" {
+		return false, "", PhaseNames()[0]
+	}
+
+	// The next phase is one whose dependencies are all completed; with
+	// concurrent waves that need not be the first pending phase in order
+	lastPhase = r.State.Auto.LastCompletedPhase
+	if graph, err := r.Config.PhaseGraph(); err == nil {
+		if ready := graph.Ready(r.State); len(ready) > 0 {
+			nextPhase = ready[0]
+		}
+	}
+	canResume = nextPhase != ""
+
+	return canResume, lastPhase, nextPhase
 }
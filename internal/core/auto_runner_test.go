@@ -0,0 +1,280 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockPhaseExecutor records the feedback it receives for each execution and,
+// with saveArtifacts, saves each output as an artifact like forge auto does
+type mockPhaseExecutor struct {
+	mu            sync.Mutex
+	feedback      []PhaseFeedback
+	saveArtifacts bool
+}
+
+func (m *mockPhaseExecutor) Execute(phase string, feedback PhaseFeedback) (*PhaseExecution, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.feedback = append(m.feedback, feedback)
+	execution := &PhaseExecution{Output: "output for " + phase}
+	if m.saveArtifacts {
+		path, err := SaveArtifact(phase, NewArtifactName(phase, "auto_mock", time.Now()), execution.Output)
+		if err != nil {
+			return nil, err
+		}
+		execution.Artifact = path
+	}
+	return execution, nil
+}
+
+// funcPhaseExecutor runs a function for each execution
+type funcPhaseExecutor func(phase string) error
+
+func (f funcPhaseExecutor) Execute(phase string, feedback PhaseFeedback) (*PhaseExecution, error) {
+	return &PhaseExecution{Output: "output for " + phase}, f(phase)
+}
+
+// sequenceValidator returns the given verdicts in order
+func sequenceValidator(verdicts ...string) *PhaseValidator {
+	i := 0
+	return &PhaseValidator{
+		ExecuteFunc: func(pattern, input string) (string, error) {
+			v := verdicts[i]
+			if i < len(verdicts)-1 {
+				i++
+			}
+			return v, nil
+		},
+	}
+}
+
+// seedDiscoveryArtifacts creates the files the discovery checkpoint checks for
+func seedDiscoveryArtifacts(t *testing.T) {
+	t.Helper()
+	chdirTemp(t)
+	for _, name := range []string{"requirements.md", "user_stories.md", "research_notes.md"} {
+		if _, err := SaveArtifact("discovery", name, "# "+name+"\n"); err != nil {
+			t.Fatalf("Failed to seed artifact: %v", err)
+		}
+	}
+}
+
+func newTestRunner(t *testing.T) *AutoRunner {
+	t.Helper()
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "state.yaml")
+	state := NewProjectState()
+	return NewAutoRunner(NewProjectConfig("test", ""), state, statePath)
+}
+
+func TestAutoRunnerRetriesWithFeedback(t *testing.T) {
+	seedDiscoveryArtifacts(t)
+	runner := newTestRunner(t)
+	executor := &mockPhaseExecutor{}
+	runner.Executor = executor
+	runner.MaxRetries = 2
+	runner.Validator = sequenceValidator(
+		`{"valid": false, "feedback": "missing user stories"}`,
+		passingVerdict("discovery"),
+	)
+
+	if err := runner.Run("discovery", "discovery", false); err != nil {
+		t.Fatalf("Expected run to succeed after retry, got %v", err)
+	}
+
+	if len(executor.feedback) != 2 {
+		t.Fatalf("Expected 2 executions, got %d", len(executor.feedback))
+	}
+	if !executor.feedback[0].IsZero() {
+		t.Errorf("Expected no feedback on first attempt, got %+v", executor.feedback[0])
+	}
+	if fb := executor.feedback[1]; fb.Source != FeedbackValidation || !strings.HasPrefix(fb.Text, "missing user stories") {
+		t.Errorf("Expected validation feedback on retry, got %+v", executor.feedback[1])
+	}
+
+	attempts := runner.State.Auto.Attempts["discovery"]
+	if len(attempts) != 2 {
+		t.Fatalf("Expected 2 recorded attempts, got %d", len(attempts))
+	}
+	if attempts[0].Verdict != "failed" || attempts[1].Verdict != "passed" {
+		t.Errorf("Expected verdicts failed, passed; got %s, %s", attempts[0].Verdict, attempts[1].Verdict)
+	}
+	if runner.State.GetPhaseStatus("discovery") != "completed" {
+		t.Errorf("Expected discovery to be completed, got %s", runner.State.GetPhaseStatus("discovery"))
+	}
+
+	// History includes the captured output of every attempt
+	entries, err := os.ReadDir(filepath.Join(filepath.Dir(runner.StatePath), "history"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Expected one history file, got %d (%v)", len(entries), err)
+	}
+	data, _ := os.ReadFile(filepath.Join(filepath.Dir(runner.StatePath), "history", entries[0].Name()))
+	if !strings.Contains(string(data), "output for discovery") {
+		t.Error("Expected history to contain attempt output")
+	}
+}
+
+func TestAutoRunnerArchivesRejectedAttempts(t *testing.T) {
+	seedDiscoveryArtifacts(t)
+	runner := newTestRunner(t)
+	runner.Executor = &mockPhaseExecutor{saveArtifacts: true}
+	runner.MaxRetries = 2
+	runner.Validator = sequenceValidator(
+		`{"valid": false, "feedback": "missing user stories"}`,
+		passingVerdict("discovery"),
+	)
+
+	if err := runner.Run("discovery", "discovery", false); err != nil {
+		t.Fatalf("Expected run to succeed after retry, got %v", err)
+	}
+
+	attempts := runner.State.Auto.Attempts["discovery"]
+	if len(attempts) != 2 {
+		t.Fatalf("Expected 2 recorded attempts, got %d", len(attempts))
+	}
+	// Both attempts ran within the same second and must not share a name
+	if filepath.Base(attempts[0].Artifact) == filepath.Base(attempts[1].Artifact) {
+		t.Errorf("Expected unique artifact names, got %s twice", filepath.Base(attempts[0].Artifact))
+	}
+	if filepath.Dir(attempts[0].Artifact) != AttemptArchiveDir("discovery") {
+		t.Errorf("Expected the rejected attempt to be archived, got %s", attempts[0].Artifact)
+	}
+	if _, err := os.Stat(attempts[0].Artifact); err != nil {
+		t.Errorf("Expected the archived artifact to exist: %v", err)
+	}
+	if filepath.Dir(attempts[1].Artifact) != ArtifactDir("discovery") {
+		t.Errorf("Expected the accepted attempt to stay with the artifacts, got %s", attempts[1].Artifact)
+	}
+
+	artifacts, err := ReadArtifacts("discovery", 0)
+	if err != nil {
+		t.Fatalf("Failed to read artifacts: %v", err)
+	}
+	count := 0
+	for _, a := range artifacts {
+		if strings.HasPrefix(a.Name, "auto_mock_") {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("Expected only the latest attempt's output among the artifacts, got %d", count)
+	}
+}
+
+func TestAutoRunnerKeepsConcurrentStateUpdates(t *testing.T) {
+	seedDiscoveryArtifacts(t)
+	runner := newTestRunner(t)
+	runner.Config.Gates = nil
+	if err := runner.State.Save(runner.StatePath); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+
+	// Another forge command updates the state while the phase runs
+	runner.Executor = funcPhaseExecutor(func(phase string) error {
+		_, err := UpdateProjectState(runner.StatePath, func(s *ProjectState) error {
+			s.SetPhaseStatus("deployment", "completed")
+			s.AddActivity("Completed phase: deployment")
+			return nil
+		})
+		return err
+	})
+
+	if err := runner.Run("discovery", "discovery", true); err != nil {
+		t.Fatalf("Expected run to succeed, got %v", err)
+	}
+
+	state, err := LoadProjectState(runner.StatePath)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	if state.GetPhaseStatus("discovery") != "completed" {
+		t.Errorf("Expected discovery to be completed, got %s", state.GetPhaseStatus("discovery"))
+	}
+	if state.GetPhaseStatus("deployment") != "completed" {
+		t.Errorf("Expected the concurrent update to be kept, got deployment %s", state.GetPhaseStatus("deployment"))
+	}
+}
+
+func TestAutoRunnerStopsAfterMaxRetries(t *testing.T) {
+	seedDiscoveryArtifacts(t)
+	runner := newTestRunner(t)
+	executor := &mockPhaseExecutor{}
+	runner.Executor = executor
+	runner.MaxRetries = 1
+	runner.Validator = sequenceValidator(`{"valid": false, "feedback": "still incomplete"}`)
+
+	err := runner.Run("discovery", "discovery", false)
+	if err == nil {
+		t.Fatal("Expected run to fail when validation never passes")
+	}
+	if len(executor.feedback) != 2 {
+		t.Errorf("Expected 2 executions, got %d", len(executor.feedback))
+	}
+	if runner.State.GetPhaseStatus("discovery") != "validation_failed" {
+		t.Errorf("Expected validation_failed status, got %s", runner.State.GetPhaseStatus("discovery"))
+	}
+	if runner.State.Auto.LastCompletedPhase == "discovery" {
+		t.Error("Expected failed phase not to be marked as last completed")
+	}
+}
+
+func TestAutoRunnerRunsWaveConcurrently(t *testing.T) {
+	runner := newTestRunner(t)
+	runner.Config.Gates = nil
+	for _, p := range []string{"discovery", "planning", "design", "implementation"} {
+		runner.State.SetPhaseStatus(p, "completed")
+	}
+	executor := &mockPhaseExecutor{}
+	runner.Executor = executor
+
+	waves, err := runner.Plan("", "")
+	if err != nil {
+		t.Fatalf("Expected no error planning, got %v", err)
+	}
+	if len(waves) != 1 || len(waves[0]) != 2 {
+		t.Fatalf("Expected testing and deployment in one wave, got %v", waves)
+	}
+
+	if err := runner.Run("", "", true); err != nil {
+		t.Fatalf("Expected run to succeed, got %v", err)
+	}
+	for _, p := range []string{"testing", "deployment"} {
+		if runner.State.GetPhaseStatus(p) != "completed" {
+			t.Errorf("Expected %s to be completed, got %s", p, runner.State.GetPhaseStatus(p))
+		}
+	}
+}
+
+func TestAutoRunnerRangeFollowsDependencies(t *testing.T) {
+	runner := newTestRunner(t)
+
+	waves, err := runner.Plan("testing", "")
+	if err != nil {
+		t.Fatalf("Expected no error planning, got %v", err)
+	}
+	if FormatWaves(waves) != "testing" {
+		t.Errorf("Expected deployment, which does not depend on testing, to be left out; got %s", FormatWaves(waves))
+	}
+
+	if _, err := runner.Plan("testing", "deployment"); err == nil {
+		t.Error("Expected an error for an end phase that does not depend on the start phase")
+	}
+}
+
+func TestAutoRunnerResumesFromReadyPhase(t *testing.T) {
+	runner := newTestRunner(t)
+	// planning waits for design, which only needs discovery
+	runner.Config.DependsOn = map[string][]string{"planning": {"design"}, "design": {"discovery"}}
+	runner.State.SetPhaseStatus("discovery", "completed")
+	runner.State.Auto = &AutoState{LastCompletedPhase: "discovery"}
+
+	canResume, last, next := runner.GetResumeInfo()
+	if !canResume || last != "discovery" || next != "design" {
+		t.Errorf("Expected to resume at design after discovery, got %v, %s, %s", canResume, last, next)
+	}
+}
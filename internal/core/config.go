@@ -1,22 +1,31 @@
 package core
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // ProjectConfig represents the forge project configuration
 type ProjectConfig struct {
-	Name        string            `yaml:"name"`
-	Description string            `yaml:"description,omitempty"`
-	Template    string            `yaml:"template,omitempty"`
-	Version     string            `yaml:"version"`
-	Tools       ToolsConfig       `yaml:"tools"`
-	Phases      map[string]string `yaml:"phases,omitempty"` // phase -> custom tool override
-	Patterns    PatternsConfig    `yaml:"patterns"`
-	Sessions    SessionsConfig    `yaml:"sessions"`
+	SchemaVersion int `yaml:"schema_version"`
+
+	Name         string                `yaml:"name"`
+	Description  string                `yaml:"description,omitempty"`
+	Template     string                `yaml:"template,omitempty"`
+	Version      string                `yaml:"version"`
+	Tools        ToolsConfig           `yaml:"tools"`
+	Phases       map[string]string     `yaml:"phases,omitempty"`     // phase -> custom tool override
+	Gates        map[string]GateConfig `yaml:"gates,omitempty"`      // phase -> approval gate for forge auto
+	DependsOn    map[string][]string   `yaml:"depends_on,omitempty"` // phase -> dependency override
+	Patterns     PatternsConfig        `yaml:"patterns"`
+	Sessions     SessionsConfig        `yaml:"sessions"`
+	Integrations IntegrationsConfig    `yaml:"integrations,omitempty"`
+	Serve        ServeConfig           `yaml:"serve,omitempty"`
+	Advanced     AdvancedConfig        `yaml:"advanced,omitempty"`
 }
 
 // ToolsConfig holds configuration for AI tools
@@ -79,15 +88,80 @@ type SessionsConfig struct {
 	PersistState bool   `yaml:"persist_state"`
 }
 
+// IntegrationsConfig configures integrations with external tools
+type IntegrationsConfig struct {
+	Git GitConfig `yaml:"git,omitempty"`
+}
+
+// GitConfig configures committing forge changes on phase completion
+type GitConfig struct {
+	AutoCommit     bool   `yaml:"auto_commit"`
+	CommitTemplate string `yaml:"commit_template,omitempty"` // text/template with .Phase, .Project and .Notes
+	Tag            bool   `yaml:"tag,omitempty"`             // Tag completions as forge/<phase>
+
+	// Context passes local changes to the implementation phase tool
+	Context GitContextConfig `yaml:"context,omitempty"`
+}
+
+// GitContextConfig selects git content with the same sources as
+// fabric-lite run --git-diff, --staged and --git-log
+type GitContextConfig struct {
+	Diff     string   `yaml:"diff,omitempty"`      // Commit or range to diff against, e.g. HEAD
+	Staged   bool     `yaml:"staged,omitempty"`    // Include staged changes
+	Log      string   `yaml:"log,omitempty"`       // Log range, e.g. main..HEAD
+	Paths    []string `yaml:"paths,omitempty"`     // Pathspecs limiting the output
+	MaxBytes int      `yaml:"max_bytes,omitempty"` // Default: DefaultGitInputLimit
+}
+
+// Source converts the context settings to a GitSource
+func (c GitContextConfig) Source() GitSource {
+	src := GitSource{Diff: c.Diff, Staged: c.Staged, Log: c.Log, Paths: c.Paths, MaxBytes: c.MaxBytes}
+	if src.MaxBytes <= 0 {
+		src.MaxBytes = DefaultGitInputLimit
+	}
+	return src
+}
+
+// ServeConfig configures the OpenAI-compatible routes of fabric-lite serve
+type ServeConfig struct {
+	// Models maps model names clients send to "provider/model" targets
+	Models map[string]string `yaml:"models,omitempty"`
+
+	// Fallback lists providers (or "provider/model") tried in order when
+	// the routed provider fails
+	Fallback []string `yaml:"fallback,omitempty"`
+}
+
+// AdvancedConfig holds execution tuning options
+type AdvancedConfig struct {
+	ToolTimeout int `yaml:"tool_timeout,omitempty"` // Seconds per tool execution
+	MaxRetries  int `yaml:"max_retries,omitempty"`  // Extra attempts when validation fails
+
+	// CostPer1KTokens prices tool token usage in forge report
+	CostPer1KTokens float64 `yaml:"cost_per_1k_tokens,omitempty"`
+}
+
+// DefaultToolTimeout is used when advanced.tool_timeout is not configured
+const DefaultToolTimeout = 300 * time.Second
+
+// ToolTimeoutDuration returns the configured tool timeout
+func (a AdvancedConfig) ToolTimeoutDuration() time.Duration {
+	if a.ToolTimeout <= 0 {
+		return DefaultToolTimeout
+	}
+	return time.Duration(a.ToolTimeout) * time.Second
+}
+
 // NewProjectConfig creates a new project configuration
 func NewProjectConfig(name, template string) *ProjectConfig {
 	homeDir, _ := os.UserHomeDir()
 	configDir := filepath.Join(homeDir, ".config", "fabric-lite")
 
 	return &ProjectConfig{
-		Name:     name,
-		Template: template,
-		Version:  "1.0.0",
+		SchemaVersion: ConfigSchemaVersion,
+		Name:          name,
+		Template:      template,
+		Version:       "1.0.0",
 		Tools: ToolsConfig{
 			Gemini: GeminiConfig{
 				Model:   "gemini-2.0-flash-exp",
@@ -130,6 +204,10 @@ func NewProjectConfig(name, template string) *ProjectConfig {
 			MaxHistory:   100,
 			PersistState: true,
 		},
+		Gates: map[string]GateConfig{
+			"implementation": {Mode: GateConfirm, When: GateAfter},
+			"deployment":     {Mode: GateConfirm, When: GateBefore},
+		},
 	}
 }
 
@@ -149,10 +227,43 @@ func LoadProjectConfig(path string) (*ProjectConfig, error) {
 		return nil, err
 	}
 
+	// Older configs are upgraded in memory; the file keeps its comments
 	var cfg ProjectConfig
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	if _, err := decodeVersioned("config", data, ConfigSchemaVersion, configMigrations, &cfg); err != nil {
 		return nil, err
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
 	return &cfg, nil
 }
+
+// Validate checks phase names used in the config against the phase registry
+func (c *ProjectConfig) Validate() error {
+	for phase := range c.Phases {
+		if !IsValidPhase(phase) {
+			return fmt.Errorf("phases: unknown phase %q", phase)
+		}
+	}
+	for phase := range c.Gates {
+		if !IsValidPhase(phase) {
+			return fmt.Errorf("gates: unknown phase %q", phase)
+		}
+	}
+
+	// Catch dependency cycles at load time rather than mid-run
+	if _, err := c.PhaseGraph(); err != nil {
+		return fmt.Errorf("invalid depends_on: %w", err)
+	}
+	return nil
+}
+
+// PhaseGraph returns the phase dependency graph with config overrides applied
+func (c *ProjectConfig) PhaseGraph() (*PhaseGraph, error) {
+	if c == nil {
+		return NewPhaseGraph(nil)
+	}
+	return NewPhaseGraph(c.DependsOn)
+}
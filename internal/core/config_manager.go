@@ -13,20 +13,21 @@ import (
 
 var (
 	defaultConfig *ProjectConfig
-	configOnce    sync.Once
+	configMutex   sync.RWMutex
 )
 
-// SetDefaultConfig sets the global Config instance.
-// This should only be called once during application initialization.
+// SetDefaultConfig sets the global Config instance, replacing any earlier one
 func SetDefaultConfig(cfg *ProjectConfig) {
-	configOnce.Do(func() {
-		defaultConfig = cfg
-	})
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	defaultConfig = cfg
 }
 
 // GetDefaultConfig returns the global Config instance.
 // Panics if the config has not been set.
 func GetDefaultConfig() *ProjectConfig {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
 	if defaultConfig == nil {
 		panic("Default Config has not been initialized. Call SetDefaultConfig first.")
 	}
@@ -75,7 +76,33 @@ func (cm *ConfigManager) Load() (*ProjectConfig, error) {
 	cm.loaded = true
 
 	// Initialize and set the global ProviderManager
-	// We need to construct a providers.Config from ProjectConfig for ProviderManager
+	providerCfg := ProvidersFromConfig(config)
+	fileCfg, err := providers.LoadConfig(cm.ProvidersPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", cm.ProvidersPath(), err)
+	}
+	MergeProviders(providerCfg, fileCfg.Providers)
+
+	pm := NewProviderManager(providerCfg)
+	if err := pm.InitializeAll(); err != nil {
+		return nil, fmt.Errorf("failed to initialize providers: %w", err)
+	}
+	SetDefaultProviderManager(pm)
+	SetDefaultConfig(config) // Set global config after successful load and PM init
+
+	return config, nil
+}
+
+// providerTools maps the providers built from tool settings to the tools
+// section that enables them
+var providerTools = map[string]string{
+	"ollama":    "ollama",
+	"anthropic": "claude",
+}
+
+// ProvidersFromConfig builds the provider configuration for the enabled
+// tools of a ProjectConfig
+func ProvidersFromConfig(config *ProjectConfig) *providers.Config {
 	providerCfg := &providers.Config{
 		DefaultProvider: "ollama", // Default to ollama if not specified
 		Providers:       []providers.ProviderConfig{},
@@ -92,16 +119,7 @@ func (cm *ConfigManager) Load() (*ProjectConfig, error) {
 			},
 		})
 	}
-	if config.Tools.Gemini.Enabled {
-		providerCfg.Providers = append(providerCfg.Providers, providers.ProviderConfig{
-			Name: "gemini",
-			Type: "gemini", // Assuming a gemini provider type exists
-			Config: map[string]any{
-				"api_key": config.Tools.Gemini.APIKey,
-				"model":   config.Tools.Gemini.Model,
-			},
-		})
-	}
+	// tools.gemini runs the gemini CLI; there is no gemini provider
 	if config.Tools.Claude.Enabled {
 		providerCfg.Providers = append(providerCfg.Providers, providers.ProviderConfig{
 			Name: "anthropic", // Claude tool uses anthropic provider type
@@ -114,15 +132,36 @@ func (cm *ConfigManager) Load() (*ProjectConfig, error) {
 			},
 		})
 	}
+	return providerCfg
+}
 
-	pm := NewProviderManager(providerCfg)
-	if err := pm.InitializeAll(); err != nil {
-		return nil, fmt.Errorf("failed to initialize providers: %w", err)
+// MergeProviders adds providers to cfg; one with the name of a provider
+// already in cfg replaces it
+func MergeProviders(cfg *providers.Config, extra []providers.ProviderConfig) {
+	for _, pc := range extra {
+		replaced := false
+		for i := range cfg.Providers {
+			if cfg.Providers[i].Name == pc.Name {
+				cfg.Providers[i] = pc
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			cfg.Providers = append(cfg.Providers, pc)
+		}
 	}
-	SetDefaultProviderManager(pm)
-	SetDefaultConfig(config) // Set global config after successful load and PM init
+}
 
-	return config, nil
+// Path returns the main config file path
+func (cm *ConfigManager) Path() string {
+	return cm.configPath
+}
+
+// ProvidersPath returns the providers.yaml file next to the main config.
+// Its providers are added to those built from the tools settings.
+func (cm *ConfigManager) ProvidersPath() string {
+	return filepath.Join(filepath.Dir(cm.configPath), "providers.yaml")
 }
 
 // loadMainConfig loads from the unified config.yaml file
@@ -135,6 +174,18 @@ func (cm *ConfigManager) loadMainConfig() (*ProjectConfig, error) {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
+	// Tool and provider names depend on what is registered, so only
+	// 'fabric-lite doctor' checks them
+	var problems []string
+	for _, issue := range ValidateConfig(data, ConfigNames{}) {
+		if issue.Severity == IssueError {
+			problems = append(problems, issue.String())
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid config %s:\n  %s", cm.configPath, strings.Join(problems, "\n  "))
+	}
+
 	var config ProjectConfig
 	err = yaml.Unmarshal(data, &config)
 	if err != nil {
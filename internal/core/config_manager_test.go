@@ -3,26 +3,22 @@ package core
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
-
-	"github.com/rice0649/fabric-lite/internal/providers"
 )
 
 func TestNewConfigManager(t *testing.T) {
 	tests := []struct {
 		name       string
 		configPath string
-		wantPath   bool
 	}{
 		{
 			name:       "with custom path",
 			configPath: "/custom/path/config.yaml",
-			wantPath:   true,
 		},
 		{
 			name:       "with empty path uses default",
 			configPath: "",
-			wantPath:   true,
 		},
 	}
 
@@ -32,30 +28,25 @@ func TestNewConfigManager(t *testing.T) {
 			if cm == nil {
 				t.Fatal("NewConfigManager returned nil")
 			}
-			if tt.configPath != "" && cm.configPath != tt.configPath {
-				t.Errorf("configPath = %v, want %v", cm.configPath, tt.configPath)
+			if tt.configPath != "" && cm.Path() != tt.configPath {
+				t.Errorf("Path() = %v, want %v", cm.Path(), tt.configPath)
 			}
-			if tt.configPath == "" && cm.configPath == "" {
-				t.Error("configPath should have default value")
+			if tt.configPath == "" && filepath.Base(cm.Path()) != "config.yaml" {
+				t.Errorf("Path() = %v, want the default config.yaml", cm.Path())
 			}
 		})
 	}
 }
 
 func TestConfigManager_Load(t *testing.T) {
-	// Create a temporary directory for test configs
 	tmpDir := t.TempDir()
 
-	// Create a valid config file
 	validConfig := `
-default_provider: openai
-providers:
-  - name: openai
-    type: http
-    config:
-      endpoint: https://api.openai.com/v1/chat/completions
-      api_key_env: OPENAI_API_KEY
-      model: gpt-4o-mini
+name: demo
+tools:
+  ollama:
+    enabled: true
+    model: qwen2.5
 patterns:
   directories:
     - ./patterns
@@ -72,57 +63,66 @@ sessions:
 		cm := NewConfigManager(validConfigPath)
 		config, err := cm.Load()
 		if err != nil {
-			t.Errorf("Load() error = %v", err)
-			return
+			t.Fatalf("Load() error = %v", err)
+		}
+		if config.Name != "demo" {
+			t.Errorf("Name = %v, want demo", config.Name)
 		}
-		if config == nil {
-			t.Error("Load() returned nil config without error")
+		if config.Tools.Ollama.Model != "qwen2.5" || config.Tools.Ollama.Endpoint != "http://localhost:11434" {
+			t.Errorf("Expected the configured model and the default endpoint, got %+v", config.Tools.Ollama)
 		}
-		if config.DefaultProvider != "openai" {
-			t.Errorf("DefaultProvider = %v, want openai", config.DefaultProvider)
+		if config.Sessions.MaxHistory != 50 {
+			t.Errorf("Sessions.MaxHistory = %v, want 50", config.Sessions.MaxHistory)
 		}
 	})
 
-	t.Run("load non-existent config falls back or errors", func(t *testing.T) {
-		// Use a path where there's definitely no providers.yaml fallback
-		isolatedDir := t.TempDir()
-		nonExistentPath := filepath.Join(isolatedDir, "subdir", "nonexistent.yaml")
-		cm := NewConfigManager(nonExistentPath)
-		_, err := cm.Load()
-		// ConfigManager may fall back to legacy config or error - both are valid
-		// We just verify it doesn't panic
-		_ = err
+	t.Run("load non-existent config uses defaults", func(t *testing.T) {
+		cm := NewConfigManager(filepath.Join(t.TempDir(), "subdir", "nonexistent.yaml"))
+		config, err := cm.Load()
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if config.Name != "default-project" {
+			t.Errorf("Name = %v, want default-project", config.Name)
+		}
 	})
 }
 
-func TestConfigManager_LoadCached(t *testing.T) {
-	tmpDir := t.TempDir()
-	configPath := filepath.Join(tmpDir, "config.yaml")
+func TestConfigManager_LoadInvalid(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	data := "name: demo\nsessions:\n  max_history: lots\nphases:\n  shipping: codex\nunknown_key: true\n"
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
 
-	// Create config
-	config := `
-default_provider: test
-providers: []
-`
-	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+	_, err := NewConfigManager(configPath).Load()
+	if err == nil {
+		t.Fatal("Expected an error for an invalid config")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, configPath) || !strings.Contains(msg, "line 3: sessions.max_history") {
+		t.Errorf("Expected the file and line of the bad value, got %q", msg)
+	}
+	if strings.Contains(msg, "unknown_key") {
+		t.Errorf("Expected warnings not to fail the load, got %q", msg)
+	}
+}
+
+func TestConfigManager_LoadCached(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("name: cached\n"), 0644); err != nil {
 		t.Fatalf("Failed to write test config: %v", err)
 	}
 
 	cm := NewConfigManager(configPath)
-
-	// First load
 	cfg1, err := cm.Load()
 	if err != nil {
 		t.Fatalf("First Load() failed: %v", err)
 	}
-
-	// Second load should return cached
 	cfg2, err := cm.Load()
 	if err != nil {
 		t.Fatalf("Second Load() failed: %v", err)
 	}
-
-	// Should be the same pointer
 	if cfg1 != cfg2 {
 		t.Error("Second Load() should return cached config")
 	}
@@ -130,12 +130,12 @@ providers: []
 
 func TestConfigManager_ApplyDefaults(t *testing.T) {
 	cm := &ConfigManager{}
-	config := &providers.Config{}
+	config := &ProjectConfig{}
 
 	cm.applyDefaults(config)
 
-	if config.DefaultProvider != "openai" {
-		t.Errorf("DefaultProvider = %v, want openai", config.DefaultProvider)
+	if config.Name != "default-project" || config.Version != "1.0.0" {
+		t.Errorf("Expected the default name and version, got %q and %q", config.Name, config.Version)
 	}
 	if len(config.Patterns.Directories) == 0 {
 		t.Error("Patterns.Directories should have default values")
@@ -146,196 +146,113 @@ func TestConfigManager_ApplyDefaults(t *testing.T) {
 	if config.Sessions.MaxHistory != 100 {
 		t.Errorf("Sessions.MaxHistory = %v, want 100", config.Sessions.MaxHistory)
 	}
+	if config.Tools.Codex.Provider != "ollama" {
+		t.Errorf("Tools.Codex.Provider = %v, want ollama", config.Tools.Codex.Provider)
+	}
 }
 
-func TestConfigManager_GetProvider(t *testing.T) {
-	tmpDir := t.TempDir()
-	configPath := filepath.Join(tmpDir, "config.yaml")
+func TestProvidersFromConfig(t *testing.T) {
+	config := NewProjectConfig("demo", "")
+	config.Tools.Gemini.Enabled = false
+	config.Tools.Ollama.Enabled = true
+	config.Tools.Claude.Enabled = true
 
-	config := `
-default_provider: openai
-providers:
-  - name: openai
-    type: http
-    config:
-      model: gpt-4o
-  - name: anthropic
-    type: anthropic
-    config:
-      model: claude-3
-`
-	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
-		t.Fatalf("Failed to write test config: %v", err)
+	providerCfg := ProvidersFromConfig(config)
+	if len(providerCfg.Providers) != 2 {
+		t.Fatalf("Expected ollama and anthropic, got %+v", providerCfg.Providers)
 	}
-
-	cm := NewConfigManager(configPath)
-
-	tests := []struct {
-		name         string
-		providerName string
-		wantErr      bool
-	}{
-		{
-			name:         "existing provider",
-			providerName: "openai",
-			wantErr:      false,
-		},
-		{
-			name:         "another existing provider",
-			providerName: "anthropic",
-			wantErr:      false,
-		},
-		{
-			name:         "non-existent provider",
-			providerName: "notfound",
-			wantErr:      true,
-		},
+	if p := providerCfg.Providers[0]; p.Name != "ollama" || p.Config["endpoint"] != "http://localhost:11434" {
+		t.Errorf("Unexpected ollama provider: %+v", p)
 	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			provider, err := cm.GetProvider(tt.providerName)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("GetProvider() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !tt.wantErr && provider == nil {
-				t.Error("GetProvider() returned nil without error")
-			}
-			if !tt.wantErr && provider.Name != tt.providerName {
-				t.Errorf("GetProvider() name = %v, want %v", provider.Name, tt.providerName)
-			}
-		})
+	if p := providerCfg.Providers[1]; p.Name != "anthropic" || p.Type != "anthropic" || p.Config["max_tokens"] != 4096 {
+		t.Errorf("Unexpected anthropic provider: %+v", p)
 	}
 }
 
-func TestConfigManager_GetDefaultProvider(t *testing.T) {
-	tmpDir := t.TempDir()
-	configPath := filepath.Join(tmpDir, "config.yaml")
-
-	config := `
-default_provider: anthropic
-providers: []
-`
-	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+func TestConfigManager_LoadProvidersFile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("tools:\n  ollama:\n    enabled: true\n"), 0644); err != nil {
 		t.Fatalf("Failed to write test config: %v", err)
 	}
-
-	cm := NewConfigManager(configPath)
-	defaultProvider, err := cm.GetDefaultProvider()
-	if err != nil {
-		t.Fatalf("GetDefaultProvider() failed: %v", err)
-	}
-	if defaultProvider != "anthropic" {
-		t.Errorf("GetDefaultProvider() = %v, want anthropic", defaultProvider)
-	}
-}
-
-func TestConfigManager_GetPatternPaths(t *testing.T) {
-	tmpDir := t.TempDir()
-	configPath := filepath.Join(tmpDir, "config.yaml")
-
-	config := `
-default_provider: openai
-providers: []
-patterns:
-  directories:
-    - /path/one
-    - /path/two
+	providersYAML := `providers:
+  - name: ollama
+    type: ollama
+    config:
+      endpoint: http://gpu-box:11434
+  - name: local
+    type: openai
+    config:
+      endpoint: http://127.0.0.1:1/v1/chat/completions
+      auth: none
 `
-	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
-		t.Fatalf("Failed to write test config: %v", err)
+	if err := os.WriteFile(filepath.Join(dir, "providers.yaml"), []byte(providersYAML), 0644); err != nil {
+		t.Fatalf("Failed to write providers file: %v", err)
 	}
 
-	cm := NewConfigManager(configPath)
-	paths, err := cm.GetPatternPaths()
-	if err != nil {
-		t.Fatalf("GetPatternPaths() failed: %v", err)
+	if _, err := NewConfigManager(configPath).Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	pm := GetDefaultProviderManager()
+	if _, err := pm.Get("local"); err != nil {
+		t.Errorf("Expected the provider from providers.yaml, got %v", err)
 	}
-	if len(paths) != 2 {
-		t.Errorf("GetPatternPaths() returned %d paths, want 2", len(paths))
+	pc, err := pm.GetConfigForProvider("ollama")
+	if err != nil || pc.Config["endpoint"] != "http://gpu-box:11434" {
+		t.Errorf("Expected providers.yaml to replace the ollama provider, got %+v, %v", pc, err)
 	}
 }
 
-func TestConfigManager_GetSessionConfig(t *testing.T) {
-	tmpDir := t.TempDir()
-	configPath := filepath.Join(tmpDir, "config.yaml")
-
-	config := `
-default_provider: openai
-providers: []
-sessions:
-  directory: /sessions
-  max_history: 200
-  persist_state: true
-`
-	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
-		t.Fatalf("Failed to write test config: %v", err)
+func TestConfigManager_LoadUnknownProviderType(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	providersYAML := "providers:\n  - name: mystery\n    type: nosuchtype\n"
+	if err := os.WriteFile(filepath.Join(dir, "providers.yaml"), []byte(providersYAML), 0644); err != nil {
+		t.Fatalf("Failed to write providers file: %v", err)
 	}
 
-	cm := NewConfigManager(configPath)
-	sessionConfig, err := cm.GetSessionConfig()
-	if err != nil {
-		t.Fatalf("GetSessionConfig() failed: %v", err)
-	}
-	if sessionConfig.MaxHistory != 200 {
-		t.Errorf("MaxHistory = %v, want 200", sessionConfig.MaxHistory)
+	_, err := NewConfigManager(configPath).Load()
+	if err == nil || !strings.Contains(err.Error(), "provider mystery: unknown provider type: nosuchtype") {
+		t.Errorf("Expected the unknown provider type to fail the load, got %v", err)
 	}
 }
 
 func TestConfigManager_Save(t *testing.T) {
-	tmpDir := t.TempDir()
-	configPath := filepath.Join(tmpDir, "newconfig.yaml")
+	configPath := filepath.Join(t.TempDir(), "nested", "newconfig.yaml")
 
 	cm := NewConfigManager(configPath)
-	cm.config = &providers.Config{
-		DefaultProvider: "test",
-		Providers:       []providers.ProviderConfig{},
+	if err := cm.Save(NewProjectConfig("saved", "")); err != nil {
+		t.Fatalf("Save() failed: %v", err)
 	}
-	cm.loaded = true
 
-	err := cm.Save()
+	loaded, err := NewConfigManager(configPath).loadMainConfig()
 	if err != nil {
-		t.Fatalf("Save() failed: %v", err)
+		t.Fatalf("Failed to load saved config: %v", err)
 	}
-
-	// Verify file was created
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		t.Error("Save() did not create config file")
+	if loaded.Name != "saved" {
+		t.Errorf("Name = %v, want saved", loaded.Name)
 	}
 }
 
 func TestConfigManager_SaveNoConfig(t *testing.T) {
-	cm := NewConfigManager("/tmp/test.yaml")
-	err := cm.Save()
-	if err == nil {
-		t.Error("Save() should fail with no config loaded")
+	cm := NewConfigManager(filepath.Join(t.TempDir(), "config.yaml"))
+	if err := cm.Save(nil); err == nil {
+		t.Error("Save() should fail without a config")
 	}
 }
 
 func TestConfigManager_CreateDefaultConfig(t *testing.T) {
-	tmpDir := t.TempDir()
-	configPath := filepath.Join(tmpDir, "default.yaml")
+	configPath := filepath.Join(t.TempDir(), "default.yaml")
 
 	cm := NewConfigManager(configPath)
-	err := cm.CreateDefaultConfig()
-	if err != nil {
+	if err := cm.CreateDefaultConfig(); err != nil {
 		t.Fatalf("CreateDefaultConfig() failed: %v", err)
 	}
 
-	// Verify file was created
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		t.Error("CreateDefaultConfig() did not create config file")
 	}
-
-	// Verify config was loaded
-	if cm.config == nil {
-		t.Error("CreateDefaultConfig() did not set config")
-	}
-	if cm.config.DefaultProvider != "openai" {
-		t.Errorf("DefaultProvider = %v, want openai", cm.config.DefaultProvider)
-	}
-	if len(cm.config.Providers) != 3 {
-		t.Errorf("Providers count = %v, want 3", len(cm.config.Providers))
+	if cm.config == nil || cm.config.Name != "default-project" {
+		t.Errorf("CreateDefaultConfig() did not set the default config, got %+v", cm.config)
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestNewProjectConfig(t *testing.T) {
@@ -268,3 +269,12 @@ func TestProjectConfigWithDefaults(t *testing.T) {
 		t.Error("Expected phases map to be initialized, got nil")
 	}
 }
+
+func TestAdvancedConfigToolTimeout(t *testing.T) {
+	if got := (AdvancedConfig{}).ToolTimeoutDuration(); got != DefaultToolTimeout {
+		t.Errorf("Expected default timeout %s, got %s", DefaultToolTimeout, got)
+	}
+	if got := (AdvancedConfig{ToolTimeout: 30}).ToolTimeoutDuration(); got != 30*time.Second {
+		t.Errorf("Expected 30s timeout, got %s", got)
+	}
+}
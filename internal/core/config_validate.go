@@ -0,0 +1,337 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigIssue is a problem found in a config file. Line is 0 when the
+// problem has no single location, e.g. a dependency cycle.
+type ConfigIssue struct {
+	Line     int    `json:"line,omitempty" yaml:"line,omitempty"`
+	Path     string `json:"path,omitempty" yaml:"path,omitempty"` // Dotted key, e.g. tools.codex.provider
+	Severity string `json:"severity" yaml:"severity"`
+	Message  string `json:"message" yaml:"message"`
+}
+
+// String formats the issue as "line N: path: message"
+func (i ConfigIssue) String() string {
+	msg := i.Message
+	if i.Path != "" {
+		msg = i.Path + ": " + msg
+	}
+	if i.Line > 0 {
+		msg = fmt.Sprintf("line %d: %s", i.Line, msg)
+	}
+	return msg
+}
+
+// ConfigNames lists the names a config may refer to. A nil list skips
+// the corresponding check.
+type ConfigNames struct {
+	Tools     []string // Tools phases may be assigned to
+	Providers []string // Providers tools and serve routes may use
+}
+
+// ValidateConfigFile checks a config.yaml or .forge/config.yaml file. The
+// error is only set when the file cannot be read.
+func ValidateConfigFile(path string, names ConfigNames) ([]ConfigIssue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateConfig(data, names), nil
+}
+
+// ValidateConfig checks a config document against the ProjectConfig schema.
+// Unknown keys are warnings since they are ignored when loading; bad
+// types, unknown phases and names not in names are errors.
+func ValidateConfig(data []byte, names ConfigNames) []ConfigIssue {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []ConfigIssue{{Severity: IssueError, Message: err.Error()}}
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	v := &configValidator{keys: make(map[string]*yaml.Node)}
+	v.walk(doc.Content[0], reflect.TypeOf(ProjectConfig{}), "")
+	if v.errors > 0 {
+		// Names can only be checked once the document decodes
+		return v.issues
+	}
+
+	var cfg ProjectConfig
+	if err := doc.Decode(&cfg); err != nil {
+		v.add(nil, "", IssueError, "%v", err)
+		return v.issues
+	}
+	v.checkNames(&cfg, names)
+
+	// Report in file order; issues without a line come last
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		li, lj := v.issues[i].Line, v.issues[j].Line
+		return li != 0 && (lj == 0 || li < lj)
+	})
+	return v.issues
+}
+
+// configValidator collects issues while walking a config document
+type configValidator struct {
+	issues []ConfigIssue
+	errors int
+	keys   map[string]*yaml.Node // Dotted path -> key node, to locate name issues
+}
+
+func (v *configValidator) add(node *yaml.Node, path, severity, format string, args ...interface{}) {
+	issue := ConfigIssue{Path: path, Severity: severity, Message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		issue.Line = node.Line
+	}
+	if severity == IssueError {
+		v.errors++
+	}
+	v.issues = append(v.issues, issue)
+}
+
+// addAt reports an issue at the key recorded for path
+func (v *configValidator) addAt(path, severity, format string, args ...interface{}) {
+	v.add(v.keys[path], path, severity, format, args...)
+}
+
+// walk checks node against the Go type it decodes into
+func (v *configValidator) walk(node *yaml.Node, t reflect.Type, path string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	// Types with their own decoding (e.g. a gate written as a mode string)
+	// are checked by decoding them; mappings are still walked for unknown keys
+	if reflect.PtrTo(t).Implements(reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()) {
+		if err := node.Decode(reflect.New(t).Interface()); err != nil {
+			v.add(node, path, IssueError, "%v", err)
+			return
+		}
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			v.add(node, path, IssueError, "expected a mapping, got %s", describeNode(node))
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := joinKeyPath(path, key.Value)
+			v.keys[keyPath] = key
+			field, ok := fields[key.Value]
+			if !ok {
+				v.add(key, keyPath, IssueWarning, "unknown key %q", key.Value)
+				continue
+			}
+			v.walk(value, field, keyPath)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			v.add(node, path, IssueError, "expected a mapping, got %s", describeNode(node))
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := joinKeyPath(path, key.Value)
+			v.keys[keyPath] = key
+			v.walk(value, t.Elem(), keyPath)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			v.add(node, path, IssueError, "expected a list, got %s", describeNode(node))
+			return
+		}
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			v.keys[itemPath] = item
+			v.walk(item, t.Elem(), itemPath)
+		}
+	case reflect.Interface:
+		return
+	default:
+		if node.Kind != yaml.ScalarNode || node.Decode(reflect.New(t).Interface()) != nil {
+			v.add(node, path, IssueError, "expected %s, got %s", describeKind(t), describeNode(node))
+		}
+	}
+}
+
+// checkNames checks phase, tool and provider names once the document decodes
+func (v *configValidator) checkNames(cfg *ProjectConfig, names ConfigNames) {
+	if cfg.SchemaVersion > ConfigSchemaVersion {
+		v.addAt("schema_version", IssueError, "schema version %d is newer than supported version %d (upgrade forge)",
+			cfg.SchemaVersion, ConfigSchemaVersion)
+	}
+
+	for _, phase := range sortedKeys(cfg.Phases) {
+		path := "phases." + phase
+		if !IsValidPhase(phase) {
+			v.addAt(path, IssueError, "unknown phase %q", phase)
+		}
+		if tool := cfg.Phases[phase]; tool != "" && names.Tools != nil && !containsString(names.Tools, tool) {
+			v.addAt(path, IssueError, "unknown tool %q (expected one of %s)", tool, strings.Join(sortedCopy(names.Tools), ", "))
+		}
+	}
+	for _, phase := range sortedKeys(cfg.Gates) {
+		path := "gates." + phase
+		if !IsValidPhase(phase) {
+			v.addAt(path, IssueError, "unknown phase %q", phase)
+		}
+		if err := cfg.Gates[phase].Validate(); err != nil {
+			v.addAt(path, IssueError, "%v", err)
+		}
+	}
+	for _, phase := range sortedKeys(cfg.DependsOn) {
+		path := "depends_on." + phase
+		if !IsValidPhase(phase) {
+			v.addAt(path, IssueError, "unknown phase %q", phase)
+		}
+		for i, dep := range cfg.DependsOn[phase] {
+			if !IsValidPhase(dep) {
+				v.addAt(fmt.Sprintf("%s[%d]", path, i), IssueError, "unknown phase %q", dep)
+			}
+		}
+	}
+	if v.errors == 0 {
+		if _, err := cfg.PhaseGraph(); err != nil {
+			v.addAt("depends_on", IssueError, "%v", err)
+		}
+	}
+
+	if names.Providers == nil {
+		return
+	}
+	if cfg.Tools.Codex.Provider != "" {
+		v.checkProvider("tools.codex.provider", cfg.Tools.Codex.Provider, names.Providers)
+	}
+	for _, model := range sortedKeys(cfg.Serve.Models) {
+		provider, _, _ := strings.Cut(cfg.Serve.Models[model], "/")
+		v.checkProvider("serve.models."+model, provider, names.Providers)
+	}
+	for i, target := range cfg.Serve.Fallback {
+		provider, _, _ := strings.Cut(target, "/")
+		v.checkProvider(fmt.Sprintf("serve.fallback[%d]", i), provider, names.Providers)
+	}
+}
+
+// checkProvider reports a provider reference that is not configured. The
+// providers built from tools settings are only warned about: the defaults
+// refer to ollama before it is enabled.
+func (v *configValidator) checkProvider(path, provider string, configured []string) {
+	if containsString(configured, provider) {
+		return
+	}
+	if tool, ok := providerTools[provider]; ok {
+		v.addAt(path, IssueWarning, "provider %q is not enabled (set tools.%s.enabled)", provider, tool)
+		return
+	}
+	v.addAt(path, IssueError, "unknown provider %q (configured: %s)", provider, strings.Join(sortedCopy(configured), ", "))
+}
+
+// yamlFields maps the yaml keys of a struct to field types, including
+// inlined structs
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(opts, "inline") && f.Type.Kind() == reflect.Struct {
+			for k, ft := range yamlFields(f.Type) {
+				fields[k] = ft
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func joinKeyPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// describeKind names the YAML value a Go type expects
+func describeKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	default:
+		return t.Kind().String()
+	}
+}
+
+// describeNode names the YAML value found in a document
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	default:
+		return fmt.Sprintf("%q", node.Value)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedCopy(list []string) []string {
+	sorted := append([]string(nil), list...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateConfig(t *testing.T) {
+	data := `name: demo
+tools:
+  codex:
+    provider: openai
+    enabeld: true
+  ollama:
+    enabled: true
+phases:
+  implementation: codex
+  testing: nosuchtool
+gates:
+  deployment: sometimes
+depends_on:
+  testing: [implementation, shipping]
+serve:
+  models:
+    fast: ollama/llama3.2
+    smart: anthropic/claude
+  fallback: [ollama, gemini]
+`
+	issues := ValidateConfig([]byte(data), ConfigNames{
+		Tools:     []string{"codex", "gemini"},
+		Providers: []string{"ollama"},
+	})
+
+	want := []string{
+		`line 4: tools.codex.provider: unknown provider "openai" (configured: ollama)`,
+		`line 5: tools.codex.enabeld: unknown key "enabeld"`,
+		`line 10: phases.testing: unknown tool "nosuchtool" (expected one of codex, gemini)`,
+		`line 12: gates.deployment: invalid gate mode "sometimes"`,
+		`line 14: depends_on.testing[1]: unknown phase "shipping"`,
+		`line 18: serve.models.smart: provider "anthropic" is not enabled (set tools.claude.enabled)`,
+		`line 19: serve.fallback[1]: unknown provider "gemini" (configured: ollama)`,
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %d: %v", len(want), len(issues), issues)
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(issues[i].String(), w) {
+			t.Errorf("Issue %d: expected %q, got %q", i, w, issues[i].String())
+		}
+	}
+	if issues[0].Severity != IssueError || issues[1].Severity != IssueWarning {
+		t.Errorf("Expected bad names to be errors and unknown keys warnings, got %+v", issues[:2])
+	}
+	if issues[5].Severity != IssueWarning || issues[6].Severity != IssueError {
+		t.Errorf("Expected disabled tool providers to be warnings and unknown ones errors, got %+v", issues[5:])
+	}
+}
+
+func TestValidateConfigTypes(t *testing.T) {
+	data := `name: [not, a, string]
+tools:
+  ollama:
+    enabled: sure
+sessions:
+  max_history: lots
+patterns:
+  directories: ./patterns
+advanced:
+  cost_per_1k_tokens: 0.5
+`
+	issues := ValidateConfig([]byte(data), ConfigNames{Tools: []string{}})
+
+	want := []string{
+		`line 1: name: expected a string, got a list`,
+		`line 4: tools.ollama.enabled: expected a boolean, got "sure"`,
+		`line 6: sessions.max_history: expected an integer, got "lots"`,
+		`line 8: patterns.directories: expected a list, got "./patterns"`,
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %d: %v", len(want), len(issues), issues)
+	}
+	for i, w := range want {
+		if issues[i].String() != w || issues[i].Severity != IssueError {
+			t.Errorf("Issue %d: expected error %q, got %s %q", i, w, issues[i].Severity, issues[i].String())
+		}
+	}
+}
+
+func TestValidateConfigValid(t *testing.T) {
+	data := `schema_version: 1
+name: demo
+gates:
+  implementation: confirm
+  deployment:
+    mode: manual
+    when: before
+phases:
+  implementation: codex
+`
+	if issues := ValidateConfig([]byte(data), ConfigNames{Tools: []string{"codex"}, Providers: []string{"ollama"}}); len(issues) != 0 {
+		t.Errorf("Expected no issues, got %v", issues)
+	}
+
+	issues := ValidateConfig([]byte("name: [unclosed"), ConfigNames{})
+	if len(issues) != 1 || issues[0].Severity != IssueError || !strings.Contains(issues[0].Message, "line 1") {
+		t.Errorf("Expected a syntax error, got %v", issues)
+	}
+
+	issues = ValidateConfig([]byte("gates:\n  deployment:\n    mode: manual\n    whenn: before\n"), ConfigNames{})
+	if len(issues) != 1 || issues[0].String() != `line 4: gates.deployment.whenn: unknown key "whenn"` {
+		t.Errorf("Expected unknown gate keys to be reported, got %v", issues)
+	}
+}
+
+func TestValidateExampleConfig(t *testing.T) {
+	path := filepath.Join("..", "..", "config", "config.example.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read example config: %v", err)
+	}
+	var cfg ProjectConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("Failed to parse example config: %v", err)
+	}
+
+	var names []string
+	for _, pc := range ProvidersFromConfig(&cfg).Providers {
+		names = append(names, pc.Name)
+	}
+	if issues := ValidateConfig(data, ConfigNames{Providers: names}); len(issues) != 0 {
+		t.Errorf("Expected %s to validate, got %v", path, issues)
+	}
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Gate modes control whether forge auto pauses for human approval
+const (
+	GateAuto    = "auto"    // never pause
+	GateConfirm = "confirm" // ask interactively, or wait for `forge approve` without a terminal
+	GateManual  = "manual"  // always wait for `forge approve`
+)
+
+// Gate positions relative to phase execution
+const (
+	GateBefore = "before"
+	GateAfter  = "after"
+)
+
+// Approval statuses
+const (
+	ApprovalPending          = "pending"
+	ApprovalApproved         = "approved"
+	ApprovalRejected         = "rejected"
+	ApprovalChangesRequested = "changes_requested"
+)
+
+// ErrApprovalPending is returned when a run stops to wait for `forge approve`
+var ErrApprovalPending = errors.New("awaiting approval")
+
+// GateConfig configures the approval gate of a phase.
+// It may be written as a plain mode string (e.g. `implementation: confirm`).
+type GateConfig struct {
+	Mode string `yaml:"mode"`
+	When string `yaml:"when,omitempty"` // before or after (default: after)
+}
+
+// UnmarshalYAML accepts either a mode string or a full gate mapping
+func (g *GateConfig) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		g.Mode = value.Value
+		return nil
+	}
+	type plain GateConfig
+	return value.Decode((*plain)(g))
+}
+
+// Validate checks the gate mode and position
+func (g GateConfig) Validate() error {
+	switch g.Mode {
+	case "", GateAuto, GateConfirm, GateManual:
+	default:
+		return fmt.Errorf("invalid gate mode %q (expected auto, confirm or manual)", g.Mode)
+	}
+	switch g.When {
+	case "", GateBefore, GateAfter:
+	default:
+		return fmt.Errorf("invalid gate position %q (expected before or after)", g.When)
+	}
+	return nil
+}
+
+// GateFor returns the gate for a phase with defaults applied
+func (c *ProjectConfig) GateFor(phase string) GateConfig {
+	gate := GateConfig{Mode: GateAuto, When: GateAfter}
+	if c == nil {
+		return gate
+	}
+	if g, ok := c.Gates[phase]; ok {
+		if g.Mode != "" {
+			gate.Mode = g.Mode
+		}
+		if g.When != "" {
+			gate.When = g.When
+		}
+	}
+	return gate
+}
+
+// ApprovalState records a gate decision for a phase
+type ApprovalState struct {
+	When        string    `yaml:"when"`
+	Status      string    `yaml:"status"` // pending, approved, rejected, changes_requested
+	Feedback    string    `yaml:"feedback,omitempty"`
+	RequestedAt time.Time `yaml:"requested_at"`
+	DecidedAt   time.Time `yaml:"decided_at,omitempty"`
+}
+
+// ApprovalRequest is presented to an Approver when a gate is reached
+type ApprovalRequest struct {
+	Phase   string
+	When    string
+	Summary string
+}
+
+// ApprovalDecision is the outcome of an approval request.
+// Status is approved, rejected or changes_requested (with Feedback).
+type ApprovalDecision struct {
+	Status   string
+	Feedback string
+}
+
+// Approver asks a human to decide on a gate (allows mocking in tests)
+type Approver interface {
+	RequestApproval(req ApprovalRequest) (ApprovalDecision, error)
+}
+
+// GateSummary describes the artifacts and checkpoint results of a phase
+func GateSummary(phase string) string {
+	var sb strings.Builder
+
+	sb.WriteString("Artifacts:\n")
+	artifacts, err := ReadArtifacts(phase, 0)
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("  (failed to read artifacts: %v)\n", err))
+	} else if len(artifacts) == 0 {
+		sb.WriteString("  (none)\n")
+	}
+	for _, a := range artifacts {
+		sb.WriteString(fmt.Sprintf("  • %s (%d bytes)\n", a.Path, len(a.Content)))
+	}
+
+	sb.WriteString("Checkpoints:\n")
+	result := ValidateCheckpoint(phase)
+	for _, check := range result.Checks {
+		icon := "✓"
+		if !check.Passed {
+			icon = "✗"
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s\n", icon, check.Name))
+		if check.Message != "" {
+			sb.WriteString(fmt.Sprintf("      %s\n", check.Message))
+		}
+	}
+
+	return sb.String()
+}
+
+// DecideApproval records a decision for a pending approval, as made by `forge approve`
+func (s *ProjectState) DecideApproval(phase string, decision ApprovalDecision) error {
+	if s.Auto == nil || s.Auto.Approvals[phase] == nil {
+		return fmt.Errorf("no approval requested for phase %s", phase)
+	}
+	approval := s.Auto.Approvals[phase]
+	if approval.Status != ApprovalPending {
+		return fmt.Errorf("approval for phase %s is already %s", phase, approval.Status)
+	}
+
+	approval.Status = decision.Status
+	approval.Feedback = decision.Feedback
+	approval.DecidedAt = time.Now()
+	s.AddActivity(fmt.Sprintf("Approval for %s (%s): %s", phase, approval.When, decision.Status))
+	return nil
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fixedApprover returns the given decisions in order, repeating the last one
+type fixedApprover struct {
+	decisions []ApprovalDecision
+	requests  []ApprovalRequest
+}
+
+func (a *fixedApprover) RequestApproval(req ApprovalRequest) (ApprovalDecision, error) {
+	a.requests = append(a.requests, req)
+	d := a.decisions[0]
+	if len(a.decisions) > 1 {
+		a.decisions = a.decisions[1:]
+	}
+	return d, nil
+}
+
+func TestGateConfigUnmarshal(t *testing.T) {
+	var cfg struct {
+		Gates map[string]GateConfig `yaml:"gates"`
+	}
+	data := `
+gates:
+  implementation: confirm
+  deployment:
+    mode: manual
+    when: before
+`
+	if err := yaml.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Gates["implementation"].Mode != GateConfirm {
+		t.Errorf("Expected scalar gate mode 'confirm', got '%s'", cfg.Gates["implementation"].Mode)
+	}
+	if g := cfg.Gates["deployment"]; g.Mode != GateManual || g.When != GateBefore {
+		t.Errorf("Expected manual/before, got %s/%s", g.Mode, g.When)
+	}
+}
+
+func TestGateFor(t *testing.T) {
+	cfg := &ProjectConfig{Gates: map[string]GateConfig{"design": {Mode: GateManual}}}
+
+	if g := cfg.GateFor("design"); g.Mode != GateManual || g.When != GateAfter {
+		t.Errorf("Expected manual/after, got %s/%s", g.Mode, g.When)
+	}
+	if g := cfg.GateFor("planning"); g.Mode != GateAuto {
+		t.Errorf("Expected auto for ungated phase, got %s", g.Mode)
+	}
+	if err := (GateConfig{Mode: "sometimes"}).Validate(); err == nil {
+		t.Error("Expected invalid mode to fail validation")
+	}
+}
+
+func TestAutoRunnerGatePendingAndResume(t *testing.T) {
+	runner := newTestRunner(t)
+	runner.Config.Gates = map[string]GateConfig{"discovery": {Mode: GateManual}}
+	executor := &mockPhaseExecutor{}
+	runner.Executor = executor
+
+	err := runner.Run("discovery", "discovery", true)
+	if !errors.Is(err, ErrApprovalPending) {
+		t.Fatalf("Expected ErrApprovalPending, got %v", err)
+	}
+	if runner.State.GetPhaseStatus("discovery") != "awaiting_approval" {
+		t.Errorf("Expected awaiting_approval, got %s", runner.State.GetPhaseStatus("discovery"))
+	}
+
+	// Approve out of band like forge approve, then resume from the saved state
+	state, err := UpdateProjectState(runner.StatePath, func(s *ProjectState) error {
+		return s.DecideApproval("discovery", ApprovalDecision{Status: ApprovalApproved})
+	})
+	if err != nil {
+		t.Fatalf("Expected no error approving, got %v", err)
+	}
+
+	resumed := NewAutoRunner(runner.Config, state, runner.StatePath)
+	resumed.Executor = executor
+	if err := resumed.Run("discovery", "discovery", true); err != nil {
+		t.Fatalf("Expected resumed run to succeed, got %v", err)
+	}
+	if len(executor.feedback) != 1 {
+		t.Errorf("Expected the approved phase not to be re-executed, got %d executions", len(executor.feedback))
+	}
+	if resumed.State.GetPhaseStatus("discovery") != "completed" {
+		t.Errorf("Expected completed, got %s", resumed.State.GetPhaseStatus("discovery"))
+	}
+}
+
+func TestAutoRunnerGateFeedbackReruns(t *testing.T) {
+	runner := newTestRunner(t)
+	runner.Config.Gates = map[string]GateConfig{"discovery": {Mode: GateConfirm}}
+	executor := &mockPhaseExecutor{}
+	runner.Executor = executor
+	runner.Approver = &fixedApprover{decisions: []ApprovalDecision{
+		{Status: ApprovalChangesRequested, Feedback: "add personas"},
+		{Status: ApprovalApproved},
+	}}
+
+	if err := runner.Run("discovery", "discovery", true); err != nil {
+		t.Fatalf("Expected run to succeed, got %v", err)
+	}
+	if len(executor.feedback) != 2 || executor.feedback[1] != (PhaseFeedback{Source: FeedbackReviewer, Text: "add personas"}) {
+		t.Errorf("Expected re-run with reviewer feedback, got %v", executor.feedback)
+	}
+	if n := len(runner.State.Auto.Attempts["discovery"]); n != 2 {
+		t.Errorf("Expected 2 attempts recorded, got %d", n)
+	}
+}
+
+func TestAutoRunnerGateReject(t *testing.T) {
+	runner := newTestRunner(t)
+	runner.Config.Gates = map[string]GateConfig{"discovery": {Mode: GateConfirm, When: GateBefore}}
+	executor := &mockPhaseExecutor{}
+	runner.Executor = executor
+	runner.Approver = &fixedApprover{decisions: []ApprovalDecision{{Status: ApprovalRejected}}}
+
+	if err := runner.Run("discovery", "discovery", true); err == nil {
+		t.Fatal("Expected rejected phase to stop the run")
+	}
+	if len(executor.feedback) != 0 {
+		t.Error("Expected phase rejected before running not to execute")
+	}
+	if runner.State.GetPhaseStatus("discovery") != "rejected" {
+		t.Errorf("Expected rejected, got %s", runner.State.GetPhaseStatus("discovery"))
+	}
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// DefaultCommitTemplate is used when integrations.git.commit_template is empty
+const DefaultCommitTemplate = "forge: Complete {{.Phase}} phase"
+
+// GitTagPrefix prefixes the tags marking phase completions, e.g. forge/design
+const GitTagPrefix = "forge/"
+
+// forgePathspec selects what phase commits include: everything under .forge
+// except the state lock and backup
+var forgePathspec = []string{".forge", ":(exclude).forge/*.lock", ":(exclude).forge/*.bak"}
+
+// ErrDirtyWorktree is returned when files outside .forge have uncommitted changes
+var ErrDirtyWorktree = errors.New("uncommitted changes outside .forge")
+
+// CommitData is passed to the commit message template
+type CommitData struct {
+	Phase   string
+	Project string
+	Notes   string
+}
+
+// GitIntegration commits forge state and artifacts with the local git binary
+type GitIntegration struct {
+	Config  GitConfig
+	Dir     string // Project directory containing .forge
+	Project string // Project name for the commit message
+	Force   bool   // Commit even when files outside .forge have uncommitted changes
+}
+
+// NewGitIntegration returns the git integration for a project, or nil when
+// integrations.git.auto_commit is off
+func NewGitIntegration(cfg *ProjectConfig, dir string, force bool) *GitIntegration {
+	if cfg == nil || !cfg.Integrations.Git.AutoCommit {
+		return nil
+	}
+	return &GitIntegration{Config: cfg.Integrations.Git, Dir: dir, Project: cfg.Name, Force: force}
+}
+
+// git runs a git command in the project directory
+func (g *GitIntegration) git(args ...string) (string, error) {
+	return runGit(g.Dir, args...)
+}
+
+// runGit runs a git command in dir and returns its output without the
+// trailing newline
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// CheckClean fails when the repository has uncommitted changes outside
+// .forge, unless Force is set. It also fails outside a git work tree.
+func (g *GitIntegration) CheckClean() error {
+	if _, err := g.git("rev-parse", "--is-inside-work-tree"); err != nil {
+		return fmt.Errorf("integrations.git.auto_commit is enabled but the project is not in a git repository: %w", err)
+	}
+	if g.Force {
+		return nil
+	}
+
+	out, err := g.git("status", "--porcelain", "--", ":/", ":(exclude).forge")
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		return nil
+	}
+
+	var paths []string
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) > 3 {
+			paths = append(paths, line[3:])
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrDirtyWorktree, strings.Join(paths, ", "))
+}
+
+// CommitMessage renders the commit message template
+func (g *GitIntegration) CommitMessage(data CommitData) (string, error) {
+	text := g.Config.CommitTemplate
+	if text == "" {
+		text = DefaultCommitTemplate
+	}
+	tmpl, err := template.New("commit").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse commit_template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render commit_template: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// CommitPhase commits the changes under .forge for a completed phase and,
+// when tagging is enabled, points the forge/<phase> tag at the commit.
+// Artifacts, archives and history of other phases are left out, since
+// phases of a concurrent wave may still be writing them. Changes outside
+// .forge are never included, so callers check CheckClean before the phase
+// work starts rather than here, after it has edited other files. It returns
+// the commit SHA, or "" when the phase has no changes under .forge.
+func (g *GitIntegration) CommitPhase(phase, notes string) (string, error) {
+	message, err := g.CommitMessage(CommitData{Phase: phase, Project: g.Project, Notes: notes})
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := g.git(append([]string{"add", "--all", "--"}, forgePathspec...)...); err != nil {
+		return "", err
+	}
+	changed, err := g.git(append([]string{"diff", "--cached", "--name-only", "--no-renames", "--relative", "-z", "--"}, forgePathspec...)...)
+	if err != nil {
+		return "", err
+	}
+	var paths []string
+	for _, path := range strings.Split(changed, "\x00") {
+		if path != "" && phaseOwnsPath(phase, path) {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return "", nil
+	}
+
+	// --only with the phase's paths leaves anything else staged, by the user
+	// or for another phase, out of the commit
+	if _, err := g.git(append([]string{"commit", "--quiet", "--only", "-m", message, "--"}, paths...)...); err != nil {
+		return "", err
+	}
+	sha, err := g.git("rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	if g.Config.Tag {
+		// Reopened phases are completed again, so the tag moves to the latest completion
+		if _, err := g.git("tag", "--force", GitTagPrefix+phase, sha); err != nil {
+			return sha, err
+		}
+	}
+	return sha, nil
+}
+
+// phaseOwnsPath reports whether a changed path under .forge belongs in the
+// commit of phase: its own artifacts, archives and history, and the files
+// shared by all phases such as the state and config
+func phaseOwnsPath(phase, path string) bool {
+	path = filepath.ToSlash(path)
+	for _, dir := range []string{ArtifactsDir, ArchiveDir} {
+		if strings.HasPrefix(path, dir+"/") {
+			return strings.HasPrefix(path, dir+"/"+phase+"/")
+		}
+	}
+	if history := filepath.ToSlash(HistoryDir(".forge/state.yaml")); strings.HasPrefix(path, history+"/") {
+		return strings.HasPrefix(path, history+"/"+phase+"_")
+	}
+	return true
+}
+
+// ShortSHA abbreviates a commit SHA for display
+func ShortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultGitInputLimit bounds how much git output is collected as input
+const DefaultGitInputLimit = 128 * 1024
+
+// gitLogFormat keeps log input readable for changelog and review patterns
+const gitLogFormat = "commit %h%nAuthor: %an%nDate:   %ad%n%n%w(0,4,4)%B"
+
+// GitSource selects local git content to use as pattern input or tool context
+type GitSource struct {
+	Diff     string   // Diff against this commit or range, e.g. HEAD or main...feature
+	Staged   bool     // Diff the index against HEAD
+	Log      string   // Log of this range, e.g. v1.2.0..HEAD
+	Paths    []string // Limit output to these pathspecs, e.g. internal/ or ':(exclude)vendor'
+	MaxBytes int      // Truncate the collected content (0 = no limit)
+}
+
+// IsZero reports whether no git content is selected
+func (s GitSource) IsZero() bool {
+	return s.Diff == "" && !s.Staged && s.Log == ""
+}
+
+// Collect runs git in dir and returns the selected content, one section per
+// source. It fails when dir is not in a git repository or a range is invalid.
+func (s GitSource) Collect(dir string) (string, error) {
+	// Ranges are passed as arguments, so they must not be read as options
+	for _, r := range []string{s.Diff, s.Log} {
+		if strings.HasPrefix(r, "-") {
+			return "", fmt.Errorf("invalid git range: %s", r)
+		}
+	}
+
+	type section struct {
+		title string
+		args  []string
+	}
+	var sections []section
+	if s.Log != "" {
+		sections = append(sections, section{
+			title: "git log " + s.Log,
+			args:  []string{"log", "--no-color", "--date=short", "--format=" + gitLogFormat, s.Log},
+		})
+	}
+	if s.Staged {
+		sections = append(sections, section{
+			title: "git diff --staged",
+			args:  []string{"diff", "--no-color", "--no-ext-diff", "--staged"},
+		})
+	}
+	if s.Diff != "" {
+		sections = append(sections, section{
+			title: "git diff " + s.Diff,
+			args:  []string{"diff", "--no-color", "--no-ext-diff", s.Diff},
+		})
+	}
+
+	var sb strings.Builder
+	for _, sec := range sections {
+		args := append(sec.args, "--")
+		args = append(args, s.Paths...)
+		out, err := runGit(dir, args...)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(out) == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n\n")
+		}
+		fmt.Fprintf(&sb, "## %s\n\n%s", sec.title, out)
+	}
+
+	return truncateGitInput(sb.String(), s.MaxBytes), nil
+}
+
+// truncateGitInput cuts content to maxBytes at a line boundary and notes how
+// much was left out
+func truncateGitInput(content string, maxBytes int) string {
+	if maxBytes <= 0 || len(content) <= maxBytes {
+		return content
+	}
+	cut := content[:maxBytes]
+	if i := strings.LastIndexByte(cut, '\n'); i > 0 {
+		cut = cut[:i]
+	}
+	return fmt.Sprintf("%s\n\n[truncated: %d more bytes]", cut, len(content)-len(cut))
+}
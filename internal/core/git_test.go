@@ -0,0 +1,294 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initGitRepo creates a git repository with one commit in a temporary directory
+func initGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "forge")
+	t.Setenv("GIT_AUTHOR_EMAIL", "forge@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "forge")
+	t.Setenv("GIT_COMMITTER_EMAIL", "forge@example.com")
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+
+	dir := t.TempDir()
+	writeRepoFile(t, filepath.Join(dir, "README.md"), "# demo\n")
+	g := &GitIntegration{Dir: dir}
+	for _, args := range [][]string{{"init", "--quiet"}, {"add", "README.md"}, {"commit", "--quiet", "-m", "init"}} {
+		if _, err := g.git(args...); err != nil {
+			t.Fatalf("Failed to set up repository: %v", err)
+		}
+	}
+	return dir
+}
+
+// writeRepoFile writes a file, creating its parent directories
+func writeRepoFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, content)
+}
+
+func TestNewGitIntegration(t *testing.T) {
+	cfg := NewProjectConfig("demo", "cli")
+	if g := NewGitIntegration(cfg, ".", false); g != nil {
+		t.Error("Expected no git integration without auto_commit")
+	}
+	if g := NewGitIntegration(nil, ".", false); g != nil {
+		t.Error("Expected no git integration without a config")
+	}
+
+	cfg.Integrations.Git.AutoCommit = true
+	if g := NewGitIntegration(cfg, ".", true); g == nil || g.Project != "demo" || !g.Force {
+		t.Errorf("Expected git integration for demo, got %+v", g)
+	}
+}
+
+func TestGitCommitMessage(t *testing.T) {
+	g := &GitIntegration{Project: "demo"}
+	if msg, err := g.CommitMessage(CommitData{Phase: "design"}); err != nil || msg != "forge: Complete design phase" {
+		t.Errorf("Expected default message, got %q, %v", msg, err)
+	}
+
+	g.Config.CommitTemplate = "{{.Project}}: {{.Phase}} done{{if .Notes}} - {{.Notes}}{{end}}"
+	if msg, _ := g.CommitMessage(CommitData{Phase: "planning", Project: "demo", Notes: "scope agreed"}); msg != "demo: planning done - scope agreed" {
+		t.Errorf("Expected rendered template, got %q", msg)
+	}
+
+	g.Config.CommitTemplate = "{{.Phase"
+	if _, err := g.CommitMessage(CommitData{}); err == nil {
+		t.Error("Expected error for an invalid template")
+	}
+}
+
+func TestGitCommitPhase(t *testing.T) {
+	dir := initGitRepo(t)
+	g := &GitIntegration{Config: GitConfig{AutoCommit: true, Tag: true}, Dir: dir, Project: "demo"}
+
+	writeRepoFile(t, filepath.Join(dir, ".forge", "state.yaml"), "current_phase: \"\"\n")
+	writeRepoFile(t, filepath.Join(dir, ".forge", "state.yaml.bak"), "backup\n")
+	writeRepoFile(t, filepath.Join(dir, ".forge", "artifacts", "design", "api.md"), "# API\n")
+
+	sha, err := g.CommitPhase("design", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sha == "" {
+		t.Fatal("Expected a commit SHA")
+	}
+
+	files, _ := g.git("show", "--name-only", "--format=%s", sha)
+	if !strings.HasPrefix(files, "forge: Complete design phase\n") {
+		t.Errorf("Expected commit message from the default template, got %q", files)
+	}
+	if !strings.Contains(files, ".forge/artifacts/design/api.md") || strings.Contains(files, "state.yaml.bak") {
+		t.Errorf("Expected artifacts without the state backup, got %q", files)
+	}
+	if tagged, _ := g.git("rev-parse", "forge/design^{commit}"); tagged != sha {
+		t.Errorf("Expected forge/design to point at %s, got %s", sha, tagged)
+	}
+
+	if again, err := g.CommitPhase("design", ""); err != nil || again != "" {
+		t.Errorf("Expected no commit without changes, got %q, %v", again, err)
+	}
+}
+
+func TestGitCommitPhaseLeavesOtherPhases(t *testing.T) {
+	dir := initGitRepo(t)
+	g := &GitIntegration{Config: GitConfig{AutoCommit: true}, Dir: dir}
+
+	// testing is still running in the same wave when deployment completes
+	writeRepoFile(t, filepath.Join(dir, ".forge", "state.yaml"), "current_phase: \"\"\n")
+	writeRepoFile(t, filepath.Join(dir, ".forge", "artifacts", "deployment", "release.md"), "# Release\n")
+	writeRepoFile(t, filepath.Join(dir, ".forge", "history", "deployment_20260302_103000.yaml"), "phase: deployment\n")
+	writeRepoFile(t, filepath.Join(dir, ".forge", "artifacts", "testing", "draft.md"), "# Draft\n")
+	writeRepoFile(t, filepath.Join(dir, ".forge", "history", "testing_20260302_103000.yaml"), "phase: testing\n")
+
+	sha, err := g.CommitPhase("deployment", "")
+	if err != nil || sha == "" {
+		t.Fatalf("Expected commit, got %q, %v", sha, err)
+	}
+	files, _ := g.git("show", "--name-only", "--format=", sha)
+	for _, want := range []string{".forge/state.yaml", ".forge/artifacts/deployment/release.md", ".forge/history/deployment_"} {
+		if !strings.Contains(files, want) {
+			t.Errorf("Expected %s in the commit, got %q", want, files)
+		}
+	}
+	if strings.Contains(files, "testing") {
+		t.Errorf("Expected the files of testing to be left out, got %q", files)
+	}
+
+	// Removed artifacts are committed with their phase
+	if err := os.Remove(filepath.Join(dir, ".forge", "artifacts", "deployment", "release.md")); err != nil {
+		t.Fatal(err)
+	}
+	sha, err = g.CommitPhase("deployment", "")
+	if err != nil || sha == "" {
+		t.Fatalf("Expected a commit for the removal, got %q, %v", sha, err)
+	}
+
+	sha, err = g.CommitPhase("testing", "")
+	if err != nil || sha == "" {
+		t.Fatalf("Expected testing to commit its own files, got %q, %v", sha, err)
+	}
+	if files, _ := g.git("show", "--name-only", "--format=", sha); !strings.Contains(files, ".forge/artifacts/testing/draft.md") {
+		t.Errorf("Expected testing artifacts in its commit, got %q", files)
+	}
+}
+
+func TestGitCommitPhaseDirty(t *testing.T) {
+	dir := initGitRepo(t)
+	g := &GitIntegration{Config: GitConfig{AutoCommit: true}, Dir: dir}
+
+	writeRepoFile(t, filepath.Join(dir, "README.md"), "# changed\n")
+	writeRepoFile(t, filepath.Join(dir, ".forge", "state.yaml"), "current_phase: \"\"\n")
+
+	if err := g.CheckClean(); !errors.Is(err, ErrDirtyWorktree) || !strings.Contains(err.Error(), "README.md") {
+		t.Fatalf("Expected dirty worktree error naming README.md, got %v", err)
+	}
+	g.Force = true
+	if err := g.CheckClean(); err != nil {
+		t.Fatalf("Expected forced check to pass, got %v", err)
+	}
+
+	// Phase commits leave unrelated changes out, even when staged
+	if _, err := g.git("add", "README.md"); err != nil {
+		t.Fatal(err)
+	}
+	g.Force = false
+	sha, err := g.CommitPhase("planning", "")
+	if err != nil || sha == "" {
+		t.Fatalf("Expected commit, got %q, %v", sha, err)
+	}
+	if files, _ := g.git("show", "--name-only", "--format=", sha); files != ".forge/state.yaml" {
+		t.Errorf("Expected only .forge/state.yaml in the commit, got %q", files)
+	}
+	if status, _ := g.git("status", "--porcelain", "README.md"); status != "M  README.md" {
+		t.Errorf("Expected README.md to stay staged, got %q", status)
+	}
+}
+
+func TestAutoRunnerCommitsPhaseWithHistory(t *testing.T) {
+	dir := initGitRepo(t)
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(oldWd) })
+
+	cfg := NewProjectConfig("demo", "")
+	cfg.Gates = nil
+	cfg.Integrations.Git.AutoCommit = true
+	runner := NewAutoRunner(cfg, NewProjectState(), filepath.Join(".forge", "state.yaml"))
+	runner.Git = NewGitIntegration(cfg, ".", false)
+	writeRepoFile(t, runner.StatePath, "current_phase: \"\"\n")
+	// The phase edits files outside .forge, as the AI tools do
+	runner.Executor = funcPhaseExecutor(func(phase string) error {
+		writeRepoFile(t, "README.md", "# edited by "+phase+"\n")
+		_, err := SaveArtifact(phase, "notes.md", "# notes\n")
+		return err
+	})
+
+	if err := runner.Run("discovery", "discovery", true); err != nil {
+		t.Fatalf("Expected run to succeed, got %v", err)
+	}
+
+	files, err := runner.Git.git("show", "--name-only", "--format=", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(files, ".forge/history/") || !strings.Contains(files, ".forge/artifacts/discovery/notes.md") {
+		t.Errorf("Expected the phase commit to include history and artifacts, got %q", files)
+	}
+	if strings.Contains(files, "README.md") {
+		t.Errorf("Expected README.md to stay out of the phase commit, got %q", files)
+	}
+
+	head, err := runner.Git.git("rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	histories, err := LoadPhaseHistories(HistoryDir(runner.StatePath))
+	if err != nil || len(histories) != 1 {
+		t.Fatalf("Expected one history, got %d, %v", len(histories), err)
+	}
+	if histories[0].Commit != head {
+		t.Errorf("Expected the history to record commit %s, got %q", head, histories[0].Commit)
+	}
+}
+
+func TestGitCheckCleanOutsideRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_CEILING_DIRECTORIES", os.TempDir())
+	g := &GitIntegration{Dir: t.TempDir()}
+	if err := g.CheckClean(); err == nil || !strings.Contains(err.Error(), "not in a git repository") {
+		t.Errorf("Expected not a git repository error, got %v", err)
+	}
+}
+
+func TestGitSourceCollect(t *testing.T) {
+	dir := initGitRepo(t)
+	g := &GitIntegration{Dir: dir}
+
+	writeRepoFile(t, filepath.Join(dir, "README.md"), "# demo\n\nUsage\n")
+	writeRepoFile(t, filepath.Join(dir, "main.go"), "package main\n")
+	if _, err := g.git("add", "main.go"); err != nil {
+		t.Fatal(err)
+	}
+
+	staged, err := GitSource{Staged: true}.Collect(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(staged, "## git diff --staged\n") || !strings.Contains(staged, "+package main") || strings.Contains(staged, "README.md") {
+		t.Errorf("Expected only the staged main.go, got %q", staged)
+	}
+
+	diff, _ := GitSource{Diff: "HEAD", Paths: []string{"README.md"}}.Collect(dir)
+	if !strings.Contains(diff, "+Usage") || strings.Contains(diff, "main.go") {
+		t.Errorf("Expected the README.md diff only, got %q", diff)
+	}
+
+	log, _ := GitSource{Log: "HEAD", Diff: "HEAD"}.Collect(dir)
+	if !strings.HasPrefix(log, "## git log HEAD\n") || !strings.Contains(log, "    init") || !strings.Contains(log, "## git diff HEAD") {
+		t.Errorf("Expected log and diff sections, got %q", log)
+	}
+
+	if empty, err := (GitSource{Diff: "HEAD", Paths: []string{"missing/"}}).Collect(dir); err != nil || empty != "" {
+		t.Errorf("Expected no content for an unchanged path, got %q, %v", empty, err)
+	}
+	if _, err := (GitSource{Diff: "--output=/tmp/x"}).Collect(dir); err == nil {
+		t.Error("Expected error for a range that looks like an option")
+	}
+	if _, err := (GitSource{Log: "no-such-ref"}).Collect(dir); err == nil {
+		t.Error("Expected error for an unknown range")
+	}
+}
+
+func TestTruncateGitInput(t *testing.T) {
+	content := "line one\nline two\nline three\n"
+	if got := truncateGitInput(content, 0); got != content {
+		t.Errorf("Expected no truncation without a limit, got %q", got)
+	}
+	got := truncateGitInput(content, 12)
+	if !strings.HasPrefix(got, "line one\n\n[truncated: ") {
+		t.Errorf("Expected truncation at a line boundary, got %q", got)
+	}
+}
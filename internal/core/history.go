@@ -0,0 +1,280 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Timeline event types
+const (
+	EventActivity = "activity" // Entry of the state activity log
+	EventPhase    = "phase"    // Completed or failed phase run from a history file
+	EventAttempt  = "attempt"  // Validation attempt from a history file
+)
+
+// Phase history outcomes
+const (
+	HistoryCompleted = "completed"
+	HistoryFailed    = "failed"
+)
+
+// Completed reports whether the history records a completed phase. Files
+// written before outcomes were recorded are judged by their last attempt.
+func (h *PhaseHistory) Completed() bool {
+	if h.Outcome != "" {
+		return h.Outcome == HistoryCompleted
+	}
+	if len(h.Attempts) == 0 {
+		return true
+	}
+	verdict := h.Attempts[len(h.Attempts)-1].Verdict
+	return verdict == "passed" || verdict == "unvalidated"
+}
+
+// TimelineEventTypes lists every timeline event type
+var TimelineEventTypes = []string{EventActivity, EventPhase, EventAttempt}
+
+// TimelineEvent is one entry of the project timeline
+type TimelineEvent struct {
+	Timestamp time.Time
+	Type      string
+	Phase     string
+	Message   string
+	Duration  time.Duration // Phase events: time from start to completion
+	Verdict   string        // Attempt events
+	Notes     string        // Phase events
+	Commit    string        // Phase events, with integrations.git.auto_commit
+}
+
+// HistoryDir returns the directory holding phase history files for a state file
+func HistoryDir(statePath string) string {
+	return filepath.Join(filepath.Dir(statePath), "history")
+}
+
+// NewHistoryPath returns an unused history file path for a phase run that
+// ended at t. Runs ending in the same second get a numeric suffix.
+func NewHistoryPath(statePath, phase string, t time.Time) string {
+	base := filepath.Join(HistoryDir(statePath), fmt.Sprintf("%s_%s", phase, t.Format("20060102_150405")))
+	path := base + ".yaml"
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = fmt.Sprintf("%s_%d.yaml", base, n)
+	}
+}
+
+// LoadPhaseHistories reads every history file in dir, oldest completion
+// first. A missing directory yields no histories. Files that cannot be read
+// are skipped and reported in the returned error alongside the others.
+func LoadPhaseHistories(dir string) ([]PhaseHistory, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var histories []PhaseHistory
+	var errs []error
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".yaml") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var h PhaseHistory
+		if err := yaml.Unmarshal(data, &h); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		if h.Phase == "" {
+			errs = append(errs, fmt.Errorf("%s: missing phase", path))
+			continue
+		}
+		histories = append(histories, h)
+	}
+
+	sort.SliceStable(histories, func(i, j int) bool {
+		return histories[i].CompletedAt.Before(histories[j].CompletedAt)
+	})
+	return histories, errors.Join(errs...)
+}
+
+// BuildTimeline merges the activity log with phase histories into one
+// timeline, oldest first
+func BuildTimeline(activities []Activity, histories []PhaseHistory) []TimelineEvent {
+	var events []TimelineEvent
+	for _, a := range activities {
+		events = append(events, TimelineEvent{
+			Timestamp: a.Timestamp,
+			Type:      EventActivity,
+			Phase:     a.Phase,
+			Message:   a.Message,
+		})
+	}
+
+	for _, pa := range UniqueAttempts(histories) {
+		at := pa.CompletedAt
+		if at.IsZero() {
+			at = pa.StartedAt
+		}
+		events = append(events, TimelineEvent{
+			Timestamp: at,
+			Type:      EventAttempt,
+			Phase:     pa.Phase,
+			Message:   fmt.Sprintf("Attempt %d: %s", pa.Attempt, pa.Verdict),
+			Verdict:   pa.Verdict,
+		})
+	}
+
+	for _, h := range histories {
+		message := fmt.Sprintf("Phase %s completed", h.Phase)
+		if !h.Completed() {
+			message = fmt.Sprintf("Phase %s failed", h.Phase)
+		}
+		events = append(events, TimelineEvent{
+			Timestamp: h.CompletedAt,
+			Type:      EventPhase,
+			Phase:     h.Phase,
+			Message:   message,
+			Duration:  h.Duration,
+			Notes:     h.Notes,
+			Commit:    h.Commit,
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events
+}
+
+// PhaseAttempt is an attempt together with the phase it belongs to
+type PhaseAttempt struct {
+	Phase string
+	AttemptRecord
+}
+
+// UniqueAttempts returns the attempts recorded in histories, oldest history
+// first. A failed run and the run that later completes the phase both list
+// the earlier attempts, so attempts are counted once.
+func UniqueAttempts(histories []PhaseHistory) []PhaseAttempt {
+	type key struct {
+		phase   string
+		attempt int
+		started time.Time
+	}
+	seen := make(map[key]bool)
+
+	var attempts []PhaseAttempt
+	for _, h := range histories {
+		for _, a := range h.Attempts {
+			k := key{h.Phase, a.Attempt, a.StartedAt.Truncate(time.Second)}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			attempts = append(attempts, PhaseAttempt{Phase: h.Phase, AttemptRecord: a})
+		}
+	}
+	return attempts
+}
+
+// TimelineFilter selects timeline events. Zero values match everything.
+type TimelineFilter struct {
+	Phase string
+	Since time.Time // Inclusive
+	Until time.Time // Exclusive
+	Types []string
+}
+
+// Match reports whether e passes the filter
+func (f TimelineFilter) Match(e TimelineEvent) bool {
+	if f.Phase != "" && e.Phase != f.Phase {
+		return false
+	}
+	if !f.Since.IsZero() && e.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Timestamp.Before(f.Until) {
+		return false
+	}
+	if len(f.Types) > 0 {
+		for _, t := range f.Types {
+			if t == e.Type {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// FilterTimeline returns the events that pass f
+func FilterTimeline(events []TimelineEvent, f TimelineFilter) []TimelineEvent {
+	var filtered []TimelineEvent
+	for _, e := range events {
+		if f.Match(e) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// PhaseStats summarizes how long a phase took across its completions
+type PhaseStats struct {
+	Phase       string
+	Completions int
+	Attempts    int
+	Total       time.Duration
+	Average     time.Duration
+	Shortest    time.Duration
+	Longest     time.Duration
+}
+
+// ComputePhaseStats returns duration statistics for every phase with at
+// least one completion, in phase order. Failed runs are not counted.
+func ComputePhaseStats(histories []PhaseHistory) []PhaseStats {
+	byPhase := make(map[string]*PhaseStats)
+	for _, h := range histories {
+		if !h.Completed() {
+			continue
+		}
+		s, ok := byPhase[h.Phase]
+		if !ok {
+			s = &PhaseStats{Phase: h.Phase, Shortest: h.Duration, Longest: h.Duration}
+			byPhase[h.Phase] = s
+		}
+		s.Completions++
+		s.Attempts += len(h.Attempts)
+		s.Total += h.Duration
+		if h.Duration < s.Shortest {
+			s.Shortest = h.Duration
+		}
+		if h.Duration > s.Longest {
+			s.Longest = h.Duration
+		}
+	}
+
+	var stats []PhaseStats
+	for _, name := range PhaseNames() {
+		if s, ok := byPhase[name]; ok {
+			s.Average = s.Total / time.Duration(s.Completions)
+			stats = append(stats, *s)
+		}
+	}
+	return stats
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadPhaseHistories(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.yaml")
+
+	if histories, err := LoadPhaseHistories(HistoryDir(statePath)); err != nil || histories != nil {
+		t.Errorf("Expected no histories for a missing directory, got %v, %v", histories, err)
+	}
+
+	if err := os.MkdirAll(HistoryDir(statePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	for _, h := range []PhaseHistory{
+		{Phase: "planning", CompletedAt: base.Add(2 * time.Hour), Duration: time.Hour},
+		{Phase: "discovery", CompletedAt: base, Duration: 30 * time.Minute, Notes: "kickoff"},
+	} {
+		if err := h.Save(NewHistoryPath(statePath, h.Phase, h.CompletedAt)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(HistoryDir(statePath), "broken.yaml"), "phase: [")
+
+	histories, err := LoadPhaseHistories(HistoryDir(statePath))
+	if err == nil {
+		t.Error("Expected the broken history file to be reported")
+	}
+	if len(histories) != 2 {
+		t.Fatalf("Expected 2 readable histories, got %d", len(histories))
+	}
+	if histories[0].Phase != "discovery" || histories[0].Notes != "kickoff" {
+		t.Errorf("Expected oldest history first with notes, got %+v", histories[0])
+	}
+}
+
+func TestBuildAndFilterTimeline(t *testing.T) {
+	base := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	activities := []Activity{
+		{Timestamp: base, Message: "Started phase: discovery", Phase: "discovery"},
+		{Timestamp: base.Add(3 * time.Hour), Message: "Started phase: planning", Phase: "planning"},
+	}
+	histories := []PhaseHistory{{
+		Phase:       "discovery",
+		StartedAt:   base,
+		CompletedAt: base.Add(2 * time.Hour),
+		Duration:    2 * time.Hour,
+		Notes:       "done",
+		Commit:      "0123456789abcdef",
+		Attempts: []AttemptRecord{
+			{Attempt: 1, StartedAt: base.Add(time.Hour), CompletedAt: base.Add(90 * time.Minute), Verdict: "failed"},
+		},
+	}}
+
+	events := BuildTimeline(activities, histories)
+	wantTypes := []string{EventActivity, EventAttempt, EventPhase, EventActivity}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("Expected %d events, got %d", len(wantTypes), len(events))
+	}
+	for i, want := range wantTypes {
+		if events[i].Type != want {
+			t.Errorf("Expected event %d to be %s, got %s", i, want, events[i].Type)
+		}
+	}
+	if events[2].Duration != 2*time.Hour || events[2].Notes != "done" || events[2].Commit != "0123456789abcdef" {
+		t.Errorf("Expected phase event to carry duration, notes and commit, got %+v", events[2])
+	}
+
+	filtered := FilterTimeline(events, TimelineFilter{Phase: "discovery", Types: []string{EventActivity, EventPhase}})
+	if len(filtered) != 2 {
+		t.Errorf("Expected 2 discovery activity/phase events, got %d", len(filtered))
+	}
+
+	filtered = FilterTimeline(events, TimelineFilter{Since: base.Add(time.Hour), Until: base.Add(3 * time.Hour)})
+	if len(filtered) != 2 || filtered[0].Type != EventAttempt {
+		t.Errorf("Expected attempt and phase events in range, got %+v", filtered)
+	}
+}
+
+func TestComputePhaseStats(t *testing.T) {
+	histories := []PhaseHistory{
+		{Phase: "planning", Duration: 4 * time.Hour},
+		{Phase: "discovery", Duration: 5 * time.Hour, Outcome: HistoryFailed},
+		{Phase: "discovery", Duration: time.Hour, Outcome: HistoryCompleted, Attempts: []AttemptRecord{{Attempt: 1}, {Attempt: 2}}},
+		{Phase: "discovery", Duration: 3 * time.Hour, Outcome: HistoryCompleted, Attempts: []AttemptRecord{{Attempt: 1}}},
+	}
+
+	stats := ComputePhaseStats(histories)
+	if len(stats) != 2 || stats[0].Phase != "discovery" || stats[1].Phase != "planning" {
+		t.Fatalf("Expected stats in phase order, got %+v", stats)
+	}
+
+	d := stats[0]
+	if d.Completions != 2 || d.Attempts != 3 {
+		t.Errorf("Expected 2 completions and 3 attempts, got %d and %d", d.Completions, d.Attempts)
+	}
+	if d.Total != 4*time.Hour || d.Average != 2*time.Hour || d.Shortest != time.Hour || d.Longest != 3*time.Hour {
+		t.Errorf("Unexpected durations: %+v", d)
+	}
+}
+
+func TestPhaseHistoryCompleted(t *testing.T) {
+	tests := []struct {
+		name    string
+		history PhaseHistory
+		want    bool
+	}{
+		{"explicit completion", PhaseHistory{Outcome: HistoryCompleted}, true},
+		{"explicit failure", PhaseHistory{Outcome: HistoryFailed, Attempts: []AttemptRecord{{Verdict: "passed"}}}, false},
+		{"legacy without attempts", PhaseHistory{}, true},
+		{"legacy passed", PhaseHistory{Attempts: []AttemptRecord{{Verdict: "failed"}, {Verdict: "passed"}}}, true},
+		{"legacy error", PhaseHistory{Attempts: []AttemptRecord{{Verdict: "error"}}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.history.Completed(); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestNewHistoryPathAvoidsCollisions(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.yaml")
+	if err := os.MkdirAll(HistoryDir(statePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	first := NewHistoryPath(statePath, "discovery", at)
+	writeFile(t, first, "phase: discovery\n")
+	second := NewHistoryPath(statePath, "discovery", at)
+
+	if first == second {
+		t.Fatalf("Expected a new path for a run ending in the same second, got %s twice", first)
+	}
+	if filepath.Base(second) != "discovery_20260401_100000_2.yaml" {
+		t.Errorf("Unexpected history file name: %s", filepath.Base(second))
+	}
+}
+
+func TestUniqueAttemptsAcrossRuns(t *testing.T) {
+	start := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	failed := AttemptRecord{Attempt: 1, StartedAt: start, Verdict: "failed"}
+	passed := AttemptRecord{Attempt: 2, StartedAt: start.Add(time.Minute), Verdict: "passed"}
+
+	attempts := UniqueAttempts([]PhaseHistory{
+		{Phase: "discovery", Outcome: HistoryFailed, Attempts: []AttemptRecord{failed}},
+		{Phase: "discovery", Outcome: HistoryCompleted, Attempts: []AttemptRecord{failed, passed}},
+	})
+	if len(attempts) != 2 || attempts[0].Verdict != "failed" || attempts[1].Verdict != "passed" {
+		t.Errorf("Expected each attempt once, got %+v", attempts)
+	}
+}
@@ -8,6 +8,7 @@ type Phase struct {
 	ToolReason  string
 	Checkpoint  Checkpoint
 	Artifacts   []string
+	DependsOn   []string // Phases that must complete before this one
 }
 
 // Checkpoint defines validation criteria for completing a phase
@@ -15,7 +16,10 @@ type Checkpoint struct {
 	Criteria []string
 }
 
-// AllPhases defines the ordered list of development phases
+// AllPhases defines the ordered list of development phases.
+// DependsOn forms a DAG used by forge auto to run independent phases
+// concurrently; deployment docs only need the implementation, so they can
+// be produced alongside testing.
 var AllPhases = []Phase{
 	{
 		Name:        "discovery",
@@ -54,6 +58,7 @@ var AllPhases = []Phase{
 			"components.md",
 			"tech_decisions.md",
 		},
+		DependsOn: []string{"discovery"},
 	},
 	{
 		Name:        "design",
@@ -73,6 +78,7 @@ var AllPhases = []Phase{
 			"data_models.md",
 			"interfaces.md",
 		},
+		DependsOn: []string{"planning"},
 	},
 	{
 		Name:        "implementation",
@@ -91,6 +97,7 @@ var AllPhases = []Phase{
 			"implementation_notes.md",
 			"code_review.md",
 		},
+		DependsOn: []string{"design"},
 	},
 	{
 		Name:        "testing",
@@ -109,6 +116,7 @@ var AllPhases = []Phase{
 			"test_plan.md",
 			"coverage_report.md",
 		},
+		DependsOn: []string{"implementation"},
 	},
 	{
 		Name:        "deployment",
@@ -128,6 +136,7 @@ var AllPhases = []Phase{
 			"release_notes.md",
 			"deployment_guide.md",
 		},
+		DependsOn: []string{"implementation"},
 	},
 }
 
@@ -0,0 +1,238 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PhaseGraph holds the dependencies between phases
+type PhaseGraph struct {
+	deps map[string][]string
+}
+
+// NewPhaseGraph builds the phase dependency graph from the built-in phase
+// definitions, with per-phase overrides replacing the default dependencies.
+// It fails on unknown phases and dependency cycles.
+func NewPhaseGraph(overrides map[string][]string) (*PhaseGraph, error) {
+	g := &PhaseGraph{deps: make(map[string][]string, len(AllPhases))}
+	for _, p := range AllPhases {
+		g.deps[p.Name] = p.DependsOn
+	}
+
+	for phase, deps := range overrides {
+		if !IsValidPhase(phase) {
+			return nil, fmt.Errorf("unknown phase in depends_on: %s", phase)
+		}
+		for _, dep := range deps {
+			if !IsValidPhase(dep) {
+				return nil, fmt.Errorf("phase %s depends on unknown phase: %s", phase, dep)
+			}
+		}
+		g.deps[phase] = deps
+	}
+
+	if cycle := g.findCycle(); cycle != nil {
+		return nil, fmt.Errorf("phase dependency cycle: %s", strings.Join(cycle, " → "))
+	}
+
+	return g, nil
+}
+
+// DependsOn returns the direct dependencies of a phase
+func (g *PhaseGraph) DependsOn(phase string) []string {
+	return g.deps[phase]
+}
+
+// Dependencies returns the phases that phase depends on directly or
+// transitively, in AllPhases order
+func (g *PhaseGraph) Dependencies(phase string) []string {
+	required := make(map[string]bool)
+	pending := append([]string(nil), g.deps[phase]...)
+	for len(pending) > 0 {
+		p := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if required[p] {
+			continue
+		}
+		required[p] = true
+		pending = append(pending, g.deps[p]...)
+	}
+
+	var dependencies []string
+	for _, p := range PhaseNames() {
+		if required[p] {
+			dependencies = append(dependencies, p)
+		}
+	}
+	return dependencies
+}
+
+// Dependents returns the phases that depend on phase directly or
+// transitively, in AllPhases order
+func (g *PhaseGraph) Dependents(phase string) []string {
+	affected := map[string]bool{phase: true}
+	var dependents []string
+	// AllPhases order does not guarantee dependencies come first once
+	// overridden, so repeat until no new dependent is found
+	for changed := true; changed; {
+		changed = false
+		for _, p := range PhaseNames() {
+			if affected[p] {
+				continue
+			}
+			for _, dep := range g.deps[p] {
+				if affected[dep] {
+					affected[p] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	for _, p := range PhaseNames() {
+		if p != phase && affected[p] {
+			dependents = append(dependents, p)
+		}
+	}
+	return dependents
+}
+
+// Range returns the phases between from and until in AllPhases order: from
+// and the phases depending on it, limited to until and the phases it depends
+// on. An empty from or until leaves that end open. The result is empty when
+// until does not depend on from.
+func (g *PhaseGraph) Range(from, until string) []string {
+	inRange := func(set []string, phase, bound string) bool {
+		if bound == "" || phase == bound {
+			return true
+		}
+		for _, p := range set {
+			if p == phase {
+				return true
+			}
+		}
+		return false
+	}
+
+	var after, before []string
+	if from != "" {
+		after = g.Dependents(from)
+	}
+	if until != "" {
+		before = g.Dependencies(until)
+	}
+
+	var phases []string
+	for _, p := range PhaseNames() {
+		if inRange(after, p, from) && inRange(before, p, until) {
+			phases = append(phases, p)
+		}
+	}
+	return phases
+}
+
+// Ready returns the phases that are not completed but whose dependencies
+// all are, in AllPhases order
+func (g *PhaseGraph) Ready(state *ProjectState) []string {
+	var ready []string
+	for _, p := range PhaseNames() {
+		if state.GetPhaseStatus(p) == "completed" {
+			continue
+		}
+		satisfied := true
+		for _, dep := range g.deps[p] {
+			if state.GetPhaseStatus(dep) != "completed" {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			ready = append(ready, p)
+		}
+	}
+	return ready
+}
+
+// Waves groups the given phases into batches that can run concurrently.
+// Each wave only depends on phases in earlier waves; dependencies outside
+// the given set are treated as already satisfied. Phases keep their
+// AllPhases order within a wave.
+func (g *PhaseGraph) Waves(phases []string) [][]string {
+	pending := make(map[string]bool, len(phases))
+	for _, p := range phases {
+		pending[p] = true
+	}
+
+	var waves [][]string
+	for len(pending) > 0 {
+		var wave []string
+		for _, p := range PhaseNames() {
+			if !pending[p] {
+				continue
+			}
+			ready := true
+			for _, dep := range g.deps[p] {
+				if pending[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				wave = append(wave, p)
+			}
+		}
+		if len(wave) == 0 {
+			// Unreachable for a validated graph
+			break
+		}
+		for _, p := range wave {
+			delete(pending, p)
+		}
+		waves = append(waves, wave)
+	}
+
+	return waves
+}
+
+// findCycle returns the phases forming a dependency cycle, if any
+func (g *PhaseGraph) findCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	marks := make(map[string]int, len(g.deps))
+	var stack []string
+
+	var visit func(phase string) []string
+	visit = func(phase string) []string {
+		marks[phase] = visiting
+		stack = append(stack, phase)
+		for _, dep := range g.deps[phase] {
+			switch marks[dep] {
+			case visiting:
+				for i, p := range stack {
+					if p == dep {
+						return append(append([]string{}, stack[i:]...), dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		marks[phase] = done
+		return nil
+	}
+
+	for _, p := range PhaseNames() {
+		if marks[p] == unvisited {
+			if cycle := visit(p); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDefaultPhaseGraphWaves(t *testing.T) {
+	graph, err := NewPhaseGraph(nil)
+	if err != nil {
+		t.Fatalf("Expected default phases to form a valid graph, got %v", err)
+	}
+
+	waves := graph.Waves(PhaseNames())
+	expected := [][]string{
+		{"discovery"},
+		{"planning"},
+		{"design"},
+		{"implementation"},
+		{"testing", "deployment"},
+	}
+	if !reflect.DeepEqual(waves, expected) {
+		t.Errorf("Expected waves %v, got %v", expected, waves)
+	}
+}
+
+func TestPhaseGraphWavesSubset(t *testing.T) {
+	graph, _ := NewPhaseGraph(nil)
+
+	// Dependencies outside the subset are treated as satisfied
+	waves := graph.Waves([]string{"design", "implementation"})
+	expected := [][]string{{"design"}, {"implementation"}}
+	if !reflect.DeepEqual(waves, expected) {
+		t.Errorf("Expected waves %v, got %v", expected, waves)
+	}
+}
+
+func TestPhaseGraphOverrides(t *testing.T) {
+	graph, err := NewPhaseGraph(map[string][]string{"deployment": {"testing"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if deps := graph.DependsOn("deployment"); len(deps) != 1 || deps[0] != "testing" {
+		t.Errorf("Expected deployment to depend on testing, got %v", deps)
+	}
+	if waves := graph.Waves(PhaseNames()); len(waves) != 6 {
+		t.Errorf("Expected a fully sequential plan, got %v", waves)
+	}
+}
+
+func TestPhaseGraphCycle(t *testing.T) {
+	_, err := NewPhaseGraph(map[string][]string{"discovery": {"design"}})
+	if err == nil {
+		t.Fatal("Expected cycle to be detected")
+	}
+	if !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("Expected cycle error, got %v", err)
+	}
+}
+
+func TestPhaseGraphUnknownPhase(t *testing.T) {
+	if _, err := NewPhaseGraph(map[string][]string{"review": {"design"}}); err == nil {
+		t.Error("Expected unknown phase to be rejected")
+	}
+	if _, err := NewPhaseGraph(map[string][]string{"design": {"review"}}); err == nil {
+		t.Error("Expected unknown dependency to be rejected")
+	}
+}
+
+func TestPhaseGraphDependents(t *testing.T) {
+	graph, _ := NewPhaseGraph(nil)
+
+	expected := []string{"implementation", "testing", "deployment"}
+	if got := graph.Dependents("design"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected dependents %v, got %v", expected, got)
+	}
+	if got := graph.Dependents("deployment"); len(got) != 0 {
+		t.Errorf("Expected no dependents of deployment, got %v", got)
+	}
+
+	// Overrides may point at a later phase in AllPhases order
+	graph, _ = NewPhaseGraph(map[string][]string{"planning": {"testing"}, "testing": {"discovery"}})
+	expected = []string{"planning", "design", "implementation", "testing", "deployment"}
+	if got := graph.Dependents("discovery"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected dependents %v, got %v", expected, got)
+	}
+}
+
+func TestPhaseGraphRange(t *testing.T) {
+	graph, _ := NewPhaseGraph(nil)
+
+	tests := []struct {
+		from, until string
+		expected    []string
+	}{
+		{"", "", PhaseNames()},
+		{"design", "", []string{"design", "implementation", "testing", "deployment"}},
+		// testing and deployment both depend on implementation, not on each other
+		{"testing", "", []string{"testing"}},
+		{"", "deployment", []string{"discovery", "planning", "design", "implementation", "deployment"}},
+		{"planning", "design", []string{"planning", "design"}},
+		{"testing", "deployment", nil},
+	}
+	for _, tt := range tests {
+		if got := graph.Range(tt.from, tt.until); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Range(%q, %q): expected %v, got %v", tt.from, tt.until, tt.expected, got)
+		}
+	}
+}
+
+func TestPhaseGraphReady(t *testing.T) {
+	graph, _ := NewPhaseGraph(nil)
+	state := NewProjectState()
+	for _, p := range []string{"discovery", "planning", "design", "implementation", "deployment"} {
+		state.SetPhaseStatus(p, "completed")
+	}
+
+	if got := graph.Ready(state); !reflect.DeepEqual(got, []string{"testing"}) {
+		t.Errorf("Expected testing to be ready, got %v", got)
+	}
+}
@@ -2,7 +2,9 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"sync"
 
 	"github.com/rice0649/fabric-lite/internal/providers"
@@ -10,20 +12,22 @@ import (
 
 var (
 	defaultProviderManager *ProviderManager
-	pmOnce                 sync.Once
+	pmMutex                sync.RWMutex
 )
 
-// SetDefaultProviderManager sets the global ProviderManager instance.
-// This should only be called once during application initialization.
+// SetDefaultProviderManager sets the global ProviderManager instance,
+// replacing any earlier one
 func SetDefaultProviderManager(pm *ProviderManager) {
-	pmOnce.Do(func() {
-		defaultProviderManager = pm
-	})
+	pmMutex.Lock()
+	defer pmMutex.Unlock()
+	defaultProviderManager = pm
 }
 
 // GetDefaultProviderManager returns the global ProviderManager instance.
 // Panics if the manager has not been set.
 func GetDefaultProviderManager() *ProviderManager {
+	pmMutex.RLock()
+	defer pmMutex.RUnlock()
 	if defaultProviderManager == nil {
 		panic("Default ProviderManager has not been initialized. Call SetDefaultProviderManager first.")
 	}
@@ -33,6 +37,7 @@ func GetDefaultProviderManager() *ProviderManager {
 // ProviderManager manages AI provider instances and their lifecycle
 type ProviderManager struct {
 	providers   map[string]providers.Provider
+	initErrors  map[string]error // Providers that failed to initialize
 	config      *providers.Config
 	mutex       sync.RWMutex
 	initialized bool
@@ -42,12 +47,15 @@ type ProviderManager struct {
 // NewProviderManager creates a new provider manager
 func NewProviderManager(config *providers.Config) *ProviderManager {
 	return &ProviderManager{
-		providers: make(map[string]providers.Provider),
-		config:    config,
+		providers:  make(map[string]providers.Provider),
+		initErrors: make(map[string]error),
+		config:     config,
 	}
 }
 
-// InitializeAll creates and registers all configured providers
+// InitializeAll creates and registers all configured providers. A provider
+// with an unknown type is an error; other providers that fail to initialize
+// are skipped with a warning and reported by InitErrors.
 func (pm *ProviderManager) InitializeAll() error {
 	pm.mutex.Lock()
 	defer pm.mutex.Unlock()
@@ -58,9 +66,13 @@ func (pm *ProviderManager) InitializeAll() error {
 
 	for _, providerConfig := range pm.config.Providers {
 		provider, err := providers.NewProvider(providerConfig)
+		if errors.Is(err, providers.ErrUnknownProviderType) {
+			return fmt.Errorf("provider %s: %w", providerConfig.Name, err)
+		}
 		if err != nil {
 			// Log warning but continue with other providers
-			fmt.Printf("Warning: failed to create provider %s: %v\n", providerConfig.Name, err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to create provider %s: %v\n", providerConfig.Name, err)
+			pm.initErrors[providerConfig.Name] = err
 			continue
 		}
 		pm.providers[providerConfig.Name] = provider
@@ -70,6 +82,18 @@ func (pm *ProviderManager) InitializeAll() error {
 	return nil
 }
 
+// InitErrors returns the providers that failed to initialize and why
+func (pm *ProviderManager) InitErrors() map[string]error {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+
+	errs := make(map[string]error, len(pm.initErrors))
+	for name, err := range pm.initErrors {
+		errs[name] = err
+	}
+	return errs
+}
+
 // Get returns a specific provider by name
 func (pm *ProviderManager) Get(name string) (providers.Provider, error) {
 	pm.mutex.RLock()
@@ -188,6 +212,15 @@ func (pm *ProviderManager) ReloadProvider(name string) error {
 	return nil
 }
 
+// Configs returns the configuration of every provider the manager was
+// created with
+func (pm *ProviderManager) Configs() []providers.ProviderConfig {
+	if pm.config == nil {
+		return nil
+	}
+	return append([]providers.ProviderConfig(nil), pm.config.Providers...)
+}
+
 // GetConfigForProvider returns the configuration for a specific provider
 func (pm *ProviderManager) GetConfigForProvider(name string) (*providers.ProviderConfig, error) {
 	if pm.config == nil {
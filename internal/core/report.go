@@ -0,0 +1,192 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PhaseMetrics summarizes the effort spent on a phase
+type PhaseMetrics struct {
+	Phase              string
+	Status             string        // Empty for aggregated metrics
+	TimeInPhase        time.Duration // Completed runs plus the elapsed time of an active phase
+	Completions        int
+	Reopens            int // Times the phase was reopened or reset, from the activity log
+	ValidationFailures int // Attempts rejected by checkpoint validation
+	ValidatedAttempts  int // Attempts that reached a passed or failed verdict
+	PassedAttempts     int
+	Tokens             int
+	Cost               float64
+}
+
+// Rework counts reopened phases and failed validations
+func (m PhaseMetrics) Rework() int {
+	return m.Reopens + m.ValidationFailures
+}
+
+// PassRate returns the share of validated attempts that passed, and false
+// when no attempt was validated
+func (m PhaseMetrics) PassRate() (float64, bool) {
+	if m.ValidatedAttempts == 0 {
+		return 0, false
+	}
+	return float64(m.PassedAttempts) / float64(m.ValidatedAttempts), true
+}
+
+// add accumulates o into m
+func (m *PhaseMetrics) add(o PhaseMetrics) {
+	m.TimeInPhase += o.TimeInPhase
+	m.Completions += o.Completions
+	m.Reopens += o.Reopens
+	m.ValidationFailures += o.ValidationFailures
+	m.ValidatedAttempts += o.ValidatedAttempts
+	m.PassedAttempts += o.PassedAttempts
+	m.Tokens += o.Tokens
+	m.Cost += o.Cost
+}
+
+// ProjectReport holds the metrics of one forge project
+type ProjectReport struct {
+	Name     string
+	Dir      string
+	Phases   []PhaseMetrics // In phase order
+	Total    PhaseMetrics
+	Warnings []string // Problems that did not stop the report, e.g. unreadable history files
+}
+
+// BuildProjectReport computes phase metrics for the forge project in dir
+// from its state, phase history files and attempt records. Elapsed time of
+// an active phase is measured up to now.
+func BuildProjectReport(dir string, now time.Time) (*ProjectReport, error) {
+	forgeDir := filepath.Join(dir, ".forge")
+	statePath := filepath.Join(forgeDir, "state.yaml")
+
+	state, err := LoadProjectState(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s is not a forge project", dir)
+		}
+		return nil, err
+	}
+
+	report := &ProjectReport{Name: filepath.Base(dir), Dir: dir}
+	var costPer1K float64
+	if cfg, err := LoadProjectConfig(filepath.Join(forgeDir, "config.yaml")); err == nil {
+		if cfg.Name != "" {
+			report.Name = cfg.Name
+		}
+		costPer1K = cfg.Advanced.CostPer1KTokens
+	} else if !os.IsNotExist(err) {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("config: %v", err))
+	}
+
+	histories, err := LoadPhaseHistories(HistoryDir(statePath))
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("history: %v", err))
+	}
+
+	byPhase := make(map[string]*PhaseMetrics)
+	for _, name := range PhaseNames() {
+		byPhase[name] = &PhaseMetrics{Phase: name, Status: state.GetPhaseStatus(name)}
+	}
+
+	for _, h := range histories {
+		m, ok := byPhase[h.Phase]
+		if !ok || !h.Completed() {
+			continue
+		}
+		m.Completions++
+		m.TimeInPhase += h.Duration
+	}
+
+	// Attempts of runs that have not been written to history yet live in state
+	sources := append([]PhaseHistory(nil), histories...)
+	if state.Auto != nil {
+		for phase, attempts := range state.Auto.Attempts {
+			sources = append(sources, PhaseHistory{Phase: phase, Attempts: attempts})
+		}
+	}
+	for _, a := range UniqueAttempts(sources) {
+		m, ok := byPhase[a.Phase]
+		if !ok {
+			continue
+		}
+		switch a.Verdict {
+		case "passed":
+			m.ValidatedAttempts++
+			m.PassedAttempts++
+		case "failed":
+			m.ValidatedAttempts++
+			m.ValidationFailures++
+		}
+		m.Tokens += a.Tokens
+	}
+
+	for _, a := range state.Activities {
+		if phase, ok := rolledBackPhase(a.Message); ok {
+			if m, ok := byPhase[phase]; ok {
+				m.Reopens++
+			}
+		}
+	}
+
+	if m, ok := byPhase[state.CurrentPhase]; ok && !state.PhaseStartedAt.IsZero() {
+		m.TimeInPhase += now.Sub(state.PhaseStartedAt)
+	}
+
+	report.Total = PhaseMetrics{Phase: "total"}
+	for _, name := range PhaseNames() {
+		m := byPhase[name]
+		m.Cost = float64(m.Tokens) / 1000 * costPer1K
+		report.Phases = append(report.Phases, *m)
+		report.Total.add(*m)
+	}
+
+	return report, nil
+}
+
+// rolledBackPhase returns the phase named by a "Reopened phase: X" or
+// "Reset phase: X ..." activity, as logged by ReopenPhase and ResetPhase
+func rolledBackPhase(message string) (string, bool) {
+	if phase, ok := strings.CutPrefix(message, "Reopened phase: "); ok {
+		return phase, true
+	}
+	if rest, ok := strings.CutPrefix(message, "Reset phase: "); ok {
+		phase, _, _ := strings.Cut(rest, " ")
+		return phase, true
+	}
+	return "", false
+}
+
+// AggregateReports sums phase metrics across projects
+func AggregateReports(reports []*ProjectReport) ([]PhaseMetrics, PhaseMetrics) {
+	byPhase := make(map[string]*PhaseMetrics)
+	for _, name := range PhaseNames() {
+		byPhase[name] = &PhaseMetrics{Phase: name}
+	}
+
+	total := PhaseMetrics{Phase: "total"}
+	for _, r := range reports {
+		for _, m := range r.Phases {
+			if agg, ok := byPhase[m.Phase]; ok {
+				agg.add(m)
+			}
+		}
+		total.add(r.Total)
+	}
+
+	var phases []PhaseMetrics
+	for _, name := range PhaseNames() {
+		phases = append(phases, *byPhase[name])
+	}
+	return phases, total
+}
+
+// EstimateTokens approximates the token count of text for tools that do
+// not report usage (about four characters per token)
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
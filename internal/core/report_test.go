@@ -0,0 +1,159 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// seedReportProject writes a forge project with two discovery completions
+// around one reopen, one failed validation and an active planning phase
+func seedReportProject(t *testing.T, dir string, costPer1K float64) time.Time {
+	t.Helper()
+	forgeDir := filepath.Join(dir, ".forge")
+	statePath := filepath.Join(forgeDir, "state.yaml")
+	if err := os.MkdirAll(HistoryDir(statePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := NewProjectConfig("demo", "cli")
+	cfg.Advanced.CostPer1KTokens = costPer1K
+	if err := cfg.Save(filepath.Join(forgeDir, "config.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	start := now.Add(-10 * time.Hour)
+	failed := AttemptRecord{Attempt: 1, StartedAt: start, CompletedAt: start.Add(time.Minute), Verdict: "failed", Tokens: 1000}
+	passed := AttemptRecord{Attempt: 2, StartedAt: start.Add(time.Hour), CompletedAt: start.Add(2 * time.Hour), Verdict: "passed", Tokens: 3000}
+
+	for _, h := range []PhaseHistory{
+		{Phase: "discovery", CompletedAt: start.Add(time.Minute), Duration: time.Minute, Outcome: HistoryFailed, Attempts: []AttemptRecord{failed}},
+		{Phase: "discovery", CompletedAt: start.Add(2 * time.Hour), Duration: 2 * time.Hour, Outcome: HistoryCompleted, Attempts: []AttemptRecord{failed, passed}},
+		{Phase: "discovery", CompletedAt: start.Add(4 * time.Hour), Duration: time.Hour, Outcome: HistoryCompleted},
+	} {
+		if err := h.Save(NewHistoryPath(statePath, h.Phase, h.CompletedAt)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	state := NewProjectState()
+	state.addPhaseActivity(start.Add(3*time.Hour), "discovery", "Reopened phase: discovery")
+	state.SetPhaseStatus("discovery", "completed")
+	state.SetPhaseStatus("planning", "in_progress")
+	state.CurrentPhase = "planning"
+	state.PhaseStartedAt = now.Add(-30 * time.Minute)
+	if err := state.Save(statePath); err != nil {
+		t.Fatal(err)
+	}
+	return now
+}
+
+func TestBuildProjectReport(t *testing.T) {
+	dir := t.TempDir()
+	now := seedReportProject(t, dir, 0.5)
+
+	report, err := BuildProjectReport(dir, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.Name != "demo" || len(report.Phases) != len(AllPhases) {
+		t.Fatalf("Unexpected report: %+v", report)
+	}
+
+	discovery := report.Phases[0]
+	if discovery.Completions != 2 || discovery.Reopens != 1 {
+		t.Errorf("Expected 2 completions and 1 reopen, got %d and %d", discovery.Completions, discovery.Reopens)
+	}
+	if discovery.TimeInPhase != 3*time.Hour {
+		t.Errorf("Expected failed runs to be left out of time in phase, got %v", discovery.TimeInPhase)
+	}
+	if discovery.ValidationFailures != 1 || discovery.Rework() != 2 {
+		t.Errorf("Expected 1 validation failure and rework 2, got %d and %d", discovery.ValidationFailures, discovery.Rework())
+	}
+	if rate, ok := discovery.PassRate(); !ok || rate != 0.5 {
+		t.Errorf("Expected a 50%% pass rate, got %v (%v)", rate, ok)
+	}
+	if discovery.Tokens != 4000 || discovery.Cost != 2 {
+		t.Errorf("Expected 4000 tokens costing 2, got %d and %v", discovery.Tokens, discovery.Cost)
+	}
+
+	planning := report.Phases[1]
+	if planning.Status != "in_progress" || planning.TimeInPhase != 30*time.Minute {
+		t.Errorf("Expected active planning phase with 30m elapsed, got %+v", planning)
+	}
+	if _, ok := planning.PassRate(); ok {
+		t.Error("Expected no pass rate without validated attempts")
+	}
+
+	if report.Total.Completions != 2 || report.Total.TimeInPhase != 3*time.Hour+30*time.Minute {
+		t.Errorf("Unexpected totals: %+v", report.Total)
+	}
+}
+
+func TestBuildProjectReportCountsPendingReopens(t *testing.T) {
+	dir := t.TempDir()
+	now := seedReportProject(t, dir, 0)
+
+	// Reopen discovery and reset planning without completing either again
+	statePath := filepath.Join(dir, ".forge", "state.yaml")
+	_, err := UpdateProjectState(statePath, func(s *ProjectState) error {
+		graph, _ := NewPhaseGraph(nil)
+		_, err := ReopenPhase(s, graph, "discovery", RollbackOptions{Downstream: true, Force: true}, now)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := BuildProjectReport(dir, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	discovery, planning := report.Phases[0], report.Phases[1]
+	if discovery.Completions != 2 || discovery.Reopens != 2 {
+		t.Errorf("Expected 2 completions and 2 reopens, got %d and %d", discovery.Completions, discovery.Reopens)
+	}
+	if planning.Completions != 0 || planning.Reopens != 1 {
+		t.Errorf("Expected the reset planning phase to count as reopened, got %+v", planning)
+	}
+}
+
+func TestBuildProjectReportRequiresForgeProject(t *testing.T) {
+	if _, err := BuildProjectReport(t.TempDir(), time.Now()); err == nil {
+		t.Error("Expected an error for a directory without .forge")
+	}
+}
+
+func TestAggregateReports(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	now := seedReportProject(t, first, 0)
+	seedReportProject(t, second, 0)
+
+	var reports []*ProjectReport
+	for _, dir := range []string{first, second} {
+		report, err := BuildProjectReport(dir, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reports = append(reports, report)
+	}
+
+	phases, total := AggregateReports(reports)
+	if phases[0].Completions != 4 || phases[0].ValidationFailures != 2 || phases[0].Status != "" {
+		t.Errorf("Unexpected aggregated discovery metrics: %+v", phases[0])
+	}
+	if total.Tokens != 8000 {
+		t.Errorf("Expected 8000 tokens in total, got %d", total.Tokens)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	if got := EstimateTokens(""); got != 0 {
+		t.Errorf("Expected 0 tokens for empty text, got %d", got)
+	}
+	if got := EstimateTokens("abcdefgh"); got != 2 {
+		t.Errorf("Expected 2 tokens for 8 characters, got %d", got)
+	}
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// RollbackOptions controls how a phase is reopened or reset
+type RollbackOptions struct {
+	Downstream bool // Also return dependent phases to pending
+	Force      bool // Reopen even while another phase is active
+}
+
+// RollbackResult describes the state changes made by ReopenPhase or ResetPhase
+type RollbackResult struct {
+	Phase     string
+	Reset     []string // Phases returned to pending, in phase order
+	Archive   []string // Phases whose artifacts should be archived
+	Stale     []string // Completed dependents left untouched without Downstream
+	Displaced string   // Unfinished current phase a forced reopen returned to pending
+}
+
+// ReopenPhase makes a completed (or failed) phase the current phase again,
+// keeping its artifacts. With Downstream, the phases depending on it are
+// reset to pending and their artifacts marked for archiving. With Force, an
+// unfinished current phase is returned to pending, keeping its artifacts.
+func ReopenPhase(state *ProjectState, graph *PhaseGraph, phase string, opts RollbackOptions, now time.Time) (*RollbackResult, error) {
+	if !IsValidPhase(phase) {
+		return nil, fmt.Errorf("invalid phase: %s", phase)
+	}
+	status := state.GetPhaseStatus(phase)
+	if status == "pending" {
+		return nil, fmt.Errorf("phase '%s' has not been started. Use 'forge phase start %s'", phase, phase)
+	}
+	if state.CurrentPhase == phase && status == "in_progress" {
+		return nil, fmt.Errorf("phase '%s' is already in progress", phase)
+	}
+	if state.CurrentPhase != "" && state.CurrentPhase != phase && !opts.Force {
+		return nil, fmt.Errorf("already in phase '%s'. Use 'forge phase complete' first or --force to switch",
+			state.CurrentPhase)
+	}
+
+	result := &RollbackResult{Phase: phase}
+	result.resetDependents(state, graph, opts.Downstream)
+
+	// Dependents reset above are already pending
+	if current := state.CurrentPhase; current != "" && current != phase {
+		if status := state.GetPhaseStatus(current); status != "completed" && status != "pending" {
+			state.SetPhaseStatus(current, "pending")
+			state.addPhaseActivity(now, current, fmt.Sprintf("Paused phase: %s for %s", current, phase))
+			result.Displaced = current
+		}
+	}
+
+	state.CurrentPhase = phase
+	state.PhaseStartedAt = now
+	state.SetPhaseStatus(phase, "in_progress")
+	state.addPhaseActivity(now, phase, fmt.Sprintf("Reopened phase: %s", phase))
+	result.logResets(state, now)
+
+	cleared := append([]string{phase}, result.Reset...)
+	if result.Displaced != "" {
+		cleared = append(cleared, result.Displaced)
+	}
+	state.clearAutoState(cleared)
+	return result, nil
+}
+
+// ResetPhase returns a phase to pending and marks its artifacts for
+// archiving with ArchiveArtifacts. With Downstream, the phases depending on it are reset too.
+func ResetPhase(state *ProjectState, graph *PhaseGraph, phase string, opts RollbackOptions, now time.Time) (*RollbackResult, error) {
+	if !IsValidPhase(phase) {
+		return nil, fmt.Errorf("invalid phase: %s", phase)
+	}
+
+	result := &RollbackResult{Phase: phase}
+	result.resetDependents(state, graph, opts.Downstream)
+	result.Reset = append([]string{phase}, result.Reset...)
+	result.Archive = append([]string{phase}, result.Archive...)
+
+	state.SetPhaseStatus(phase, "pending")
+	result.logResets(state, now)
+
+	for _, p := range result.Reset {
+		if state.CurrentPhase == p {
+			state.CurrentPhase = ""
+			state.PhaseStartedAt = time.Time{}
+		}
+	}
+	state.clearAutoState(result.Reset)
+	return result, nil
+}
+
+// resetDependents resets the non-pending dependents of r.Phase when
+// downstream is set, and otherwise records the completed ones as stale
+func (r *RollbackResult) resetDependents(state *ProjectState, graph *PhaseGraph, downstream bool) {
+	for _, dep := range graph.Dependents(r.Phase) {
+		status := state.GetPhaseStatus(dep)
+		if status == "pending" {
+			continue
+		}
+		if !downstream {
+			if status == "completed" {
+				r.Stale = append(r.Stale, dep)
+			}
+			continue
+		}
+		state.SetPhaseStatus(dep, "pending")
+		r.Reset = append(r.Reset, dep)
+		r.Archive = append(r.Archive, dep)
+	}
+}
+
+// logResets records an activity for every reset phase
+func (r *RollbackResult) logResets(state *ProjectState, now time.Time) {
+	for _, p := range r.Reset {
+		message := fmt.Sprintf("Reset phase: %s", p)
+		if p != r.Phase {
+			message = fmt.Sprintf("Reset phase: %s after %s was rolled back", p, r.Phase)
+		}
+		state.addPhaseActivity(now, p, message)
+	}
+}
+
+// ArchiveArtifacts moves the artifacts of the phases in r.Archive out of
+// the artifact tree. Call it once the rolled back state has been saved, so
+// a failed save leaves the artifacts in place. It returns the archive
+// directory per phase, including the phases archived before an error;
+// phases without artifacts are left out.
+func (r *RollbackResult) ArchiveArtifacts(now time.Time) (map[string]string, error) {
+	archived := make(map[string]string)
+	for _, p := range r.Archive {
+		dir, err := ArchiveArtifacts(p, now)
+		if err != nil {
+			return archived, err
+		}
+		if dir != "" {
+			archived[p] = dir
+		}
+	}
+	return archived, nil
+}
+
+// LogArchives records where ArchiveArtifacts moved each phase's artifacts
+func (r *RollbackResult) LogArchives(state *ProjectState, archived map[string]string, now time.Time) {
+	for _, p := range r.Archive {
+		if dir, ok := archived[p]; ok {
+			state.addPhaseActivity(now, p, fmt.Sprintf("Archived %s artifacts to %s", p, dir))
+		}
+	}
+}
+
+// addPhaseActivity logs an activity for a phase other than the current one
+func (s *ProjectState) addPhaseActivity(t time.Time, phase, message string) {
+	s.Activities = append(s.Activities, Activity{Timestamp: t, Message: message, Phase: phase})
+	s.UpdatedAt = t
+}
+
+// clearAutoState drops the attempts and pending approvals of the given
+// phases so 'forge auto' runs them from scratch, and points
+// LastCompletedPhase at the last phase that is still completed
+func (s *ProjectState) clearAutoState(phases []string) {
+	if s.Auto == nil {
+		return
+	}
+
+	affected := make(map[string]bool, len(phases))
+	for _, p := range phases {
+		affected[p] = true
+		delete(s.Auto.Attempts, p)
+		delete(s.Auto.Approvals, p)
+	}
+
+	if affected[s.Auto.LastCompletedPhase] {
+		s.Auto.LastCompletedPhase = ""
+		for _, p := range PhaseNames() {
+			if s.GetPhaseStatus(p) == "completed" {
+				s.Auto.LastCompletedPhase = p
+			}
+		}
+	}
+	// A failed or waiting run recorded here may belong to a rolled back phase
+	if s.Auto.CurrentPhaseStatus != "completed" {
+		s.Auto.CurrentPhaseStatus = ""
+		s.Auto.Feedback = ""
+	}
+}
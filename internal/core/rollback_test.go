@@ -0,0 +1,195 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// completedState returns a state with the first n phases completed by forge auto
+func completedState(n int) *ProjectState {
+	state := NewProjectState()
+	state.Auto = &AutoState{
+		CurrentPhaseStatus: "completed",
+		Attempts:           make(map[string][]AttemptRecord),
+		Approvals:          make(map[string]*ApprovalState),
+	}
+	for _, p := range PhaseNames()[:n] {
+		state.SetPhaseStatus(p, "completed")
+		state.Auto.LastCompletedPhase = p
+		state.Auto.Attempts[p] = []AttemptRecord{{Attempt: 1, Verdict: "passed"}}
+	}
+	return state
+}
+
+func TestReopenPhase(t *testing.T) {
+	graph, _ := NewPhaseGraph(nil)
+	state := completedState(4)
+	now := time.Now()
+
+	result, err := ReopenPhase(state, graph, "design", RollbackOptions{}, now)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if state.CurrentPhase != "design" || state.GetPhaseStatus("design") != "in_progress" || !state.PhaseStartedAt.Equal(now) {
+		t.Errorf("Expected design to be the current phase, got %s (%s)", state.CurrentPhase, state.GetPhaseStatus("design"))
+	}
+	if !reflect.DeepEqual(result.Stale, []string{"implementation"}) || len(result.Reset) != 0 || len(result.Archive) != 0 {
+		t.Errorf("Expected implementation to be stale and nothing reset, got %+v", result)
+	}
+	if state.GetPhaseStatus("implementation") != "completed" {
+		t.Errorf("Expected implementation to stay completed, got %s", state.GetPhaseStatus("implementation"))
+	}
+	if _, ok := state.Auto.Attempts["design"]; ok {
+		t.Error("Expected design attempts to be cleared")
+	}
+	if state.Auto.LastCompletedPhase != "implementation" {
+		t.Errorf("Expected last completed phase to stay implementation, got %s", state.Auto.LastCompletedPhase)
+	}
+	if last := state.Activities[len(state.Activities)-1]; last.Message != "Reopened phase: design" || last.Phase != "design" {
+		t.Errorf("Expected reopen activity, got %+v", last)
+	}
+
+	if _, err := ReopenPhase(state, graph, "design", RollbackOptions{}, now); err == nil {
+		t.Error("Expected error reopening a phase that is in progress")
+	}
+	if _, err := ReopenPhase(state, graph, "planning", RollbackOptions{}, now); err == nil {
+		t.Error("Expected error reopening while another phase is active")
+	}
+	result, err = ReopenPhase(state, graph, "planning", RollbackOptions{Force: true}, now)
+	if err != nil {
+		t.Fatalf("Expected --force to reopen planning, got %v", err)
+	}
+	if result.Displaced != "design" || state.GetPhaseStatus("design") != "pending" || state.CurrentPhase != "planning" {
+		t.Errorf("Expected design returned to pending, got %+v (design %s)", result, state.GetPhaseStatus("design"))
+	}
+	for _, issue := range state.Check(nil) {
+		if issue.Severity == IssueError {
+			t.Errorf("Expected a consistent state, got %v", issue)
+		}
+	}
+	if _, err := ReopenPhase(completedState(1), graph, "testing", RollbackOptions{}, now); err == nil {
+		t.Error("Expected error reopening a pending phase")
+	}
+}
+
+func TestReopenPhaseDownstream(t *testing.T) {
+	graph, _ := NewPhaseGraph(nil)
+	state := completedState(4)
+	state.Auto.Approvals["implementation"] = &ApprovalState{Status: ApprovalPending}
+
+	result, err := ReopenPhase(state, graph, "design", RollbackOptions{Downstream: true}, time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(result.Reset, []string{"implementation"}) || !reflect.DeepEqual(result.Archive, result.Reset) {
+		t.Errorf("Expected implementation to be reset and archived, got %+v", result)
+	}
+	if state.GetPhaseStatus("implementation") != "pending" {
+		t.Errorf("Expected implementation to be pending, got %s", state.GetPhaseStatus("implementation"))
+	}
+	if _, ok := state.Auto.Approvals["implementation"]; ok {
+		t.Error("Expected the implementation approval to be cleared")
+	}
+	if state.Auto.LastCompletedPhase != "planning" {
+		t.Errorf("Expected last completed phase planning, got %s", state.Auto.LastCompletedPhase)
+	}
+}
+
+func TestResetPhase(t *testing.T) {
+	graph, _ := NewPhaseGraph(nil)
+	state := completedState(2)
+	state.CurrentPhase = "design"
+	state.PhaseStartedAt = time.Now()
+	state.SetPhaseStatus("design", "in_progress")
+	state.Auto.CurrentPhaseStatus = "validation_failed"
+	state.Auto.Feedback = "missing API spec"
+
+	result, err := ResetPhase(state, graph, "planning", RollbackOptions{Downstream: true}, time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if expected := []string{"planning", "design"}; !reflect.DeepEqual(result.Reset, expected) {
+		t.Errorf("Expected reset %v, got %v", expected, result.Reset)
+	}
+	for _, p := range []string{"planning", "design"} {
+		if status := state.GetPhaseStatus(p); status != "pending" {
+			t.Errorf("Expected %s to be pending, got %s", p, status)
+		}
+	}
+	if state.CurrentPhase != "" || !state.PhaseStartedAt.IsZero() {
+		t.Errorf("Expected no current phase, got %s", state.CurrentPhase)
+	}
+	if state.Auto.LastCompletedPhase != "discovery" || state.Auto.CurrentPhaseStatus != "" || state.Auto.Feedback != "" {
+		t.Errorf("Expected auto state to resume after discovery, got %+v", state.Auto)
+	}
+	if issues := state.Check(nil); len(issues) != 0 {
+		t.Errorf("Expected a consistent state, got %v", issues)
+	}
+}
+
+func TestRollbackArchiveArtifacts(t *testing.T) {
+	chdirTemp(t)
+	if _, err := SaveArtifact("planning", "plan.md", "# Plan"); err != nil {
+		t.Fatal(err)
+	}
+
+	graph, _ := NewPhaseGraph(nil)
+	state := completedState(3)
+	now := time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)
+	result, err := ResetPhase(state, graph, "planning", RollbackOptions{Downstream: true}, now)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	archived, err := result.ArchiveArtifacts(now)
+	if err != nil {
+		t.Fatalf("Expected no error archiving, got %v", err)
+	}
+	result.LogArchives(state, archived, now)
+	dir := filepath.Join(ArchiveDir, "planning", "20260302_103000")
+	if len(archived) != 1 || archived["planning"] != dir {
+		t.Errorf("Expected only planning to be archived to %s, got %v", dir, archived)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "plan.md")); err != nil {
+		t.Errorf("Expected plan.md in the archive, got %v", err)
+	}
+	if artifacts, _ := ReadArtifacts("planning", 0); len(artifacts) != 0 {
+		t.Errorf("Expected no planning artifacts after reset, got %d", len(artifacts))
+	}
+	if last := state.Activities[len(state.Activities)-1]; !strings.Contains(last.Message, dir) {
+		t.Errorf("Expected archive activity, got %q", last.Message)
+	}
+}
+
+func TestArchiveArtifactsTwiceInOneSecond(t *testing.T) {
+	chdirTemp(t)
+	now := time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)
+
+	var dirs []string
+	for i := 0; i < 2; i++ {
+		if _, err := SaveArtifact("planning", "plan.md", "# Plan"); err != nil {
+			t.Fatal(err)
+		}
+		dir, err := ArchiveArtifacts("planning", now)
+		if err != nil {
+			t.Fatalf("Expected archive %d to succeed, got %v", i+1, err)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	if dirs[0] == dirs[1] || !strings.HasSuffix(dirs[1], "_2") {
+		t.Errorf("Expected a counter suffix on the second archive, got %v", dirs)
+	}
+	for _, dir := range dirs {
+		if _, err := os.Stat(filepath.Join(dir, "plan.md")); err != nil {
+			t.Errorf("Expected plan.md in %s, got %v", dir, err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema versions written by this version of forge. Files without a
+// schema_version are version 0 and are upgraded by the migrations below.
+const (
+	StateSchemaVersion  = 1
+	ConfigSchemaVersion = 1
+)
+
+// ValidPhaseStatuses lists every status a phase can have in state
+var ValidPhaseStatuses = []string{
+	"pending",
+	"in_progress",
+	"completed",
+	"validation_failed",
+	"awaiting_approval",
+	"rejected",
+}
+
+// Migration upgrades a raw YAML document from schema version From to From+1
+type Migration struct {
+	From        int
+	Description string
+	Migrate     func(doc map[string]interface{}) error
+}
+
+// stateMigrations upgrade .forge/state.yaml; append new migrations here
+// and bump StateSchemaVersion
+var stateMigrations = []Migration{
+	{
+		From:        0,
+		Description: "record phase statuses for every phase",
+		Migrate: func(doc map[string]interface{}) error {
+			statuses, _ := doc["phase_statuses"].(map[string]interface{})
+			if statuses == nil {
+				statuses = make(map[string]interface{})
+			}
+			for _, name := range PhaseNames() {
+				if _, ok := statuses[name]; !ok {
+					statuses[name] = "pending"
+				}
+			}
+			doc["phase_statuses"] = statuses
+			return nil
+		},
+	},
+}
+
+// configMigrations upgrade .forge/config.yaml; append new migrations here
+// and bump ConfigSchemaVersion
+var configMigrations = []Migration{
+	{
+		From:        0,
+		Description: "drop empty phase tool overrides",
+		Migrate: func(doc map[string]interface{}) error {
+			phases, _ := doc["phases"].(map[string]interface{})
+			for name, tool := range phases {
+				if tool == nil || tool == "" {
+					delete(phases, name)
+				}
+			}
+			return nil
+		},
+	},
+}
+
+// decodeVersioned unmarshals a versioned YAML document into out, applying
+// migrations when the document is older than current. It returns the
+// schema version found in the file.
+func decodeVersioned(kind string, data []byte, current int, migrations []Migration, out interface{}) (int, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return 0, err
+	}
+
+	version := 0
+	if v, ok := doc["schema_version"]; ok && v != nil {
+		n, ok := v.(int)
+		if !ok || n < 0 {
+			return 0, fmt.Errorf("invalid %s schema_version: %v", kind, v)
+		}
+		version = n
+	}
+	if version > current {
+		return version, fmt.Errorf("%s schema version %d is newer than supported version %d (upgrade forge)",
+			kind, version, current)
+	}
+
+	if version < current {
+		sorted := append([]Migration(nil), migrations...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].From < sorted[j].From })
+
+		if doc == nil {
+			doc = make(map[string]interface{})
+		}
+		for _, m := range sorted {
+			if m.From < version || m.From >= current {
+				continue
+			}
+			if err := m.Migrate(doc); err != nil {
+				return version, fmt.Errorf("migrate %s from version %d (%s): %w", kind, m.From, m.Description, err)
+			}
+			doc["schema_version"] = m.From + 1
+		}
+		doc["schema_version"] = current
+
+		migrated, err := yaml.Marshal(doc)
+		if err != nil {
+			return version, err
+		}
+		data = migrated
+	}
+
+	return version, yaml.Unmarshal(data, out)
+}
+
+// isValidPhaseStatus reports whether status is a known phase status
+func isValidPhaseStatus(status string) bool {
+	for _, s := range ValidPhaseStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
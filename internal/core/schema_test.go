@@ -0,0 +1,151 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestLoadProjectStateMigratesUnversioned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.yaml")
+	writeFile(t, path, "current_phase: discovery\nphase_statuses:\n  discovery: in_progress\n")
+
+	state, err := LoadProjectState(path)
+	if err != nil {
+		t.Fatalf("Expected unversioned state to load, got %v", err)
+	}
+	if state.SchemaVersion != StateSchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", StateSchemaVersion, state.SchemaVersion)
+	}
+	if state.GetPhaseStatus("discovery") != "in_progress" {
+		t.Errorf("Expected existing status to be kept, got %s", state.GetPhaseStatus("discovery"))
+	}
+	if _, ok := state.PhaseStatuses["deployment"]; !ok {
+		t.Error("Expected migration to add missing phase statuses")
+	}
+
+	// Saving writes the current schema version
+	if err := state.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "schema_version: 1") {
+		t.Error("Expected saved state to record the schema version")
+	}
+}
+
+func TestLoadProjectStateRejectsNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.yaml")
+	writeFile(t, path, "schema_version: 99\ncurrent_phase: \"\"\n")
+
+	if _, err := LoadProjectState(path); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("Expected newer schema error, got %v", err)
+	}
+}
+
+func TestLoadProjectStateValidatesPhases(t *testing.T) {
+	tests := map[string]string{
+		"unknown phase":   "schema_version: 1\nphase_statuses:\n  review: pending\n",
+		"invalid status":  "schema_version: 1\nphase_statuses:\n  discovery: done\n",
+		"unknown current": "schema_version: 1\ncurrent_phase: review\n",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.yaml")
+			writeFile(t, path, content)
+			if _, err := LoadProjectState(path); err == nil {
+				t.Error("Expected invalid state to be rejected")
+			}
+		})
+	}
+}
+
+func TestCheckProjectStateReportsInconsistencies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.yaml")
+	writeFile(t, path, `phase_statuses:
+  discovery: completed
+  planning: pending
+  design: completed
+  review: pending
+current_phase: discovery
+`)
+
+	result, err := CheckProjectState(path, nil)
+	if err != nil {
+		t.Fatalf("Expected check to load invalid state, got %v", err)
+	}
+	if result.SchemaVersion != 0 {
+		t.Errorf("Expected schema version 0 for an unversioned file, got %d", result.SchemaVersion)
+	}
+
+	var messages []string
+	for _, issue := range result.Issues {
+		messages = append(messages, issue.Severity+": "+issue.Message)
+	}
+	all := strings.Join(messages, "\n")
+
+	for _, want := range []string{
+		"error: phase_statuses: unknown phase 'review'",
+		"warning: current phase 'discovery' is already completed",
+		"warning: phase 'design' is completed but its dependency 'planning' is pending",
+		"warning: schema version 0 will be upgraded",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("Expected issue %q in:\n%s", want, all)
+		}
+	}
+	if result.Issues[0].Severity != IssueError {
+		t.Error("Expected errors to be listed first")
+	}
+}
+
+func TestNewProjectStateIsConsistent(t *testing.T) {
+	if issues := NewProjectState().Check(nil); len(issues) != 0 {
+		t.Errorf("Expected no issues for a new state, got %v", issues)
+	}
+}
+
+func TestCheckAcceptsAwaitingApproval(t *testing.T) {
+	state := NewProjectState()
+	state.CurrentPhase = "discovery"
+	state.PhaseStartedAt = time.Now()
+	state.SetPhaseStatus("discovery", "awaiting_approval")
+	if issues := state.Check(nil); len(issues) != 0 {
+		t.Errorf("Expected no issues for a phase awaiting approval, got %v", issues)
+	}
+}
+
+func TestLoadProjectConfigMigratesAndValidates(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, "name: legacy\nphases:\n  discovery:\n  testing: claude\n")
+	cfg, err := LoadProjectConfig(path)
+	if err != nil {
+		t.Fatalf("Expected legacy config to load, got %v", err)
+	}
+	if cfg.SchemaVersion != ConfigSchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", ConfigSchemaVersion, cfg.SchemaVersion)
+	}
+	if _, ok := cfg.Phases["discovery"]; ok {
+		t.Error("Expected empty phase override to be dropped")
+	}
+	if cfg.Phases["testing"] != "claude" {
+		t.Errorf("Expected testing override to be kept, got %q", cfg.Phases["testing"])
+	}
+
+	writeFile(t, path, "name: bad\ngates:\n  review: confirm\n")
+	if _, err := LoadProjectConfig(path); err == nil {
+		t.Error("Expected gate for unknown phase to be rejected")
+	}
+}
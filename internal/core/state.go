@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fmt"
 	"os"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 
 // ProjectState tracks the current state of a forge project
 type ProjectState struct {
+	SchemaVersion  int               `yaml:"schema_version"`
 	CurrentPhase   string            `yaml:"current_phase"`
 	PhaseStartedAt time.Time         `yaml:"phase_started_at,omitempty"`
 	PhaseStatuses  map[string]string `yaml:"phase_statuses"` // phase -> status (pending, in_progress, completed)
@@ -27,6 +29,26 @@ type AutoState struct {
 	SkipValidation     bool      `yaml:"skip_validation,omitempty"`
 	Feedback           string    `yaml:"feedback,omitempty"`
 	StartedAt          time.Time `yaml:"started_at,omitempty"`
+
+	// Attempts records each execution attempt per phase (phase -> attempts)
+	Attempts map[string][]AttemptRecord `yaml:"attempts,omitempty"`
+
+	// Approvals tracks gate decisions that have not been consumed yet (phase -> approval)
+	Approvals map[string]*ApprovalState `yaml:"approvals,omitempty"`
+}
+
+// AttemptRecord describes one execution and validation attempt of a phase
+type AttemptRecord struct {
+	Attempt     int       `yaml:"attempt"`
+	StartedAt   time.Time `yaml:"started_at"`
+	CompletedAt time.Time `yaml:"completed_at,omitempty"`
+	Artifact    string    `yaml:"artifact,omitempty"` // Path of the saved tool output
+	Output      string    `yaml:"output,omitempty"`   // Captured output (history files only)
+	Feedback    string    `yaml:"feedback,omitempty"` // Validation feedback for this attempt
+	Verdict     string    `yaml:"verdict"`            // passed, failed, error, unvalidated
+	Tokens      int       `yaml:"tokens,omitempty"`   // Tokens used by the phase tool, if known
+
+	Criteria []CriterionResult `yaml:"criteria,omitempty"` // Per-criterion validation results
 }
 
 // Activity represents a logged activity in the project
@@ -42,13 +64,18 @@ type PhaseHistory struct {
 	StartedAt   time.Time     `yaml:"started_at"`
 	CompletedAt time.Time     `yaml:"completed_at"`
 	Duration    time.Duration `yaml:"duration"`
+	Outcome     string        `yaml:"outcome,omitempty"` // completed or failed
 	Notes       string        `yaml:"notes,omitempty"`
+	Commit      string        `yaml:"commit,omitempty"` // SHA of the forge commit, with integrations.git.auto_commit
+
+	Attempts []AttemptRecord `yaml:"attempts,omitempty"`
 }
 
 // NewProjectState creates a new project state
 func NewProjectState() *ProjectState {
 	now := time.Now()
 	return &ProjectState{
+		SchemaVersion: StateSchemaVersion,
 		PhaseStatuses: map[string]string{
 			"discovery":      "pending",
 			"planning":       "pending",
@@ -95,14 +122,16 @@ func (s *ProjectState) AddActivity(message string) {
 	s.UpdatedAt = time.Now()
 }
 
-// Save writes the state to a YAML file
+// Save writes the state to a YAML file. The write is atomic, keeps a backup
+// of the previous state and is guarded by the state lock.
 func (s *ProjectState) Save(path string) error {
-	s.UpdatedAt = time.Now()
-	data, err := yaml.Marshal(s)
+	lock, err := LockState(path)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0644)
+	defer lock.Unlock()
+
+	return s.write(path)
 }
 
 // LoadProjectState loads state from a YAML file
@@ -112,16 +141,32 @@ func LoadProjectState(path string) (*ProjectState, error) {
 		return nil, err
 	}
 
-	var state ProjectState
-	if err := yaml.Unmarshal(data, &state); err != nil {
+	state, _, err := decodeState(data)
+	if err != nil {
 		return nil, err
 	}
 
+	if err := state.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid state %s: %w (run 'forge state check')", path, err)
+	}
+
+	return state, nil
+}
+
+// decodeState parses state, migrating older schema versions. It returns
+// the schema version found in the data.
+func decodeState(data []byte) (*ProjectState, int, error) {
+	var state ProjectState
+	version, err := decodeVersioned("state", data, StateSchemaVersion, stateMigrations, &state)
+	if err != nil {
+		return nil, version, err
+	}
+
 	if state.PhaseStatuses == nil {
 		state.PhaseStatuses = make(map[string]string)
 	}
 
-	return &state, nil
+	return &state, version, nil
 }
 
 // Save writes the phase history to a YAML file
@@ -130,5 +175,5 @@ func (h *PhaseHistory) Save(path string) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0644)
+	return writeFileAtomic(path, data, 0644)
 }
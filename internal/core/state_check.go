@@ -0,0 +1,160 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Issue severities reported by state checks
+const (
+	IssueError   = "error"
+	IssueWarning = "warning"
+)
+
+// StateIssue is a problem found in project state
+type StateIssue struct {
+	Severity string
+	Message  string
+}
+
+// Validate checks phase names and statuses against the phase registry
+func (s *ProjectState) Validate() error {
+	var errs []error
+	for _, issue := range s.schemaIssues() {
+		errs = append(errs, errors.New(issue.Message))
+	}
+	return errors.Join(errs...)
+}
+
+// Check reports schema errors and inconsistencies between fields, such as
+// a current phase that is already completed. cfg supplies phase
+// dependencies and may be nil.
+func (s *ProjectState) Check(cfg *ProjectConfig) []StateIssue {
+	issues := s.schemaIssues()
+	warn := func(format string, args ...interface{}) {
+		issues = append(issues, StateIssue{Severity: IssueWarning, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.CurrentPhase != "" && IsValidPhase(s.CurrentPhase) {
+		switch status := s.GetPhaseStatus(s.CurrentPhase); status {
+		case "in_progress", "awaiting_approval":
+		case "completed":
+			warn("current phase '%s' is already completed", s.CurrentPhase)
+		default:
+			warn("current phase '%s' has status '%s', expected in_progress", s.CurrentPhase, status)
+		}
+		if s.PhaseStartedAt.IsZero() {
+			warn("current phase '%s' has no start time", s.CurrentPhase)
+		}
+	}
+	if s.CurrentPhase == "" && !s.PhaseStartedAt.IsZero() {
+		warn("phase_started_at is set but there is no current phase")
+	}
+
+	if graph, err := cfg.PhaseGraph(); err == nil {
+		for _, phase := range PhaseNames() {
+			if s.GetPhaseStatus(phase) != "completed" {
+				continue
+			}
+			for _, dep := range graph.DependsOn(phase) {
+				if s.GetPhaseStatus(dep) != "completed" {
+					warn("phase '%s' is completed but its dependency '%s' is %s", phase, dep, s.GetPhaseStatus(dep))
+				}
+			}
+		}
+	}
+
+	if s.Auto != nil && s.Auto.LastCompletedPhase != "" && IsValidPhase(s.Auto.LastCompletedPhase) {
+		if status := s.GetPhaseStatus(s.Auto.LastCompletedPhase); status != "completed" {
+			warn("auto.last_completed_phase '%s' has status '%s'", s.Auto.LastCompletedPhase, status)
+		}
+	}
+
+	if !s.CreatedAt.IsZero() && s.UpdatedAt.Before(s.CreatedAt) {
+		warn("updated_at is before created_at")
+	}
+
+	return issues
+}
+
+// schemaIssues reports unknown phase names and statuses
+func (s *ProjectState) schemaIssues() []StateIssue {
+	var issues []StateIssue
+	fail := func(format string, args ...interface{}) {
+		issues = append(issues, StateIssue{Severity: IssueError, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.CurrentPhase != "" && !IsValidPhase(s.CurrentPhase) {
+		fail("current_phase: unknown phase '%s'", s.CurrentPhase)
+	}
+
+	phases := make([]string, 0, len(s.PhaseStatuses))
+	for phase := range s.PhaseStatuses {
+		phases = append(phases, phase)
+	}
+	sort.Strings(phases)
+	for _, phase := range phases {
+		if !IsValidPhase(phase) {
+			fail("phase_statuses: unknown phase '%s'", phase)
+			continue
+		}
+		if status := s.PhaseStatuses[phase]; !isValidPhaseStatus(status) {
+			fail("phase_statuses: invalid status '%s' for phase '%s'", status, phase)
+		}
+	}
+
+	if s.Auto != nil {
+		if p := s.Auto.LastCompletedPhase; p != "" && !IsValidPhase(p) {
+			fail("auto.last_completed_phase: unknown phase '%s'", p)
+		}
+		for phase := range s.Auto.Attempts {
+			if !IsValidPhase(phase) {
+				fail("auto.attempts: unknown phase '%s'", phase)
+			}
+		}
+		for phase := range s.Auto.Approvals {
+			if !IsValidPhase(phase) {
+				fail("auto.approvals: unknown phase '%s'", phase)
+			}
+		}
+	}
+
+	return issues
+}
+
+// StateCheckResult is the outcome of checking a state file
+type StateCheckResult struct {
+	SchemaVersion int // Version found in the file (0 = unversioned)
+	State         *ProjectState
+	Issues        []StateIssue
+}
+
+// CheckProjectState loads a state file without rejecting invalid content
+// and reports every issue found, errors first
+func CheckProjectState(path string, cfg *ProjectConfig) (*StateCheckResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	state, version, err := decodeState(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StateCheckResult{SchemaVersion: version, State: state}
+	result.Issues = state.Check(cfg)
+	if version < StateSchemaVersion {
+		result.Issues = append(result.Issues, StateIssue{
+			Severity: IssueWarning,
+			Message:  fmt.Sprintf("schema version %d will be upgraded to %d on the next save", version, StateSchemaVersion),
+		})
+	}
+
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		return result.Issues[i].Severity == IssueError && result.Issues[j].Severity != IssueError
+	})
+	return result, nil
+}
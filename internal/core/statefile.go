@@ -0,0 +1,192 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// stateLockWait is how long to wait for another process to release the state lock
+	stateLockWait = 10 * time.Second
+
+	// staleLockAge is the age after which a lock left by a crashed process is broken
+	staleLockAge = 30 * time.Second
+
+	stateLockPoll = 50 * time.Millisecond
+)
+
+// StateLockPath returns the advisory lock file guarding a state file
+func StateLockPath(statePath string) string {
+	return statePath + ".lock"
+}
+
+// StateBackupPath returns where the previous version of a state file is kept
+func StateBackupPath(statePath string) string {
+	return statePath + ".bak"
+}
+
+// StateLock is an advisory lock on a state file, held by one process at a time
+type StateLock struct {
+	path string
+}
+
+// LockState acquires the advisory lock for a state file, waiting for other
+// holders and breaking locks older than the stale timeout
+func LockState(statePath string) (*StateLock, error) {
+	lockPath := StateLockPath(statePath)
+	deadline := time.Now().Add(stateLockWait)
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return &StateLock{path: lockPath}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("create state lock: %w", err)
+		}
+
+		// A crashed process leaves its lock behind; break it once it is stale
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			breakStaleLock(lockPath, info)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("state is locked by another forge process (remove %s if it is stale)", lockPath)
+		}
+		time.Sleep(stateLockPoll)
+	}
+}
+
+// breakStaleLock removes the stale lock described by stale. The lock is
+// first renamed to a name of our own, so when another process has broken
+// it and taken a new lock in the meantime, that lock is put back instead
+// of being deleted.
+func breakStaleLock(lockPath string, stale os.FileInfo) {
+	broken := fmt.Sprintf("%s.stale-%d-%d", lockPath, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(lockPath, broken); err != nil {
+		return // Already broken or released by someone else
+	}
+	defer os.Remove(broken)
+
+	// Inodes can be reused, so the modification time is compared as well
+	if info, err := os.Stat(broken); err == nil && (!os.SameFile(info, stale) || !info.ModTime().Equal(stale.ModTime())) {
+		// Link fails if yet another process has taken the lock since
+		os.Link(broken, lockPath)
+	}
+}
+
+// Unlock releases the lock
+func (l *StateLock) Unlock() error {
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("release state lock: %w", err)
+	}
+	return nil
+}
+
+// UpdateProjectState loads the state, applies fn and saves the result while
+// holding the state lock, so concurrent forge commands do not lose updates
+func UpdateProjectState(path string, fn func(*ProjectState) error) (*ProjectState, error) {
+	lock, err := LockState(path)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	state, err := LoadProjectState(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(state); err != nil {
+		return nil, err
+	}
+	if err := state.write(path); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// RestoreProjectState replaces the state file with its backup
+func RestoreProjectState(path string) (*ProjectState, error) {
+	lock, err := LockState(path)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	backup := StateBackupPath(path)
+	data, err := os.ReadFile(backup)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no state backup found at %s", backup)
+		}
+		return nil, err
+	}
+
+	state, _, err := decodeState(data)
+	if err != nil {
+		return nil, fmt.Errorf("backup %s is not valid state: %w", backup, err)
+	}
+
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// write backs up the current state file and atomically replaces it.
+// Callers must hold the state lock.
+func (s *ProjectState) write(path string) error {
+	s.SchemaVersion = StateSchemaVersion
+	s.UpdatedAt = time.Now()
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	// Only back up state that still parses, so a corrupt file never replaces a good backup
+	if previous, err := os.ReadFile(path); err == nil {
+		var check ProjectState
+		if yaml.Unmarshal(previous, &check) == nil {
+			if err := writeFileAtomic(StateBackupPath(path), previous, 0644); err != nil {
+				return fmt.Errorf("back up state: %w", err)
+			}
+		}
+	}
+
+	return writeFileAtomic(path, data, 0644)
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory and
+// renames it over path, so readers never see a partially written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
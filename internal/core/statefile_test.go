@@ -0,0 +1,190 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSaveIsAtomicAndKeepsBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.yaml")
+
+	state := NewProjectState()
+	state.CurrentPhase = "discovery"
+	if err := state.Save(path); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+	if _, err := os.Stat(StateBackupPath(path)); !os.IsNotExist(err) {
+		t.Error("Expected no backup after the first save")
+	}
+
+	state.CurrentPhase = "planning"
+	if err := state.Save(path); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+
+	backup, err := LoadProjectState(StateBackupPath(path))
+	if err != nil {
+		t.Fatalf("Failed to load backup: %v", err)
+	}
+	if backup.CurrentPhase != "discovery" {
+		t.Errorf("Expected backup to hold the previous state, got phase '%s'", backup.CurrentPhase)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") || strings.HasSuffix(e.Name(), ".lock") {
+			t.Errorf("Expected no leftover temp or lock files, found %s", e.Name())
+		}
+	}
+}
+
+func TestCorruptStateDoesNotReplaceBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.yaml")
+
+	state := NewProjectState()
+	state.CurrentPhase = "design"
+	if err := state.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("current_phase: [broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProjectState(path); err == nil {
+		t.Fatal("Expected corrupt state to fail to load")
+	}
+
+	restored, err := RestoreProjectState(path)
+	if err != nil {
+		t.Fatalf("Failed to restore state: %v", err)
+	}
+	if restored.CurrentPhase != "design" {
+		t.Errorf("Expected restored phase 'design', got '%s'", restored.CurrentPhase)
+	}
+	if _, err := LoadProjectState(path); err != nil {
+		t.Errorf("Expected restored state to load, got %v", err)
+	}
+}
+
+func TestRestoreWithoutBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.yaml")
+	if _, err := RestoreProjectState(path); err == nil {
+		t.Error("Expected error when no backup exists")
+	}
+}
+
+func TestUpdateProjectStateConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.yaml")
+	if err := NewProjectState().Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	const writers = 10
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := UpdateProjectState(path, func(s *ProjectState) error {
+				s.AddActivity(fmt.Sprintf("writer %d", i))
+				return nil
+			})
+			if err != nil {
+				t.Errorf("Update failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	state, err := LoadProjectState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One activity from NewProjectState plus one per writer
+	if len(state.Activities) != writers+1 {
+		t.Errorf("Expected %d activities, got %d (lost updates)", writers+1, len(state.Activities))
+	}
+}
+
+func TestLockStateWaitsAndBreaksStaleLocks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.yaml")
+
+	oldWait, oldStale := stateLockWait, staleLockAge
+	stateLockWait, staleLockAge = 200*time.Millisecond, time.Hour
+	t.Cleanup(func() { stateLockWait, staleLockAge = oldWait, oldStale })
+
+	lock, err := LockState(path)
+	if err != nil {
+		t.Fatalf("Failed to lock: %v", err)
+	}
+	if _, err := LockState(path); err == nil {
+		t.Fatal("Expected second lock to time out while the first is held")
+	}
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A lock older than the stale age is broken
+	if err := os.WriteFile(StateLockPath(path), []byte("12345\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(StateLockPath(path), old, old)
+
+	lock, err = LockState(path)
+	if err != nil {
+		t.Fatalf("Expected stale lock to be broken, got %v", err)
+	}
+	lock.Unlock()
+}
+
+func TestBreakStaleLockKeepsReplacedLock(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := StateLockPath(filepath.Join(dir, "state.yaml"))
+	if err := os.WriteFile(lockPath, []byte("12345\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(lockPath, old, old)
+	stale, err := os.Stat(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process breaks the stale lock and takes a new one first
+	os.Remove(lockPath)
+	if err := os.WriteFile(lockPath, []byte("67890\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	breakStaleLock(lockPath, stale)
+	data, err := os.ReadFile(lockPath)
+	if err != nil || string(data) != "67890\n" {
+		t.Errorf("Expected the new lock to be kept, got %q (%v)", data, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected only the lock file to remain, got %d entries", len(entries))
+	}
+
+	breakStaleLock(lockPath, mustStat(t, lockPath))
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the stale lock to be removed, got %v", err)
+	}
+}
+
+func mustStat(t *testing.T, path string) os.FileInfo {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
@@ -0,0 +1,225 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/rice0649/fabric-lite/templates"
+	"gopkg.in/yaml.v3"
+)
+
+// TemplateFileName is the definition file inside each template directory
+const TemplateFileName = "template.yaml"
+
+// ProjectTemplate describes the directories and files a template scaffolds
+type ProjectTemplate struct {
+	Name        string                 `yaml:"name"`
+	Description string                 `yaml:"description"`
+	Options     map[string]interface{} `yaml:"options,omitempty"` // Defaults for template option answers
+	Directories []string               `yaml:"directories"`
+	Files       []TemplateFile         `yaml:"files"`
+
+	Source string `yaml:"-"` // "builtin" or the path of a user template
+}
+
+// TemplateFile is a file rendered with text/template. Path and Content may use
+// placeholders; When is a template condition (e.g. `eq .Options.language "Go"`)
+// that must hold for the file to be created.
+type TemplateFile struct {
+	Path    string `yaml:"path"`
+	Content string `yaml:"content"`
+	When    string `yaml:"when,omitempty"`
+}
+
+// TemplateData is the data available to template placeholders
+type TemplateData struct {
+	Name        string
+	Description string
+	Version     string
+	Template    string
+	Options     map[string]interface{}
+	Config      *ProjectConfig
+}
+
+// RenderedFile is a template file with placeholders resolved
+type RenderedFile struct {
+	Path    string
+	Content string
+}
+
+// RenderedTemplate is the result of rendering a template
+type RenderedTemplate struct {
+	Directories []string
+	Files       []RenderedFile
+}
+
+// UserTemplatesDir returns the directory for user-defined templates
+func UserTemplatesDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "fabric-lite", "templates")
+}
+
+// NewTemplateData builds template data from the project config and option answers.
+// Answers override the template's default options.
+func NewTemplateData(cfg *ProjectConfig, tmpl *ProjectTemplate, answers map[string]interface{}) TemplateData {
+	options := make(map[string]interface{})
+	if tmpl != nil {
+		for k, v := range tmpl.Options {
+			options[k] = v
+		}
+	}
+	for k, v := range answers {
+		options[k] = v
+	}
+
+	data := TemplateData{Options: options, Config: cfg}
+	if cfg != nil {
+		data.Name = cfg.Name
+		data.Description = cfg.Description
+		data.Version = cfg.Version
+		data.Template = cfg.Template
+	}
+	return data
+}
+
+// LoadTemplate loads a template by name. User templates take precedence
+// over the built-in ones.
+func LoadTemplate(name string) (*ProjectTemplate, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return nil, fmt.Errorf("invalid template name: %q", name)
+	}
+
+	userPath := filepath.Join(UserTemplatesDir(), name, TemplateFileName)
+	if data, err := os.ReadFile(userPath); err == nil {
+		return parseTemplate(name, userPath, data)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read template %s: %w", userPath, err)
+	}
+
+	data, err := fs.ReadFile(templates.FS, path.Join(name, TemplateFileName))
+	if err != nil {
+		return nil, fmt.Errorf("unknown template: %s", name)
+	}
+	return parseTemplate(name, "builtin", data)
+}
+
+// ListTemplates returns all available templates sorted by name
+func ListTemplates() ([]*ProjectTemplate, error) {
+	names := make(map[string]bool)
+
+	builtin, err := fs.ReadDir(templates.FS, ".")
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range builtin {
+		if e.IsDir() {
+			names[e.Name()] = true
+		}
+	}
+
+	if entries, err := os.ReadDir(UserTemplatesDir()); err == nil {
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(UserTemplatesDir(), e.Name(), TemplateFileName)); err == nil {
+				names[e.Name()] = true
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	list := make([]*ProjectTemplate, 0, len(sorted))
+	for _, name := range sorted {
+		tmpl, err := LoadTemplate(name)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, tmpl)
+	}
+	return list, nil
+}
+
+func parseTemplate(name, source string, data []byte) (*ProjectTemplate, error) {
+	var tmpl ProjectTemplate
+	if err := yaml.Unmarshal(data, &tmpl); err != nil {
+		return nil, fmt.Errorf("parse template %s: %w", name, err)
+	}
+	if tmpl.Name == "" {
+		tmpl.Name = name
+	}
+	tmpl.Source = source
+	return &tmpl, nil
+}
+
+// Render resolves placeholders in directories, file paths and contents,
+// dropping files whose When condition does not hold
+func (t *ProjectTemplate) Render(data TemplateData) (*RenderedTemplate, error) {
+	out := &RenderedTemplate{}
+
+	for _, dir := range t.Directories {
+		rendered, err := renderString(t.Name+":"+dir, dir, data)
+		if err != nil {
+			return nil, err
+		}
+		if rendered = strings.TrimSpace(rendered); rendered != "" {
+			out.Directories = append(out.Directories, rendered)
+		}
+	}
+
+	for _, f := range t.Files {
+		if f.When != "" {
+			ok, err := evalCondition(t.Name+":"+f.Path, f.When, data)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		filePath, err := renderString(t.Name+":"+f.Path, f.Path, data)
+		if err != nil {
+			return nil, err
+		}
+		content, err := renderString(t.Name+":"+f.Path, f.Content, data)
+		if err != nil {
+			return nil, err
+		}
+		out.Files = append(out.Files, RenderedFile{Path: strings.TrimSpace(filePath), Content: content})
+	}
+
+	return out, nil
+}
+
+func renderString(name, text string, data TemplateData) (string, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse template %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+func evalCondition(name, cond string, data TemplateData) (bool, error) {
+	result, err := renderString(name+" (when)", "{{if "+cond+"}}true{{end}}", data)
+	if err != nil {
+		return false, err
+	}
+	return result == "true", nil
+}
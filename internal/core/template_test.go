@@ -0,0 +1,114 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadBuiltinTemplates(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	list, err := ListTemplates()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var names []string
+	for _, tmpl := range list {
+		names = append(names, tmpl.Name)
+		if tmpl.Source != "builtin" {
+			t.Errorf("Expected builtin source for %s, got %s", tmpl.Name, tmpl.Source)
+		}
+		if len(tmpl.Files) == 0 {
+			t.Errorf("Expected template %s to define files", tmpl.Name)
+		}
+	}
+	if strings.Join(names, ",") != "api,cli,library,webapp" {
+		t.Errorf("Expected api,cli,library,webapp; got %v", names)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	tmpl, err := LoadTemplate("cli")
+	if err != nil {
+		t.Fatalf("Failed to load template: %v", err)
+	}
+
+	cfg := NewProjectConfig("mytool", "cli")
+	cfg.Description = "Does useful things"
+	rendered, err := tmpl.Render(NewTemplateData(cfg, tmpl, nil))
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+
+	files := make(map[string]string)
+	for _, f := range rendered.Files {
+		files[f.Path] = f.Content
+	}
+	if !strings.HasPrefix(files["README.md"], "# mytool\n\nDoes useful things") {
+		t.Errorf("Expected README placeholders to be rendered, got %q", files["README.md"])
+	}
+	if _, ok := files["cmd/mytool/main.go"]; !ok {
+		t.Error("Expected templated path cmd/mytool/main.go")
+	}
+	if !strings.Contains(files["go.mod"], "module github.com/user/mytool") {
+		t.Error("Expected go.mod for the default Go language")
+	}
+
+	// Answers override defaults and switch off conditional files
+	rendered, err = tmpl.Render(NewTemplateData(cfg, tmpl, map[string]interface{}{"language": "Python"}))
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+	for _, f := range rendered.Files {
+		if f.Path == "go.mod" || f.Path == "cmd/mytool/main.go" {
+			t.Errorf("Expected %s to be skipped for Python", f.Path)
+		}
+	}
+}
+
+func TestUserTemplateOverridesBuiltin(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dir := filepath.Join(home, ".config", "fabric-lite", "templates")
+	for name, def := range map[string]string{
+		"cli":     "name: cli\ndescription: My CLI\nfiles:\n  - path: main.py\n    content: print('{{.Name}}')\n",
+		"service": "name: service\ndescription: Custom service\ndirectories:\n  - src\n",
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, TemplateFileName), []byte(def), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tmpl, err := LoadTemplate("cli")
+	if err != nil {
+		t.Fatalf("Failed to load template: %v", err)
+	}
+	if tmpl.Description != "My CLI" {
+		t.Errorf("Expected user template to take precedence, got %q", tmpl.Description)
+	}
+
+	list, err := ListTemplates()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(list) != 5 {
+		t.Errorf("Expected 5 templates including the user one, got %d", len(list))
+	}
+}
+
+func TestLoadTemplateInvalidName(t *testing.T) {
+	for _, name := range []string{"", "..", "../etc", "unknown"} {
+		if _, err := LoadTemplate(name); err == nil {
+			t.Errorf("Expected error for template %q", name)
+		}
+	}
+}
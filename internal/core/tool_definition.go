@@ -0,0 +1,123 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Tool run modes
+const (
+	ToolModeInteractive = "interactive" // attach the terminal (default)
+	ToolModeCapture     = "capture"     // capture output and return it
+)
+
+// ToolsFile is the file tools are declared in
+const ToolsFile = "tools.yaml"
+
+// ToolDefinition declares a command-line AI tool. Args, Stdin and Env
+// values are text/template strings over .Prompt, .Phase, .Pattern,
+// .Context and .Config; args that render empty are dropped.
+type ToolDefinition struct {
+	Description string            `yaml:"description,omitempty"`
+	Command     string            `yaml:"command,omitempty"`
+	Args        []string          `yaml:"args,omitempty"`     // Default: the prompt as the only argument
+	Stdin       string            `yaml:"stdin,omitempty"`    // Piped to the command when output is captured
+	Env         map[string]string `yaml:"env,omitempty"`      // Added to the environment; $VARS are expanded
+	WorkDir     string            `yaml:"work_dir,omitempty"` // Relative to the project directory
+	Mode        string            `yaml:"mode,omitempty"`     // interactive or capture, for direct runs
+	Check       []string          `yaml:"check,omitempty"`    // Availability command (default: command in PATH)
+	Prompts     map[string]string `yaml:"prompts,omitempty"`  // phase -> prompt when none is given ("default" for others)
+	Patterns    map[string]string `yaml:"patterns,omitempty"` // phase -> pattern when none is given ("default" for others)
+	Config      map[string]string `yaml:"config,omitempty"`   // Free-form values for templates, e.g. {{.Config.model}}
+}
+
+// Merge returns d with the fields set in override replaced
+func (d ToolDefinition) Merge(override ToolDefinition) ToolDefinition {
+	if override.Description != "" {
+		d.Description = override.Description
+	}
+	if override.Command != "" {
+		d.Command = override.Command
+	}
+	if override.Args != nil {
+		d.Args = override.Args
+	}
+	if override.Stdin != "" {
+		d.Stdin = override.Stdin
+	}
+	if override.WorkDir != "" {
+		d.WorkDir = override.WorkDir
+	}
+	if override.Mode != "" {
+		d.Mode = override.Mode
+	}
+	if override.Check != nil {
+		d.Check = override.Check
+	}
+	d.Env = mergeStrings(d.Env, override.Env)
+	d.Prompts = mergeStrings(d.Prompts, override.Prompts)
+	d.Patterns = mergeStrings(d.Patterns, override.Patterns)
+	d.Config = mergeStrings(d.Config, override.Config)
+	return d
+}
+
+func mergeStrings(base, override map[string]string) map[string]string {
+	if len(override) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
+// Validate checks the command and mode
+func (d ToolDefinition) Validate() error {
+	if d.Command == "" {
+		return fmt.Errorf("command is required")
+	}
+	switch d.Mode {
+	case "", ToolModeInteractive, ToolModeCapture:
+	default:
+		return fmt.Errorf("invalid mode %q (expected interactive or capture)", d.Mode)
+	}
+	return nil
+}
+
+// LoadToolDefinitions reads the tools section of a tools.yaml file.
+// A missing file declares no tools.
+func LoadToolDefinitions(path string) (map[string]ToolDefinition, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Tools map[string]ToolDefinition `yaml:"tools"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return file.Tools, nil
+}
+
+// ToolDefinitionPaths lists tools.yaml files in load order: the user's
+// ~/.config/fabric-lite, then the forge project, so projects win
+func ToolDefinitionPaths() []string {
+	var paths []string
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(homeDir, ".config", "fabric-lite", ToolsFile))
+	}
+	return append(paths, filepath.Join(".forge", ToolsFile))
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadToolDefinitions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ToolsFile)
+	if defs, err := LoadToolDefinitions(path); err != nil || defs != nil {
+		t.Errorf("Expected no tools for a missing file, got %v, %v", defs, err)
+	}
+
+	data := `
+tools:
+  aider:
+    name: Aider
+    command: aider
+    args: ["--yes", "--message", "{{.Prompt}}"]
+    mode: capture
+    env:
+      AIDER_MODEL: "{{.Config.model}}"
+    config:
+      model: gpt-4o
+    features:
+      - documentation-only fields are ignored
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defs, err := LoadToolDefinitions(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	aider := defs["aider"]
+	if aider.Command != "aider" || len(aider.Args) != 3 || aider.Mode != ToolModeCapture || aider.Config["model"] != "gpt-4o" {
+		t.Errorf("Unexpected definition: %+v", aider)
+	}
+	if err := aider.Validate(); err != nil {
+		t.Errorf("Expected a valid definition, got %v", err)
+	}
+}
+
+func TestToolDefinitionMerge(t *testing.T) {
+	base := ToolDefinition{
+		Command: "gemini",
+		Args:    []string{"{{.Prompt}}"},
+		Prompts: map[string]string{"discovery": "research", "testing": "test"},
+	}
+	merged := base.Merge(ToolDefinition{
+		Command: "gemini-beta",
+		Prompts: map[string]string{"testing": "test harder"},
+	})
+
+	if merged.Command != "gemini-beta" || len(merged.Args) != 1 {
+		t.Errorf("Expected the command replaced and args kept, got %+v", merged)
+	}
+	if merged.Prompts["discovery"] != "research" || merged.Prompts["testing"] != "test harder" {
+		t.Errorf("Expected prompts merged per phase, got %v", merged.Prompts)
+	}
+	if base.Prompts["testing"] != "test" {
+		t.Error("Expected the base definition to be unchanged")
+	}
+	if err := (ToolDefinition{}).Validate(); err == nil {
+		t.Error("Expected an error without a command")
+	}
+}
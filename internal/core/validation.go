@@ -0,0 +1,238 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ValidationPattern is the pattern used for AI phase validation
+const ValidationPattern = "validation/validate_phase_output"
+
+// PhaseValidator validates phase outputs using AI
+type PhaseValidator struct {
+	ExecuteFunc      func(pattern, input string) (string, error)
+	MaxArtifactBytes int // Bounds artifact content sent to the validator (0 = DefaultArtifactContextLimit)
+}
+
+// CriterionResult is the verdict on a single checkpoint criterion
+type CriterionResult struct {
+	Criterion string `json:"criterion" yaml:"criterion"`
+	Passed    bool   `json:"passed" yaml:"passed"`
+	Evidence  string `json:"evidence" yaml:"evidence,omitempty"`
+}
+
+// AIValidationResult holds the result of AI validation
+type AIValidationResult struct {
+	Valid    bool              `json:"valid"`
+	Feedback string            `json:"feedback"`
+	Criteria []CriterionResult `json:"criteria,omitempty"`
+}
+
+// PhaseVerdict is the combined result of checkpoint checks and AI validation
+type PhaseVerdict struct {
+	Phase      string
+	Passed     bool
+	Checkpoint ValidationResult
+	AI         *AIValidationResult // nil when AI validation did not run
+	Criteria   []CriterionResult   // Per-criterion results from the AI validator
+	Feedback   string
+	Warning    string // Set when AI validation failed and the checkpoint verdict was used
+}
+
+// ValidatePhase runs the validation pipeline for a phase. Deterministic
+// checkpoint checks run first; when they pass and a validator is configured,
+// the phase artifacts (size-limited) are sent to the validation pattern,
+// which judges each checkpoint criterion with evidence. When the validator
+// call itself fails, the checkpoint verdict stands and Warning says why.
+func ValidatePhase(phase string, validator *PhaseValidator) (*PhaseVerdict, error) {
+	phaseInfo := GetPhase(phase)
+	if phaseInfo == nil {
+		return nil, fmt.Errorf("unknown phase: %s", phase)
+	}
+
+	verdict := &PhaseVerdict{
+		Phase:      phase,
+		Checkpoint: ValidateCheckpoint(phase),
+	}
+
+	// Missing files fail fast without spending an AI call
+	if !verdict.Checkpoint.Passed {
+		verdict.Feedback = checkpointFeedback(verdict.Checkpoint)
+		return verdict, nil
+	}
+
+	if validator == nil || validator.ExecuteFunc == nil {
+		verdict.Passed = true
+		verdict.Feedback = "Checkpoint checks passed (AI validation not configured)"
+		return verdict, nil
+	}
+
+	limit := validator.MaxArtifactBytes
+	if limit <= 0 {
+		limit = DefaultArtifactContextLimit
+	}
+	artifacts, err := ReadArtifacts(phase, limit)
+	if err != nil {
+		return nil, fmt.Errorf("read artifacts: %w", err)
+	}
+
+	output, err := validator.ExecuteFunc(ValidationPattern, validationInput(phaseInfo, verdict.Checkpoint, artifacts))
+	if err != nil {
+		verdict.Passed = true
+		verdict.Warning = fmt.Sprintf("AI validation failed, using checkpoint checks only: %v", err)
+		verdict.Feedback = "Checkpoint checks passed (AI validation unavailable)"
+		return verdict, nil
+	}
+
+	result, err := parseValidationResult(output)
+	if err != nil {
+		return nil, fmt.Errorf("parse validation result: %w", err)
+	}
+
+	// Any failed or unjudged criterion fails the phase, whatever the overall flag says
+	result.Criteria = withMissingCriteria(phaseInfo.Checkpoint.Criteria, result.Criteria)
+	verdict.AI = result
+	verdict.Criteria = result.Criteria
+	verdict.Passed = result.Valid
+	for _, c := range result.Criteria {
+		if !c.Passed {
+			verdict.Passed = false
+		}
+	}
+	verdict.Feedback = aiFeedback(result)
+
+	return verdict, nil
+}
+
+// validationInput builds the validation pattern input for a phase
+func validationInput(phase *Phase, checkpoint ValidationResult, artifacts []Artifact) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Phase: %s\n", phase.Name)
+	fmt.Fprintf(&sb, "Description: %s\n", phase.Description)
+	fmt.Fprintf(&sb, "Expected Artifacts: %s\n\n", strings.Join(phase.Artifacts, ", "))
+
+	sb.WriteString("Checkpoint Criteria:\n")
+	for _, c := range phase.Checkpoint.Criteria {
+		fmt.Fprintf(&sb, "- %s\n", c)
+	}
+
+	sb.WriteString("\nAutomated Checks:\n")
+	for _, check := range checkpoint.Checks {
+		status := "passed"
+		if !check.Passed {
+			status = "failed"
+		}
+		fmt.Fprintf(&sb, "- %s: %s\n", check.Name, status)
+	}
+
+	sb.WriteString("\nArtifacts:\n\n")
+	if len(artifacts) == 0 {
+		sb.WriteString("(no artifacts found in " + ArtifactDir(phase.Name) + ")\n\n")
+	}
+	for _, a := range artifacts {
+		sb.WriteString(formatArtifact(a))
+	}
+
+	sb.WriteString("Judge each checkpoint criterion against the artifact contents above.")
+	return sb.String()
+}
+
+// checkpointFeedback summarizes failed checkpoint checks
+func checkpointFeedback(result ValidationResult) string {
+	var sb strings.Builder
+	sb.WriteString("Checkpoint checks failed:")
+	for _, check := range result.Checks {
+		if check.Passed {
+			continue
+		}
+		sb.WriteString("\n- " + check.Name)
+		if check.Message != "" {
+			sb.WriteString(": " + check.Message)
+		}
+	}
+	return sb.String()
+}
+
+// withMissingCriteria appends a failed result for each checkpoint criterion
+// the validator did not judge
+func withMissingCriteria(criteria []string, results []CriterionResult) []CriterionResult {
+	judged := make(map[string]bool, len(results))
+	for _, r := range results {
+		judged[strings.ToLower(strings.TrimSpace(r.Criterion))] = true
+	}
+	for _, c := range criteria {
+		if !judged[strings.ToLower(c)] {
+			results = append(results, CriterionResult{
+				Criterion: c,
+				Passed:    false,
+				Evidence:  "not judged by the validator",
+			})
+		}
+	}
+	return results
+}
+
+// aiFeedback combines the validator feedback with the evidence of failed criteria
+func aiFeedback(result *AIValidationResult) string {
+	var failed []string
+	for _, c := range result.Criteria {
+		if !c.Passed {
+			failed = append(failed, fmt.Sprintf("- %s: %s", c.Criterion, c.Evidence))
+		}
+	}
+	if len(failed) == 0 {
+		return result.Feedback
+	}
+	return result.Feedback + "\n\nFailed criteria:\n" + strings.Join(failed, "\n")
+}
+
+// parseValidationResult extracts AIValidationResult from AI output
+func parseValidationResult(output string) (*AIValidationResult, error) {
+	// Try to find JSON in the output (may be wrapped in markdown)
+	jsonStr := extractJSONFromOutput(output)
+	if jsonStr == "" {
+		// If no JSON found, treat as invalid with the output as feedback
+		return &AIValidationResult{
+			Valid:    false,
+			Feedback: "Could not parse validation response: " + output,
+		}, nil
+	}
+
+	var result AIValidationResult
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		return nil, fmt.Errorf("invalid JSON in validation response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// extractJSONFromOutput extracts JSON from potentially markdown-wrapped output
+func extractJSONFromOutput(s string) string {
+	// Try to find JSON in code blocks first
+	codeBlockRegex := regexp.MustCompile("```(?:json)?\\s*([\\s\\S]*?)```")
+	matches := codeBlockRegex.FindStringSubmatch(s)
+	if len(matches) > 1 {
+		return strings.TrimSpace(matches[1])
+	}
+
+	// Try to find raw JSON object
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") {
+		depth := 0
+		for i, c := range s {
+			if c == '{' {
+				depth++
+			} else if c == '}' {
+				depth--
+				if depth == 0 {
+					return s[:i+1]
+				}
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,190 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// passingVerdict returns a validator response that passes every checkpoint
+// criterion of a phase
+func passingVerdict(phase string) string {
+	result := AIValidationResult{Valid: true, Feedback: "looks good"}
+	for _, c := range GetPhase(phase).Checkpoint.Criteria {
+		result.Criteria = append(result.Criteria, CriterionResult{Criterion: c, Passed: true, Evidence: "covered"})
+	}
+	data, _ := json.Marshal(result)
+	return string(data)
+}
+
+func TestValidatePhaseCheckpointFailureSkipsAI(t *testing.T) {
+	chdirTemp(t)
+
+	called := false
+	validator := &PhaseValidator{
+		ExecuteFunc: func(pattern, input string) (string, error) {
+			called = true
+			return `{"valid": true, "feedback": "ok"}`, nil
+		},
+	}
+
+	verdict, err := ValidatePhase("discovery", validator)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if verdict.Passed {
+		t.Error("Expected verdict to fail when checkpoint files are missing")
+	}
+	if called {
+		t.Error("Expected AI validator not to run when checkpoint checks fail")
+	}
+	if !strings.Contains(verdict.Feedback, "Requirements document") {
+		t.Errorf("Expected feedback to name the failed check, got '%s'", verdict.Feedback)
+	}
+}
+
+func TestValidatePhaseSendsArtifactContents(t *testing.T) {
+	seedDiscoveryArtifacts(t)
+	if _, err := SaveArtifact("discovery", "requirements.md", "Users can export reports as CSV"); err != nil {
+		t.Fatalf("Failed to save artifact: %v", err)
+	}
+
+	var gotPattern, gotInput string
+	validator := &PhaseValidator{
+		ExecuteFunc: func(pattern, input string) (string, error) {
+			gotPattern, gotInput = pattern, input
+			return passingVerdict("discovery"), nil
+		},
+	}
+
+	verdict, err := ValidatePhase("discovery", validator)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !verdict.Passed {
+		t.Errorf("Expected verdict to pass, got feedback '%s'", verdict.Feedback)
+	}
+	if gotPattern != ValidationPattern {
+		t.Errorf("Expected pattern %s, got %s", ValidationPattern, gotPattern)
+	}
+	if !strings.Contains(gotInput, "Users can export reports as CSV") {
+		t.Error("Expected validation input to include artifact contents")
+	}
+	if len(verdict.Criteria) != 4 || verdict.Criteria[0].Evidence == "" {
+		t.Errorf("Expected every criterion with evidence, got %+v", verdict.Criteria)
+	}
+}
+
+func TestValidatePhaseFailedCriterion(t *testing.T) {
+	seedDiscoveryArtifacts(t)
+
+	validator := &PhaseValidator{
+		ExecuteFunc: func(pattern, input string) (string, error) {
+			return `{"valid": true, "feedback": "mostly fine", "criteria": [
+				{"criterion": "Technical constraints identified", "passed": false, "evidence": "no constraints section"}
+			]}`, nil
+		},
+	}
+
+	verdict, err := ValidatePhase("discovery", validator)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if verdict.Passed {
+		t.Error("Expected a failed criterion to fail the verdict")
+	}
+	if !strings.Contains(verdict.Feedback, "no constraints section") {
+		t.Errorf("Expected feedback to include criterion evidence, got '%s'", verdict.Feedback)
+	}
+}
+
+func TestValidatePhaseLimitsArtifactSize(t *testing.T) {
+	seedDiscoveryArtifacts(t)
+	if _, err := SaveArtifact("discovery", "research_notes.md", strings.Repeat("x", 500)); err != nil {
+		t.Fatalf("Failed to save artifact: %v", err)
+	}
+
+	var gotInput string
+	validator := &PhaseValidator{
+		MaxArtifactBytes: 100,
+		ExecuteFunc: func(pattern, input string) (string, error) {
+			gotInput = input
+			return `{"valid": true, "feedback": "ok"}`, nil
+		},
+	}
+
+	if _, err := ValidatePhase("discovery", validator); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Contains(gotInput, strings.Repeat("x", 101)) {
+		t.Error("Expected artifact contents to be truncated to the limit")
+	}
+	if !strings.Contains(gotInput, "(truncated)") {
+		t.Error("Expected truncated artifacts to be marked")
+	}
+}
+
+func TestValidatePhaseWithoutValidator(t *testing.T) {
+	seedDiscoveryArtifacts(t)
+
+	verdict, err := ValidatePhase("discovery", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !verdict.Passed || verdict.AI != nil {
+		t.Errorf("Expected checkpoint-only verdict to pass without AI result, got %+v", verdict)
+	}
+}
+
+func TestValidatePhaseMissingCriterionFails(t *testing.T) {
+	seedDiscoveryArtifacts(t)
+
+	validator := &PhaseValidator{
+		ExecuteFunc: func(pattern, input string) (string, error) {
+			return `{"valid": true, "feedback": "ok", "criteria": [
+				{"criterion": "Requirements document exists", "passed": true, "evidence": "requirements.md"}
+			]}`, nil
+		},
+	}
+
+	verdict, err := ValidatePhase("discovery", validator)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if verdict.Passed {
+		t.Error("Expected unjudged criteria to fail the verdict")
+	}
+	if len(verdict.Criteria) != 4 {
+		t.Fatalf("Expected all 4 criteria in the verdict, got %+v", verdict.Criteria)
+	}
+	for _, c := range verdict.Criteria[1:] {
+		if c.Passed {
+			t.Errorf("Expected missing criterion %q to fail", c.Criterion)
+		}
+	}
+	if !strings.Contains(verdict.Feedback, "Research notes compiled") {
+		t.Errorf("Expected feedback to name the missing criteria, got '%s'", verdict.Feedback)
+	}
+}
+
+func TestValidatePhaseFallsBackWhenAIFails(t *testing.T) {
+	seedDiscoveryArtifacts(t)
+
+	validator := &PhaseValidator{
+		ExecuteFunc: func(pattern, input string) (string, error) {
+			return "", errors.New("connection refused")
+		},
+	}
+
+	verdict, err := ValidatePhase("discovery", validator)
+	if err != nil {
+		t.Fatalf("Expected the checkpoint verdict instead of an error, got %v", err)
+	}
+	if !verdict.Passed || verdict.AI != nil {
+		t.Errorf("Expected passing checkpoint-only verdict, got %+v", verdict)
+	}
+	if !strings.Contains(verdict.Warning, "connection refused") {
+		t.Errorf("Expected warning to carry the provider error, got '%s'", verdict.Warning)
+	}
+}
@@ -178,6 +178,7 @@ func TestListPatterns(t *testing.T) {
 	// Create test patterns
 	createTestPattern(t, "patterns/test1", "Test System 1", "Test User 1")
 	createTestPattern(t, "patterns/test2", "Test System 2", "") // No user prompt
+	createTestPattern(t, "patterns/group/nested", "Nested System", "")
 
 	// Create a file (should be ignored)
 	err = os.WriteFile("patterns/not-a-pattern.txt", []byte("not a pattern"), 0644)
@@ -193,8 +194,8 @@ func TestListPatterns(t *testing.T) {
 		t.Errorf("Expected no error listing patterns, got %v", err)
 	}
 
-	if len(patterns) != 2 {
-		t.Errorf("Expected 2 patterns, got %d", len(patterns))
+	if len(patterns) != 3 {
+		t.Errorf("Expected 3 patterns, got %d", len(patterns))
 	}
 
 	// Check pattern names
@@ -209,6 +210,9 @@ func TestListPatterns(t *testing.T) {
 	if !patternNames["test2"] {
 		t.Error("Expected to find test2 pattern")
 	}
+	if !patternNames["group/nested"] {
+		t.Error("Expected to find the nested group/nested pattern")
+	}
 }
 
 func TestLoadPattern(t *testing.T) {
@@ -351,6 +355,60 @@ func TestBuildRequest(t *testing.T) {
 	}
 }
 
+func TestBuildRequestVariables(t *testing.T) {
+	executor := NewPatternExecutor()
+	executor.SetVariables(map[string]string{"language": "Go"})
+
+	pattern := &PatternInfo{
+		Name:   "test",
+		System: "Review {{ language }} code for {{audience}}.",
+		User:   "Code:\n{{input}}\nEnd.",
+	}
+	if vars := patternVariables(pattern.System, pattern.User); len(vars) != 2 || vars[0] != "audience" || vars[1] != "language" {
+		t.Errorf("Expected sorted variables without input, got %v", vars)
+	}
+
+	request := executor.buildRequest(pattern, "x := {{language}}", "", false)
+	if request.System != "Review Go code for {{audience}}." {
+		t.Errorf("Expected set variables replaced and unset ones kept, got '%s'", request.System)
+	}
+	if request.Prompt != "Code:\nx := {{language}}\nEnd." {
+		t.Errorf("Expected the input in place of {{input}} and left unsubstituted, got '%s'", request.Prompt)
+	}
+}
+
+func TestPatternSampling(t *testing.T) {
+	tmpDir := t.TempDir()
+	patternDir := filepath.Join(tmpDir, "focused")
+	createTestPattern(t, patternDir, "# IDENTITY and PURPOSE\nYou are precise.", "")
+	settings := "temperature: 0.1\ntop_k: 20\nstop: [\"END\"]\n"
+	if err := os.WriteFile(filepath.Join(patternDir, PatternSettingsFile), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	executor := NewPatternExecutor()
+	executor.SetPatternsDir(tmpDir)
+	pattern, err := executor.loadPattern("focused")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if pattern.Sampling.Temperature == nil || *pattern.Sampling.Temperature != 0.1 || *pattern.Sampling.TopK != 20 || pattern.Sampling.Stop[0] != "END" {
+		t.Errorf("Expected the pattern's sampling defaults, got %+v", pattern.Sampling)
+	}
+
+	temperature := 0.9
+	executor.SetSampling(providers.Sampling{Temperature: &temperature})
+	request := executor.buildRequest(pattern, "input", "", false)
+	if *request.Temperature != 0.9 || request.TopK == nil || *request.TopK != 20 {
+		t.Errorf("Expected flags to override only what they set, got %+v", request.Sampling)
+	}
+
+	os.WriteFile(filepath.Join(patternDir, PatternSettingsFile), []byte("top_p: 3\n"), 0644)
+	if _, err := executor.loadPattern("focused"); err == nil {
+		t.Error("Expected an error for an out-of-range top_p")
+	}
+}
+
 func TestExecute(t *testing.T) {
 	executor := NewPatternExecutor()
 	ctx := context.Background()
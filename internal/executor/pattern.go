@@ -4,10 +4,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/rice0649/fabric-lite/internal/providers"
+	"gopkg.in/yaml.v3"
 )
 
 type PatternInfo struct {
@@ -15,11 +19,29 @@ type PatternInfo struct {
 	Description string
 	System      string
 	User        string
+	Variables   []string // {{name}} placeholders other than {{input}}, sorted
+	Sampling    providers.Sampling
 }
 
+// PatternSettingsFile holds a pattern's optional defaults next to system.md
+const PatternSettingsFile = "pattern.yaml"
+
+// patternSettings is the content of pattern.yaml, e.g. "temperature: 0.2"
+type patternSettings struct {
+	providers.Sampling `yaml:",inline"`
+}
+
+// InputVariable is the placeholder replaced by the pattern input
+const InputVariable = "input"
+
+// variablePattern matches {{name}} placeholders in pattern prompts
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
 type PatternExecutor struct {
 	providers   map[string]providers.Provider
 	patternsDir string
+	variables   map[string]string
+	sampling    providers.Sampling
 }
 
 func NewPatternExecutor() *PatternExecutor {
@@ -33,6 +55,22 @@ func (e *PatternExecutor) GetPatternsDir() string {
 	return getPatternsDir()
 }
 
+// SetPatternsDir overrides the directory patterns are loaded from
+func (e *PatternExecutor) SetPatternsDir(dir string) {
+	e.patternsDir = dir
+}
+
+// SetVariables sets the values substituted for {{name}} placeholders;
+// placeholders without a value are left as they are
+func (e *PatternExecutor) SetVariables(vars map[string]string) {
+	e.variables = vars
+}
+
+// SetSampling sets sampling parameters that override the pattern's defaults
+func (e *PatternExecutor) SetSampling(sampling providers.Sampling) {
+	e.sampling = sampling
+}
+
 func getPatternsDir() string {
 	// Check local patterns first
 	if _, err := os.Stat("patterns"); err == nil {
@@ -75,29 +113,50 @@ func (e *PatternExecutor) LoadProviderDirect(name string, provider providers.Pro
 	e.providers[name] = provider
 }
 
+// ListPatterns loads every pattern in the patterns directory, including
+// patterns grouped in subdirectories such as deployment/create_changelog.
+// A directory with a system.md is a pattern; other directories are searched
+// for patterns. Invalid patterns are skipped.
 func (e *PatternExecutor) ListPatterns() ([]PatternInfo, error) {
-	patternsDir := e.patternsDir
-	entries, err := os.ReadDir(patternsDir)
-	if err != nil {
+	if _, err := os.ReadDir(e.patternsDir); err != nil {
 		return nil, fmt.Errorf("failed to read patterns directory: %w", err)
 	}
 
 	var patterns []PatternInfo
+	e.listPatterns("", &patterns)
+	return patterns, nil
+}
+
+// listPatterns adds the patterns below the group directory dir, a slash
+// separated path relative to the patterns directory
+func (e *PatternExecutor) listPatterns(dir string, patterns *[]PatternInfo) {
+	entries, err := os.ReadDir(filepath.Join(e.patternsDir, filepath.FromSlash(dir)))
+	if err != nil {
+		return
+	}
+
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
 		}
 
-		patternName := entry.Name()
+		patternName := path.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(e.patternsDir, filepath.FromSlash(patternName), "system.md")); err != nil {
+			e.listPatterns(patternName, patterns)
+			continue
+		}
 		pattern, err := e.loadPattern(patternName)
 		if err != nil {
 			continue // Skip invalid patterns
 		}
 
-		patterns = append(patterns, *pattern)
+		*patterns = append(*patterns, *pattern)
 	}
+}
 
-	return patterns, nil
+// LoadPattern reads and validates a pattern from the patterns directory
+func (e *PatternExecutor) LoadPattern(name string) (*PatternInfo, error) {
+	return e.loadPattern(name)
 }
 
 func (e *PatternExecutor) loadPattern(name string) (*PatternInfo, error) {
@@ -122,14 +181,67 @@ func (e *PatternExecutor) loadPattern(name string) (*PatternInfo, error) {
 		userContent = string(userData)
 	}
 
+	// Load settings (optional)
+	var settings patternSettings
+	settingsData, err := os.ReadFile(filepath.Join(patternDir, PatternSettingsFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read pattern settings: %w", err)
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(settingsData, &settings); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", PatternSettingsFile, err)
+		}
+		if err := settings.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", PatternSettingsFile, err)
+		}
+	}
+
 	return &PatternInfo{
 		Name:        name,
 		Description: extractDescription(string(systemContent)),
 		System:      string(systemContent),
 		User:        userContent,
+		Variables:   patternVariables(string(systemContent), userContent),
+		Sampling:    settings.Sampling,
 	}, nil
 }
 
+// patternVariables returns the placeholder names used in the prompts
+func patternVariables(prompts ...string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, prompt := range prompts {
+		for _, m := range variablePattern.FindAllStringSubmatch(prompt, -1) {
+			if name := m[1]; name != InputVariable && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// hasVariable reports whether prompt contains the {{name}} placeholder
+func hasVariable(prompt, name string) bool {
+	for _, m := range variablePattern.FindAllStringSubmatch(prompt, -1) {
+		if m[1] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// applyVariables replaces the placeholders that have a value
+func applyVariables(prompt string, vars map[string]string) string {
+	return variablePattern.ReplaceAllStringFunc(prompt, func(m string) string {
+		if value, ok := vars[variablePattern.FindStringSubmatch(m)[1]]; ok {
+			return value
+		}
+		return m
+	})
+}
+
 func extractDescription(content string) string {
 	lines := strings.Split(content, "\n")
 	for _, line := range lines {
@@ -153,10 +265,20 @@ func extractDescription(content string) string {
 
 // buildRequest creates a CompletionRequest from pattern and input
 func (e *PatternExecutor) buildRequest(pattern *PatternInfo, input, model string, stream bool) providers.CompletionRequest {
-	// Build full prompt
+	vars := map[string]string{InputVariable: input}
+	for name, value := range e.variables {
+		if name != InputVariable {
+			vars[name] = value
+		}
+	}
+
+	// Build full prompt; a user prompt with {{input}} places the input itself
 	fullPrompt := pattern.User
 	if fullPrompt != "" {
-		fullPrompt = pattern.User + "\n\nInput:\n" + input
+		fullPrompt = applyVariables(pattern.User, vars)
+		if !hasVariable(pattern.User, InputVariable) {
+			fullPrompt += "\n\nInput:\n" + input
+		}
 	} else {
 		fullPrompt = input
 	}
@@ -167,11 +289,12 @@ func (e *PatternExecutor) buildRequest(pattern *PatternInfo, input, model string
 	}
 
 	return providers.CompletionRequest{
-		System:    pattern.System,
+		System:    applyVariables(pattern.System, vars),
 		Prompt:    fullPrompt,
 		Model:     model,
 		Stream:    stream,
 		MaxTokens: 4096,
+		Sampling:  pattern.Sampling.Merge(e.sampling),
 	}
 }
 
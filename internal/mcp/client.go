@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// Client is a minimal JSON-RPC client for an MCP server. Calls are
+// serialized, so it suits tests and in-process use rather than
+// concurrent clients.
+type Client struct {
+	mu     sync.Mutex
+	w      io.Writer
+	dec    *json.Decoder
+	nextID int
+	close  func() error
+}
+
+// NewClient talks to a server reading from w and writing to r
+func NewClient(r io.Reader, w io.Writer) *Client {
+	return &Client{w: w, dec: json.NewDecoder(r)}
+}
+
+// Connect serves s in process and returns a client connected to it. Close
+// the client to stop the server.
+func (s *Server) Connect(ctx context.Context) *Client {
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		err := s.Serve(ctx, serverR, serverW)
+		serverW.Close()
+		done <- err
+	}()
+
+	c := NewClient(clientR, clientW)
+	c.close = func() error {
+		clientW.Close()
+		// Drain responses nobody waits for so the server can finish
+		go io.Copy(io.Discard, clientR)
+		return <-done
+	}
+	return c
+}
+
+// Call sends a request and decodes its result into result, which may be nil
+func (c *Client) Call(method string, params, result interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+	if err := c.send(id, method, params); err != nil {
+		return err
+	}
+
+	for {
+		var msg message
+		if err := c.dec.Decode(&msg); err != nil {
+			return fmt.Errorf("read response: %w", err)
+		}
+		if string(msg.ID) != string(id) {
+			continue // a notification or a response to an abandoned call
+		}
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(msg.Result, result)
+	}
+}
+
+// Notify sends a notification, which gets no response
+func (c *Client) Notify(method string, params interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.send(nil, method, params)
+}
+
+// Initialize performs the MCP handshake
+func (c *Client) Initialize() error {
+	params := map[string]interface{}{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]string{"name": "fabric-lite-client", "version": "0"},
+	}
+	if err := c.Call("initialize", params, nil); err != nil {
+		return err
+	}
+	return c.Notify("notifications/initialized", nil)
+}
+
+// Close stops a server started with Connect
+func (c *Client) Close() error {
+	if c.close == nil {
+		return nil
+	}
+	return c.close()
+}
+
+func (c *Client) send(id json.RawMessage, method string, params interface{}) error {
+	msg := message{JSONRPC: "2.0", ID: id, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		msg.Params = data
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = c.w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,328 @@
+// Package mcp implements a Model Context Protocol server over stdio:
+// newline-delimited JSON-RPC 2.0 messages publishing tools and resources
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// ProtocolVersion is the MCP revision this server implements
+const ProtocolVersion = "2024-11-05"
+
+// maxMessageSize bounds a single JSON-RPC message
+const maxMessageSize = 16 << 20
+
+// JSON-RPC and MCP error codes
+const (
+	CodeParseError       = -32700
+	CodeInvalidRequest   = -32600
+	CodeMethodNotFound   = -32601
+	CodeInvalidParams    = -32602
+	CodeInternalError    = -32603
+	CodeResourceNotFound = -32002
+)
+
+// ToolHandler runs a tool with its JSON arguments. The returned text is the
+// tool result; on error it is reported together with the error message.
+type ToolHandler func(ctx context.Context, args json.RawMessage) (string, error)
+
+// Tool is a callable tool
+type Tool struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	InputSchema Schema      `json:"inputSchema"`
+	Handler     ToolHandler `json:"-"`
+}
+
+// Schema is the JSON schema of a tool's arguments
+type Schema struct {
+	Type       string              `json:"type"`
+	Properties map[string]Property `json:"properties,omitempty"`
+	Required   []string            `json:"required,omitempty"`
+}
+
+// Property describes one tool argument
+type Property struct {
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+}
+
+// Resource is readable context identified by a URI
+type Resource struct {
+	URI         string                 `json:"uri"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	MimeType    string                 `json:"mimeType,omitempty"`
+	Read        func() (string, error) `json:"-"`
+}
+
+// Error is a JSON-RPC error
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// isNotification reports whether the message expects no response
+func (m *message) isNotification() bool {
+	return len(m.ID) == 0
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Server publishes tools and resources to an MCP client
+type Server struct {
+	name      string
+	version   string
+	tools     []Tool
+	resources []Resource
+
+	mu       sync.Mutex
+	inFlight map[string]context.CancelFunc
+}
+
+// NewServer creates a server identifying itself as name and version
+func NewServer(name, version string) *Server {
+	return &Server{name: name, version: version, inFlight: make(map[string]context.CancelFunc)}
+}
+
+// AddTool publishes t, replacing an earlier tool with the same name
+func (s *Server) AddTool(t Tool) {
+	for i := range s.tools {
+		if s.tools[i].Name == t.Name {
+			s.tools[i] = t
+			return
+		}
+	}
+	s.tools = append(s.tools, t)
+}
+
+// AddResource publishes r
+func (s *Server) AddResource(r Resource) {
+	s.resources = append(s.resources, r)
+}
+
+// Serve reads requests from in and writes responses to out until in is
+// closed or ctx is cancelled. Requests run concurrently; responses may
+// arrive out of order, matched to requests by id.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	var (
+		writeMu sync.Mutex
+		wg      sync.WaitGroup
+	)
+	enc := json.NewEncoder(out)
+	write := func(resp *response) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		enc.Encode(resp)
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var msg message
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			write(&response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &Error{Code: CodeParseError, Message: "parse error"}})
+			continue
+		}
+		if msg.isNotification() {
+			s.notify(&msg)
+			continue
+		}
+
+		reqCtx, cancel := context.WithCancel(ctx)
+		s.track(msg.ID, cancel)
+		wg.Add(1)
+		go func(msg message) {
+			defer wg.Done()
+			defer s.untrack(msg.ID)
+			write(s.handle(reqCtx, &msg))
+		}(msg)
+	}
+
+	wg.Wait()
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// notify handles a notification; the client expects no response
+func (s *Server) notify(msg *message) {
+	if msg.Method != "notifications/cancelled" {
+		return
+	}
+	var params struct {
+		RequestID json.RawMessage `json:"requestId"`
+	}
+	if json.Unmarshal(msg.Params, &params) == nil {
+		s.mu.Lock()
+		cancel := s.inFlight[string(params.RequestID)]
+		s.mu.Unlock()
+		if cancel != nil {
+			cancel()
+		}
+	}
+}
+
+func (s *Server) track(id json.RawMessage, cancel context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight[string(id)] = cancel
+}
+
+func (s *Server) untrack(id json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cancel, ok := s.inFlight[string(id)]; ok {
+		cancel()
+		delete(s.inFlight, string(id))
+	}
+}
+
+// handle dispatches a request to its method
+func (s *Server) handle(ctx context.Context, msg *message) *response {
+	resp := &response{JSONRPC: "2.0", ID: msg.ID}
+	if msg.JSONRPC != "2.0" || msg.Method == "" {
+		resp.Error = &Error{Code: CodeInvalidRequest, Message: "invalid request"}
+		return resp
+	}
+
+	var err *Error
+	switch msg.Method {
+	case "initialize":
+		resp.Result = s.initialize()
+	case "ping":
+		resp.Result = struct{}{}
+	case "tools/list":
+		resp.Result = map[string]interface{}{"tools": s.toolList()}
+	case "tools/call":
+		resp.Result, err = s.callTool(ctx, msg.Params)
+	case "resources/list":
+		resp.Result = map[string]interface{}{"resources": s.resourceList()}
+	case "resources/read":
+		resp.Result, err = s.readResource(msg.Params)
+	default:
+		err = &Error{Code: CodeMethodNotFound, Message: "method not found: " + msg.Method}
+	}
+	if err != nil {
+		resp.Result, resp.Error = nil, err
+	}
+	return resp
+}
+
+func (s *Server) initialize() interface{} {
+	return map[string]interface{}{
+		"protocolVersion": ProtocolVersion,
+		"capabilities": map[string]interface{}{
+			"tools":     map[string]bool{"listChanged": false},
+			"resources": map[string]bool{"subscribe": false, "listChanged": false},
+		},
+		"serverInfo": map[string]string{"name": s.name, "version": s.version},
+	}
+}
+
+func (s *Server) toolList() []Tool {
+	tools := make([]Tool, len(s.tools))
+	copy(tools, s.tools)
+	return tools
+}
+
+func (s *Server) resourceList() []Resource {
+	resources := make([]Resource, len(s.resources))
+	copy(resources, s.resources)
+	return resources
+}
+
+// callTool runs a tool. Tool failures are results with isError set, so the
+// model sees them; only unknown tools and bad params are protocol errors.
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (interface{}, *Error) {
+	var call struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil || call.Name == "" {
+		return nil, &Error{Code: CodeInvalidParams, Message: "tools/call requires a tool name"}
+	}
+	var tool *Tool
+	for i := range s.tools {
+		if s.tools[i].Name == call.Name {
+			tool = &s.tools[i]
+			break
+		}
+	}
+	if tool == nil {
+		return nil, &Error{Code: CodeInvalidParams, Message: "unknown tool: " + call.Name}
+	}
+	if len(call.Arguments) == 0 || string(call.Arguments) == "null" {
+		call.Arguments = json.RawMessage("{}")
+	}
+
+	text, err := tool.Handler(ctx, call.Arguments)
+	result := map[string]interface{}{}
+	if err != nil {
+		text = strings.TrimSpace(strings.TrimSpace(text) + "\n\nError: " + err.Error())
+		result["isError"] = true
+	}
+	result["content"] = []textContent{{Type: "text", Text: text}}
+	return result, nil
+}
+
+func (s *Server) readResource(params json.RawMessage) (interface{}, *Error) {
+	var read struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(params, &read); err != nil || read.URI == "" {
+		return nil, &Error{Code: CodeInvalidParams, Message: "resources/read requires a uri"}
+	}
+	for _, r := range s.resources {
+		if r.URI != read.URI {
+			continue
+		}
+		text, err := r.Read()
+		if err != nil {
+			return nil, &Error{Code: CodeInternalError, Message: err.Error()}
+		}
+		return map[string]interface{}{
+			"contents": []map[string]string{{"uri": r.URI, "mimeType": r.MimeType, "text": text}},
+		}, nil
+	}
+	return nil, &Error{Code: CodeResourceNotFound, Message: "resource not found: " + read.URI}
+}
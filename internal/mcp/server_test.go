@@ -0,0 +1,203 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type toolResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError"`
+}
+
+func newTestServer() *Server {
+	s := NewServer("test", "1.0")
+	s.AddTool(Tool{
+		Name:        "echo",
+		Description: "Echo the text",
+		InputSchema: Schema{Type: "object", Properties: map[string]Property{"text": {Type: "string"}}, Required: []string{"text"}},
+		Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+			var in struct {
+				Text string `json:"text"`
+			}
+			if err := json.Unmarshal(args, &in); err != nil {
+				return "", err
+			}
+			return in.Text, nil
+		},
+	})
+	s.AddTool(Tool{
+		Name:        "fail",
+		InputSchema: Schema{Type: "object"},
+		Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+			return "partial output", errors.New("boom")
+		},
+	})
+	s.AddTool(Tool{
+		Name:        "wait",
+		InputSchema: Schema{Type: "object"},
+		Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+	})
+	s.AddResource(Resource{
+		URI:      "test://status",
+		Name:     "status",
+		MimeType: "application/json",
+		Read:     func() (string, error) { return `{"ok":true}`, nil },
+	})
+	return s
+}
+
+func connect(t *testing.T, s *Server) *Client {
+	t.Helper()
+	c := s.Connect(context.Background())
+	t.Cleanup(func() { c.Close() })
+	if err := c.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	return c
+}
+
+func TestInitialize(t *testing.T) {
+	c := newTestServer().Connect(context.Background())
+	defer c.Close()
+
+	var result struct {
+		ProtocolVersion string            `json:"protocolVersion"`
+		ServerInfo      map[string]string `json:"serverInfo"`
+		Capabilities    map[string]json.RawMessage
+	}
+	if err := c.Call("initialize", map[string]interface{}{"protocolVersion": ProtocolVersion}, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.ProtocolVersion != ProtocolVersion || result.ServerInfo["name"] != "test" || result.ServerInfo["version"] != "1.0" {
+		t.Errorf("Unexpected initialize result: %+v", result)
+	}
+	if result.Capabilities["tools"] == nil || result.Capabilities["resources"] == nil {
+		t.Errorf("Expected tools and resources capabilities, got %v", result.Capabilities)
+	}
+	if err := c.Call("ping", nil, nil); err != nil {
+		t.Errorf("Expected ping to succeed, got %v", err)
+	}
+}
+
+func TestTools(t *testing.T) {
+	c := connect(t, newTestServer())
+
+	var list struct {
+		Tools []Tool `json:"tools"`
+	}
+	if err := c.Call("tools/list", nil, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Tools) != 3 || list.Tools[0].Name != "echo" || list.Tools[0].InputSchema.Required[0] != "text" {
+		t.Errorf("Unexpected tools: %+v", list.Tools)
+	}
+
+	var result toolResult
+	if err := c.Call("tools/call", map[string]interface{}{"name": "echo", "arguments": map[string]string{"text": "hi"}}, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError || len(result.Content) != 1 || result.Content[0].Type != "text" || result.Content[0].Text != "hi" {
+		t.Errorf("Unexpected echo result: %+v", result)
+	}
+
+	result = toolResult{}
+	if err := c.Call("tools/call", map[string]interface{}{"name": "fail"}, &result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError || result.Content[0].Text != "partial output\n\nError: boom" {
+		t.Errorf("Expected the failure as a tool result, got %+v", result)
+	}
+
+	var rpcErr *Error
+	err := c.Call("tools/call", map[string]interface{}{"name": "missing"}, nil)
+	if !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidParams {
+		t.Errorf("Expected invalid params for an unknown tool, got %v", err)
+	}
+	err = c.Call("prompts/list", nil, nil)
+	if !errors.As(err, &rpcErr) || rpcErr.Code != CodeMethodNotFound {
+		t.Errorf("Expected method not found, got %v", err)
+	}
+}
+
+func TestResources(t *testing.T) {
+	c := connect(t, newTestServer())
+
+	var list struct {
+		Resources []Resource `json:"resources"`
+	}
+	if err := c.Call("resources/list", nil, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Resources) != 1 || list.Resources[0].URI != "test://status" {
+		t.Errorf("Unexpected resources: %+v", list.Resources)
+	}
+
+	var read struct {
+		Contents []map[string]string `json:"contents"`
+	}
+	if err := c.Call("resources/read", map[string]string{"uri": "test://status"}, &read); err != nil {
+		t.Fatal(err)
+	}
+	if len(read.Contents) != 1 || read.Contents[0]["text"] != `{"ok":true}` || read.Contents[0]["mimeType"] != "application/json" {
+		t.Errorf("Unexpected contents: %+v", read.Contents)
+	}
+
+	var rpcErr *Error
+	err := c.Call("resources/read", map[string]string{"uri": "test://missing"}, nil)
+	if !errors.As(err, &rpcErr) || rpcErr.Code != CodeResourceNotFound {
+		t.Errorf("Expected resource not found, got %v", err)
+	}
+}
+
+func TestCancelledRequest(t *testing.T) {
+	s := newTestServer()
+	in := strings.NewReader(strings.Join([]string{
+		`{"jsonrpc": "2.0", "id": 7, "method": "tools/call", "params": {"name": "wait"}}`,
+		`{"jsonrpc": "2.0", "method": "notifications/cancelled", "params": {"requestId": 7}}`,
+		`not json`,
+		`{"jsonrpc": "1.0", "id": 8, "method": "ping"}`,
+	}, "\n"))
+	var out bytes.Buffer
+
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(context.Background(), in, &out) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected a clean exit at end of input, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the cancelled tool call to return")
+	}
+
+	var codes []int
+	var cancelled bool
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var msg message
+		if err := dec.Decode(&msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Error != nil {
+			codes = append(codes, msg.Error.Code)
+		}
+		if string(msg.ID) == "7" && strings.Contains(string(msg.Result), "context canceled") {
+			cancelled = true
+		}
+	}
+	if !cancelled {
+		t.Error("Expected the cancelled call to report context canceled")
+	}
+	if len(codes) != 2 || codes[0] != CodeParseError || codes[1] != CodeInvalidRequest {
+		t.Errorf("Expected parse and invalid request errors, got %v", codes)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"time"
 )
@@ -24,6 +25,7 @@ type AnthropicProvider struct {
 	model     string
 	maxTokens int
 	client    *http.Client
+	models    *modelCatalog
 }
 
 type anthropicRequest struct {
@@ -32,6 +34,26 @@ type anthropicRequest struct {
 	System    string             `json:"system,omitempty"`
 	Messages  []anthropicMessage `json:"messages"`
 	Stream    bool               `json:"stream,omitempty"`
+
+	// Seed and the penalties have no Anthropic equivalent
+	Temperature   *float64 `json:"temperature,omitempty"`
+	TopP          *float64 `json:"top_p,omitempty"`
+	TopK          *int     `json:"top_k,omitempty"`
+	StopSequences []string `json:"stop_sequences,omitempty"`
+}
+
+// anthropicModelList is a page of the models endpoint
+type anthropicModelList struct {
+	Data []struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name"`
+	} `json:"data"`
+	HasMore bool   `json:"has_more"`
+	LastID  string `json:"last_id"`
+	Error   *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
 }
 
 type anthropicMessage struct {
@@ -71,16 +93,26 @@ func NewAnthropicProvider(name string, config map[string]any) (*AnthropicProvide
 		apiKey = os.Getenv(apiKeyEnv)
 	}
 
-	return &AnthropicProvider{
+	client, err := newHTTPClient(config, 120*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s: %w", name, err)
+	}
+
+	p := &AnthropicProvider{
 		name:      name,
 		endpoint:  endpoint,
 		apiKey:    apiKey,
 		model:     model,
 		maxTokens: maxTokens,
-		client: &http.Client{
-			Timeout: 120 * time.Second,
-		},
-	}, nil
+		client:    client,
+	}
+
+	var fetch func(ctx context.Context) ([]ModelInfo, error)
+	if modelsURL := modelsEndpoint(config, endpoint, "/messages"); modelsURL != "" {
+		fetch = func(ctx context.Context) ([]ModelInfo, error) { return p.fetchModels(ctx, modelsURL) }
+	}
+	p.models = newModelCatalog(config, model, fetch)
+	return p, nil
 }
 
 func (p *AnthropicProvider) Name() string {
@@ -91,8 +123,65 @@ func (p *AnthropicProvider) IsAvailable() bool {
 	return p.apiKey != ""
 }
 
+// GetModels returns the cached models the API lists, or the configured
+// models until they have been discovered; it never waits on the API
 func (p *AnthropicProvider) GetModels() []string {
-	return []string{"claude-sonnet-4-20250514", "claude-3-5-sonnet-20241022", "claude-3-opus-20240229"}
+	return p.models.ids()
+}
+
+// ListModels describes the API's models, newest first
+func (p *AnthropicProvider) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	return p.models.list(ctx)
+}
+
+func (p *AnthropicProvider) fetchModels(ctx context.Context, modelsURL string) ([]ModelInfo, error) {
+	if !p.IsAvailable() {
+		return nil, fmt.Errorf("provider %s is not available (missing API key)", p.name)
+	}
+
+	var models []ModelInfo
+	afterID := ""
+	for {
+		pageURL := modelsURL + "?limit=1000"
+		if afterID != "" {
+			pageURL += "&after_id=" + url.QueryEscape(afterID)
+		}
+		req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("x-api-key", p.apiKey)
+		req.Header.Set("anthropic-version", anthropicAPIVersion)
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		var page anthropicModelList
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse models: %w", err)
+		}
+		if page.Error != nil {
+			return nil, fmt.Errorf("API error: %s - %s", page.Error.Type, page.Error.Message)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, truncate(string(body), 200))
+		}
+
+		for _, m := range page.Data {
+			models = append(models, ModelInfo{ID: m.ID, DisplayName: m.DisplayName})
+		}
+		if !page.HasMore || page.LastID == "" {
+			return models, nil
+		}
+		afterID = page.LastID
+	}
 }
 
 func (p *AnthropicProvider) Execute(ctx context.Context, request CompletionRequest) (*CompletionResponse, error) {
@@ -119,7 +208,11 @@ func (p *AnthropicProvider) Execute(ctx context.Context, request CompletionReque
 		Messages: []anthropicMessage{
 			{Role: "user", Content: request.Prompt},
 		},
-		Stream: false,
+		Stream:        false,
+		Temperature:   request.Temperature,
+		TopP:          request.TopP,
+		TopK:          request.TopK,
+		StopSequences: request.Stop,
 	}
 
 	jsonData, err := json.Marshal(anthropicReq)
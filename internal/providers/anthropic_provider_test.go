@@ -5,11 +5,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
 
 func TestNewAnthropicProvider(t *testing.T) {
+	// GetModels discovers models in the background; keep that off the network
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{"id": "claude-test"}], "has_more": false}`))
+	}))
+	defer server.Close()
+
 	tests := []struct {
 		name        string
 		config      map[string]any
@@ -19,7 +26,7 @@ func TestNewAnthropicProvider(t *testing.T) {
 		{
 			name: "Valid config with API key",
 			config: map[string]any{
-				"endpoint":   "https://api.anthropic.com/v1/messages",
+				"endpoint":   server.URL + "/v1/messages",
 				"api_key":    "test-key",
 				"model":      "claude-sonnet-4-20250514",
 				"max_tokens": 4096,
@@ -30,7 +37,7 @@ func TestNewAnthropicProvider(t *testing.T) {
 		{
 			name: "Valid config with API key env var",
 			config: map[string]any{
-				"endpoint":    "https://api.anthropic.com/v1/messages",
+				"endpoint":    server.URL + "/v1/messages",
 				"api_key_env": "NONEXISTENT_API_KEY", // Use non-existent env var
 				"model":       "claude-sonnet-4-20250514",
 				"max_tokens":  4096,
@@ -41,7 +48,7 @@ func TestNewAnthropicProvider(t *testing.T) {
 		{
 			name: "Missing API key",
 			config: map[string]any{
-				"endpoint": "https://api.anthropic.com/v1/messages",
+				"endpoint": server.URL + "/v1/messages",
 				"model":    "claude-sonnet-4-20250514",
 			},
 			expectError: false,
@@ -50,13 +57,17 @@ func TestNewAnthropicProvider(t *testing.T) {
 		{
 			name: "Default values",
 			config: map[string]any{
-				"api_key": "test-key",
+				"api_key":         "test-key",
+				"discover_models": false,
 			},
 			expectError: false,
 			expectAvail: true,
 		},
 	}
 
+	// The default api_key_env must not pick up a key from the environment
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Set environment variable if needed
@@ -209,6 +220,7 @@ func TestAnthropicProviderExecuteError(t *testing.T) {
 }
 
 func TestAnthropicProviderExecuteUnavailable(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
 	config := map[string]any{
 		"endpoint": "https://api.anthropic.com/v1/messages",
 		// No API key
@@ -300,6 +312,7 @@ func TestAnthropicProviderExecuteStream(t *testing.T) {
 }
 
 func TestAnthropicProviderExecuteStreamError(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
 	config := map[string]any{
 		"endpoint": "https://api.anthropic.com/v1/messages",
 		// No API key - provider unavailable
@@ -339,10 +352,10 @@ func TestAnthropicProviderRequestDefaults(t *testing.T) {
 		n, _ := r.Body.Read(body)
 		bodyStr := string(body[:n])
 
-		if !contains(bodyStr, "claude-sonnet-4-20250514") {
+		if !strings.Contains(bodyStr, "claude-sonnet-4-20250514") {
 			t.Error("Expected default model to be used")
 		}
-		if !contains(bodyStr, "4096") {
+		if !strings.Contains(bodyStr, "4096") {
 			t.Error("Expected default max_tokens to be used")
 		}
 
@@ -415,3 +428,24 @@ func TestAnthropicProviderContextCancellation(t *testing.T) {
 		t.Error("Expected context cancellation error, got nil")
 	}
 }
+
+func TestAnthropicProviderSampling(t *testing.T) {
+	var body map[string]any
+	server := captureRequest(t, &body, `{"content": [{"type": "text", "text": "ok"}], "model": "m"}`)
+
+	p, _ := NewAnthropicProvider("test", map[string]any{"endpoint": server.URL, "api_key": "k"})
+	temperature, seed := 0.3, 1
+	_, err := p.Execute(context.Background(), CompletionRequest{
+		Prompt:   "hi",
+		Sampling: Sampling{Temperature: &temperature, Seed: &seed, Stop: []string{"END"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if body["temperature"] != 0.3 || body["stop_sequences"].([]any)[0] != "END" {
+		t.Errorf("Expected temperature and stop_sequences, got %v", body)
+	}
+	if _, ok := body["seed"]; ok {
+		t.Errorf("Expected seed to be dropped for Anthropic, got %v", body)
+	}
+}
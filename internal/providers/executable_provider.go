@@ -4,16 +4,47 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// defaultMaxOutputBytes bounds executable output unless max_output_bytes is set
+const defaultMaxOutputBytes = 10 << 20
+
+// maxStderrBytes bounds the stderr kept for error messages
+const maxStderrBytes = 64 << 10
+
+// killWait is how long to wait for output pipes after the process is killed
+const killWait = 2 * time.Second
+
 // ExecutableProvider implements Provider for custom executable scripts
 type ExecutableProvider struct {
-	name       string
+	commandConfig
+	name      string
+	maxOutput int
+}
+
+// NewExecutableProvider creates a new executable-based provider
+func NewExecutableProvider(name string, config map[string]any) (*ExecutableProvider, error) {
+	cc, err := parseCommandConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w for executable provider", err)
+	}
+	return &ExecutableProvider{
+		commandConfig: cc,
+		name:          name,
+		maxOutput:     getConfigInt(config, "max_output_bytes", defaultMaxOutputBytes),
+	}, nil
+}
+
+// commandConfig configures the process run by executable and plugin providers
+type commandConfig struct {
 	executable string
 	args       []string
 	env        map[string]string
@@ -21,11 +52,10 @@ type ExecutableProvider struct {
 	timeout    time.Duration
 }
 
-// NewExecutableProvider creates a new executable-based provider
-func NewExecutableProvider(name string, config map[string]any) (*ExecutableProvider, error) {
+func parseCommandConfig(config map[string]any) (commandConfig, error) {
 	executable := getConfigString(config, "executable", "")
 	if executable == "" {
-		return nil, fmt.Errorf("executable path is required for executable provider")
+		return commandConfig{}, fmt.Errorf("executable path is required")
 	}
 
 	// Expand ~ to home directory
@@ -56,35 +86,54 @@ func NewExecutableProvider(name string, config map[string]any) (*ExecutableProvi
 		}
 	}
 
-	workDir := getConfigString(config, "work_dir", "")
-	timeout := time.Duration(getConfigInt(config, "timeout_seconds", 300)) * time.Second
-
-	return &ExecutableProvider{
-		name:       name,
+	return commandConfig{
 		executable: executable,
 		args:       args,
 		env:        env,
-		workDir:    workDir,
-		timeout:    timeout,
+		workDir:    getConfigString(config, "work_dir", ""),
+		timeout:    time.Duration(getConfigInt(config, "timeout_seconds", 300)) * time.Second,
 	}, nil
 }
 
-func (p *ExecutableProvider) Name() string {
-	return p.name
+// command builds the process with the configured args, environment and directory
+func (c commandConfig) command(ctx context.Context, extraArgs ...string) *exec.Cmd {
+	args := append(append([]string{}, c.args...), extraArgs...)
+	cmd := exec.CommandContext(ctx, c.executable, args...)
+
+	cmd.Env = os.Environ()
+	for k, v := range c.env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	if c.workDir != "" {
+		cmd.Dir = c.workDir
+	}
+
+	setProcessGroup(cmd)
+	cmd.WaitDelay = killWait
+	return cmd
 }
 
-func (p *ExecutableProvider) IsAvailable() bool {
+// installed reports whether the executable exists and can be run
+func (c commandConfig) installed() bool {
 	// Check if executable exists and is executable
-	info, err := os.Stat(p.executable)
+	info, err := os.Stat(c.executable)
 	if err != nil {
 		// Try to find in PATH
-		_, err = exec.LookPath(p.executable)
+		_, err = exec.LookPath(c.executable)
 		return err == nil
 	}
 	// Check if it's executable
 	return info.Mode()&0111 != 0
 }
 
+func (p *ExecutableProvider) Name() string {
+	return p.name
+}
+
+func (p *ExecutableProvider) IsAvailable() bool {
+	return p.installed()
+}
+
 func (p *ExecutableProvider) GetModels() []string {
 	// Executable providers typically don't have models
 	return []string{"default"}
@@ -93,67 +142,183 @@ func (p *ExecutableProvider) GetModels() []string {
 func (p *ExecutableProvider) Execute(ctx context.Context, request CompletionRequest) (*CompletionResponse, error) {
 	start := time.Now()
 
+	var out strings.Builder
+	err := p.run(ctx, request, func(s string) error {
+		out.WriteString(s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompletionResponse{
+		Content:  out.String(),
+		Model:    "executable:" + filepath.Base(p.executable),
+		Duration: time.Since(start),
+	}, nil
+}
+
+// ExecuteStream emits stdout as it is written, one chunk per read
+func (p *ExecutableProvider) ExecuteStream(ctx context.Context, request CompletionRequest) (<-chan StreamChunk, error) {
+	chunks := make(chan StreamChunk)
+
+	go func() {
+		defer close(chunks)
+		err := p.run(ctx, request, func(s string) error {
+			select {
+			case chunks <- StreamChunk{Content: s}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		select {
+		case chunks <- StreamChunk{Error: err, Done: true}:
+		case <-ctx.Done():
+		}
+	}()
+
+	return chunks, nil
+}
+
+// run starts the executable with the prompt as last argument and on stdin,
+// and passes stdout to emit as it arrives. Cancellation, the timeout and
+// the output limit kill the process group.
+func (p *ExecutableProvider) run(ctx context.Context, request CompletionRequest, emit func(string) error) error {
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(ctx, p.timeout)
 	defer cancel()
 
-	// Build command
-	args := append(p.args, request.Prompt)
-	cmd := exec.CommandContext(ctx, p.executable, args...)
-
-	// Set environment
-	cmd.Env = os.Environ()
-	for k, v := range p.env {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
-	}
+	cmd := p.command(ctx, request.Prompt)
 
 	// Add system prompt as environment variable if present
 	if request.System != "" {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("FABRIC_SYSTEM=%s", request.System))
 	}
-
-	// Set working directory
-	if p.workDir != "" {
-		cmd.Dir = p.workDir
-	}
-
-	// Capture output
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	cmd.Env = append(cmd.Env, samplingEnv(request.Sampling)...)
 
 	// Provide input via stdin
 	cmd.Stdin = strings.NewReader(request.Prompt)
 
-	err := cmd.Run()
+	stderr := &limitedBuffer{max: maxStderrBytes}
+	cmd.Stderr = stderr
+	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			return nil, fmt.Errorf("execution timed out after %v", p.timeout)
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("execution failed: %w", err)
+	}
+
+	var runErr error
+	var pending []byte // an incomplete UTF-8 sequence held for the next read
+	total := 0
+	buf := make([]byte, 32*1024)
+	for runErr == nil {
+		n, err := stdout.Read(buf)
+		if n > 0 {
+			total += n
+			if p.maxOutput > 0 && total > p.maxOutput {
+				runErr = fmt.Errorf("output exceeded %d bytes (max_output_bytes)", p.maxOutput)
+				break
+			}
+			data := append(pending, buf[:n]...)
+			cut := completeUTF8(data)
+			pending = append([]byte(nil), data[cut:]...)
+			if cut > 0 {
+				runErr = emit(string(data[:cut]))
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				runErr = err
+			}
+			break
 		}
-		return nil, fmt.Errorf("execution failed: %w\nstderr: %s", err, stderr.String())
+	}
+	if runErr == nil && len(pending) > 0 {
+		runErr = emit(string(pending))
+	}
+	if runErr != nil {
+		cmd.Cancel()
 	}
 
-	return &CompletionResponse{
-		Content:  stdout.String(),
-		Model:    "executable:" + filepath.Base(p.executable),
-		Duration: time.Since(start),
-	}, nil
+	waitErr := cmd.Wait()
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		return fmt.Errorf("execution timed out after %v", p.timeout)
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case runErr != nil:
+		return runErr
+	case waitErr != nil:
+		return fmt.Errorf("execution failed: %w\nstderr: %s", waitErr, stderr.String())
+	}
+	return nil
 }
 
-func (p *ExecutableProvider) ExecuteStream(ctx context.Context, request CompletionRequest) (<-chan StreamChunk, error) {
-	// Executable providers don't support streaming natively
-	// Return the full result as a single chunk
-	chunks := make(chan StreamChunk, 1)
+// samplingEnv passes the set sampling parameters as FABRIC_TEMPERATURE,
+// FABRIC_TOP_P and so on; stop sequences are newline-separated
+func samplingEnv(s Sampling) []string {
+	var env []string
+	addFloat := func(name string, v *float64) {
+		if v != nil {
+			env = append(env, name+"="+strconv.FormatFloat(*v, 'g', -1, 64))
+		}
+	}
+	addInt := func(name string, v *int) {
+		if v != nil {
+			env = append(env, name+"="+strconv.Itoa(*v))
+		}
+	}
+	addFloat("FABRIC_TEMPERATURE", s.Temperature)
+	addFloat("FABRIC_TOP_P", s.TopP)
+	addInt("FABRIC_TOP_K", s.TopK)
+	if len(s.Stop) > 0 {
+		env = append(env, "FABRIC_STOP="+strings.Join(s.Stop, "\n"))
+	}
+	addInt("FABRIC_SEED", s.Seed)
+	addFloat("FABRIC_PRESENCE_PENALTY", s.PresencePenalty)
+	addFloat("FABRIC_FREQUENCY_PENALTY", s.FrequencyPenalty)
+	return env
+}
 
-	go func() {
-		defer close(chunks)
-		resp, err := p.Execute(ctx, request)
-		if err != nil {
-			chunks <- StreamChunk{Error: err, Done: true}
-			return
+// completeUTF8 returns the length of the longest prefix of b that does not
+// end inside a UTF-8 sequence
+func completeUTF8(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return i
+			}
+			break
 		}
-		chunks <- StreamChunk{Content: resp.Content, Done: true}
-	}()
+	}
+	return len(b)
+}
 
-	return chunks, nil
+// limitedBuffer keeps the first max bytes written to it and silently drops
+// the rest; writes always report success so the process is not cut off
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(data []byte) (int, error) {
+	kept := data
+	if room := b.max - b.buf.Len(); len(kept) > room {
+		b.truncated = true
+		kept = kept[:max(room, 0)]
+	}
+	b.buf.Write(kept)
+	return len(data), nil
+}
+
+func (b *limitedBuffer) String() string {
+	s := strings.TrimSpace(b.buf.String())
+	if b.truncated {
+		s += "\n[truncated]"
+	}
+	return s
 }
@@ -0,0 +1,159 @@
+package providers
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newShellProvider runs script with sh; the prompt arrives as $1
+func newShellProvider(t *testing.T, script string, extra map[string]any) *ExecutableProvider {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires /bin/sh")
+	}
+	config := map[string]any{
+		"executable": "/bin/sh",
+		"args":       []any{"-c", script, "sh"},
+	}
+	for k, v := range extra {
+		config[k] = v
+	}
+	p, err := NewExecutableProvider("shell", config)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return p
+}
+
+func TestExecutableProviderExecute(t *testing.T) {
+	p := newShellProvider(t, `echo "$FABRIC_SYSTEM: $1"; cat`, nil)
+	resp, err := p.Execute(context.Background(), CompletionRequest{System: "sys", Prompt: "hi"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Content != "sys: hi\nhi" || resp.Model != "executable:sh" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+
+	p = newShellProvider(t, `echo partial; echo bad >&2; exit 3`, nil)
+	if _, err := p.Execute(context.Background(), CompletionRequest{Prompt: "hi"}); err == nil || !strings.Contains(err.Error(), "stderr: bad") {
+		t.Errorf("Expected the error to include stderr, got %v", err)
+	}
+}
+
+func TestExecutableProviderSampling(t *testing.T) {
+	p := newShellProvider(t, `echo "$FABRIC_TEMPERATURE $FABRIC_TOP_K $FABRIC_STOP"`, nil)
+	temperature, k := 0.25, 5
+	resp, err := p.Execute(context.Background(), CompletionRequest{
+		Prompt:   "hi",
+		Sampling: Sampling{Temperature: &temperature, TopK: &k, Stop: []string{"END"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Content != "0.25 5 END\n" {
+		t.Errorf("Expected the sampling environment, got %q", resp.Content)
+	}
+}
+
+func TestExecutableProviderExecuteStream(t *testing.T) {
+	flag := filepath.Join(t.TempDir(), "continue")
+	// The second line is only written once the test has seen the first
+	script := `echo one; while [ ! -f "` + flag + `" ]; do sleep 0.01; done; echo two`
+	p := newShellProvider(t, script, nil)
+
+	chunks, err := p.ExecuteStream(context.Background(), CompletionRequest{Prompt: "hi"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var parts []string
+	var done bool
+	for chunk := range chunks {
+		if chunk.Error != nil {
+			t.Fatalf("Unexpected stream error: %v", chunk.Error)
+		}
+		if chunk.Done {
+			done = true
+			continue
+		}
+		parts = append(parts, chunk.Content)
+		if len(parts) == 1 {
+			os.WriteFile(flag, nil, 0644)
+		}
+	}
+	if strings.Join(parts, "") != "one\ntwo\n" || parts[0] != "one\n" || !done {
+		t.Errorf("Expected output before the process exited, got %q, done %v", parts, done)
+	}
+}
+
+func TestExecutableProviderMaxOutput(t *testing.T) {
+	p := newShellProvider(t, `yes | head -c 100000`, map[string]any{"max_output_bytes": 1000})
+	if _, err := p.Execute(context.Background(), CompletionRequest{Prompt: "hi"}); err == nil || !strings.Contains(err.Error(), "exceeded 1000 bytes") {
+		t.Errorf("Expected an output limit error, got %v", err)
+	}
+}
+
+func TestExecutableProviderLargeStderr(t *testing.T) {
+	// Stderr beyond the kept 64KB is dropped without failing the run
+	p := newShellProvider(t, `yes warning | head -c 100000 >&2; echo done`, nil)
+	resp, err := p.Execute(context.Background(), CompletionRequest{Prompt: "hi"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Content != "done\n" {
+		t.Errorf("Expected the output, got %q", resp.Content)
+	}
+
+	var buf limitedBuffer
+	buf.max = 4
+	if n, err := buf.Write([]byte("abcdef")); n != 6 || err != nil {
+		t.Errorf("Expected the full write to be reported, got %d, %v", n, err)
+	}
+	if buf.String() != "abcd\n[truncated]" {
+		t.Errorf("Expected the first 4 bytes, got %q", buf.String())
+	}
+}
+
+func TestExecutableProviderKillsProcessGroup(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "pid")
+	p := newShellProvider(t, `sleep 30 & echo $! > "`+pidFile+`"; wait`, map[string]any{"timeout_seconds": 1})
+
+	start := time.Now()
+	_, err := p.Execute(context.Background(), CompletionRequest{Prompt: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Expected the timeout to stop the provider promptly, took %v", elapsed)
+	}
+
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	deadline := time.Now().Add(2 * time.Second)
+	for exec.Command("kill", "-0", strconv.Itoa(pid)).Run() == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the child process %d to be killed", pid)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestCompleteUTF8(t *testing.T) {
+	b := []byte("héllo")
+	for n, want := range map[int]int{1: 1, 2: 1, 3: 3, len(b): len(b)} {
+		if got := completeUTF8(b[:n]); got != want {
+			t.Errorf("completeUTF8(%q) = %d, want %d", b[:n], got, want)
+		}
+	}
+}
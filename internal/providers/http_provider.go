@@ -8,29 +8,73 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"sort"
 	"strings"
+	"text/template"
 	"time"
 )
 
+// Ways of sending the API key
+const (
+	AuthBearer = "bearer" // Authorization: Bearer <key> (default)
+	AuthHeader = "header" // <auth_header>: <key>, e.g. Azure's api-key
+	AuthNone   = "none"   // No key, e.g. a local server or an authenticating proxy
+)
+
+// Azure OpenAI defaults for the azure provider type
+const (
+	azureAPIVersion = "2024-10-21"
+	azureEndpoint   = "{{.BaseURL}}/openai/deployments/{{.Deployment}}/chat/completions"
+)
+
 // HTTPProvider implements Provider for OpenAI-compatible APIs
 type HTTPProvider struct {
-	name      string
-	endpoint  string
-	apiKey    string
-	model     string
-	headers   map[string]string
-	client    *http.Client
-	maxTokens int
+	name        string
+	endpoint    string // May be a template, see endpointData
+	apiKey      string
+	auth        string
+	model       string
+	headers     map[string]string
+	client      *http.Client
+	maxTokens   int
+	models      *modelCatalog
+	url         *template.Template
+	baseURL     string
+	apiVersion  string
+	deployment  string
+	deployments map[string]string
+}
+
+// endpointData is what endpoint templates see
+type endpointData struct {
+	Model      string // The request's model
+	Deployment string // deployments[model], else deployment, else the model
+	APIVersion string
+	BaseURL    string
 }
 
 // OpenAI API request/response structures
 type openAIRequest struct {
-	Model       string          `json:"model"`
-	Messages    []openAIMessage `json:"messages"`
-	MaxTokens   int             `json:"max_tokens,omitempty"`
-	Temperature float64         `json:"temperature,omitempty"`
-	Stream      bool            `json:"stream,omitempty"`
+	Model     string          `json:"model"`
+	Messages  []openAIMessage `json:"messages"`
+	MaxTokens int             `json:"max_tokens,omitempty"`
+	Stream    bool            `json:"stream,omitempty"`
+	Sampling                  // Same field names as OpenAI; top_k is for local servers
+}
+
+// openAIModelList is a /v1/models response. OpenAI only sends ids; the
+// context fields come from OpenRouter and LM Studio (context_length,
+// max_context_length) and vLLM (max_model_len).
+type openAIModelList struct {
+	Data []struct {
+		ID               string          `json:"id"`
+		ContextLength    int             `json:"context_length"`
+		MaxContextLength int             `json:"max_context_length"`
+		MaxModelLen      int             `json:"max_model_len"`
+		Capabilities     json.RawMessage `json:"capabilities"`
+	} `json:"data"`
 }
 
 type openAIMessage struct {
@@ -79,20 +123,129 @@ func NewHTTPProvider(name string, config map[string]any) (*HTTPProvider, error)
 		apiKey = os.Getenv(apiKeyEnv)
 	}
 
-	return &HTTPProvider{
-		name:      name,
-		endpoint:  endpoint,
-		apiKey:    apiKey,
-		model:     model,
-		maxTokens: maxTokens,
-		headers: map[string]string{
-			"Content-Type":  "application/json",
-			"Authorization": "Bearer " + apiKey,
-		},
-		client: &http.Client{
-			Timeout: 120 * time.Second,
-		},
-	}, nil
+	client, err := newHTTPClient(config, 120*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s: %w", name, err)
+	}
+
+	p := &HTTPProvider{
+		name:        name,
+		endpoint:    endpoint,
+		apiKey:      apiKey,
+		auth:        getConfigString(config, "auth", AuthBearer),
+		model:       model,
+		maxTokens:   maxTokens,
+		headers:     map[string]string{"Content-Type": "application/json"},
+		client:      client,
+		baseURL:     strings.TrimRight(getConfigString(config, "base_url", ""), "/"),
+		apiVersion:  getConfigString(config, "api_version", ""),
+		deployment:  getConfigString(config, "deployment", ""),
+		deployments: getConfigStringMap(config, "deployments"),
+	}
+
+	switch p.auth {
+	case AuthBearer:
+		p.headers["Authorization"] = "Bearer " + apiKey
+	case AuthHeader:
+		p.headers[getConfigString(config, "auth_header", "api-key")] = apiKey
+	case AuthNone:
+	default:
+		return nil, fmt.Errorf("provider %s: invalid auth %q (expected bearer, header or none)", name, p.auth)
+	}
+	// Extra headers may override the defaults; $VARS are expanded
+	for k, v := range getConfigStringMap(config, "headers") {
+		p.headers[k] = os.ExpandEnv(v)
+	}
+
+	if p.url, err = template.New("endpoint").Parse(endpoint); err != nil {
+		return nil, fmt.Errorf("provider %s: endpoint: %w", name, err)
+	}
+
+	// OpenAI-compatible servers (LM Studio, vLLM, ...) list models next
+	// to chat/completions; templated endpoints need models_endpoint
+	var fetch func(ctx context.Context) ([]ModelInfo, error)
+	modelsURL := getConfigString(config, "models_endpoint", "")
+	if !strings.Contains(endpoint, "{{") {
+		modelsURL = modelsEndpoint(config, endpoint, "/chat/completions")
+	}
+	if modelsURL != "" {
+		modelsURL = p.withAPIVersion(modelsURL)
+		fetch = func(ctx context.Context) ([]ModelInfo, error) { return p.fetchModels(ctx, modelsURL) }
+	}
+	p.models = newModelCatalog(config, model, fetch)
+	return p, nil
+}
+
+// NewAzureProvider creates an HTTPProvider for Azure OpenAI. It defaults to
+// the api-key header from AZURE_OPENAI_API_KEY, the deployment URL under
+// base_url (default $AZURE_OPENAI_ENDPOINT) and a stable api_version.
+// Deployments are listed from the deployments config rather than discovered.
+func NewAzureProvider(name string, config map[string]any) (*HTTPProvider, error) {
+	azure := map[string]any{
+		"auth":            AuthHeader,
+		"auth_header":     "api-key",
+		"api_key_env":     "AZURE_OPENAI_API_KEY",
+		"api_version":     azureAPIVersion,
+		"endpoint":        azureEndpoint,
+		"base_url":        os.Getenv("AZURE_OPENAI_ENDPOINT"),
+		"discover_models": false,
+	}
+	for k, v := range config {
+		azure[k] = v
+	}
+	if _, ok := config["models"]; !ok {
+		var models []any
+		for model := range getConfigStringMap(config, "deployments") {
+			models = append(models, model)
+		}
+		sort.Slice(models, func(i, j int) bool { return models[i].(string) < models[j].(string) })
+		azure["models"] = models
+	}
+	if getConfigString(azure, "endpoint", "") == azureEndpoint && getConfigString(azure, "base_url", "") == "" {
+		return nil, fmt.Errorf("provider %s: base_url (or AZURE_OPENAI_ENDPOINT) is required for azure", name)
+	}
+	return NewHTTPProvider(name, azure)
+}
+
+// requestURL renders the endpoint for model and adds the api-version query
+func (p *HTTPProvider) requestURL(model string) (string, error) {
+	deployment := p.deployments[model]
+	if deployment == "" {
+		deployment = p.deployment
+	}
+	if deployment == "" {
+		deployment = model
+	}
+
+	var buf strings.Builder
+	err := p.url.Execute(&buf, endpointData{
+		Model:      model,
+		Deployment: url.PathEscape(deployment),
+		APIVersion: p.apiVersion,
+		BaseURL:    p.baseURL,
+	})
+	if err != nil {
+		return "", fmt.Errorf("render endpoint: %w", err)
+	}
+	return p.withAPIVersion(buf.String()), nil
+}
+
+// withAPIVersion adds api-version to rawURL unless the endpoint sets it
+func (p *HTTPProvider) withAPIVersion(rawURL string) string {
+	if p.apiVersion == "" {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	q := u.Query()
+	if q.Has("api-version") {
+		return rawURL
+	}
+	q.Set("api-version", p.apiVersion)
+	u.RawQuery = q.Encode()
+	return u.String()
 }
 
 func (p *HTTPProvider) Name() string {
@@ -100,11 +253,62 @@ func (p *HTTPProvider) Name() string {
 }
 
 func (p *HTTPProvider) IsAvailable() bool {
-	return p.apiKey != "" && p.endpoint != ""
+	return (p.apiKey != "" || p.auth == AuthNone) && p.endpoint != ""
 }
 
+// GetModels returns the cached models the endpoint lists, or the
+// configured models until they have been discovered; it never waits on
+// the endpoint
 func (p *HTTPProvider) GetModels() []string {
-	return []string{"gpt-4o", "gpt-4o-mini", "gpt-4-turbo", "gpt-3.5-turbo"}
+	return p.models.ids()
+}
+
+// ListModels describes the endpoint's models
+func (p *HTTPProvider) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	return p.models.list(ctx)
+}
+
+func (p *HTTPProvider) fetchModels(ctx context.Context, modelsURL string) ([]ModelInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", modelsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, truncate(string(body), 200))
+	}
+
+	var list openAIModelList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse models: %w", err)
+	}
+
+	models := make([]ModelInfo, 0, len(list.Data))
+	for _, m := range list.Data {
+		info := ModelInfo{ID: m.ID, ContextWindow: m.ContextLength, Capabilities: stringList(m.Capabilities)}
+		if info.ContextWindow == 0 {
+			info.ContextWindow = m.MaxContextLength
+		}
+		if info.ContextWindow == 0 {
+			info.ContextWindow = m.MaxModelLen
+		}
+		models = append(models, info)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models, nil
 }
 
 func (p *HTTPProvider) Execute(ctx context.Context, request CompletionRequest) (*CompletionResponse, error) {
@@ -136,6 +340,7 @@ func (p *HTTPProvider) Execute(ctx context.Context, request CompletionRequest) (
 		Messages:  messages,
 		MaxTokens: maxTokens,
 		Stream:    false,
+		Sampling:  request.Sampling,
 	}
 
 	// Make HTTP request
@@ -144,7 +349,11 @@ func (p *HTTPProvider) Execute(ctx context.Context, request CompletionRequest) (
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", p.endpoint, bytes.NewBuffer(jsonData))
+	endpoint, err := p.requestURL(model)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -220,6 +429,7 @@ func (p *HTTPProvider) ExecuteStream(ctx context.Context, request CompletionRequ
 			Messages:  messages,
 			MaxTokens: maxTokens,
 			Stream:    true,
+			Sampling:  request.Sampling,
 		}
 
 		jsonData, err := json.Marshal(oaiReq)
@@ -228,7 +438,12 @@ func (p *HTTPProvider) ExecuteStream(ctx context.Context, request CompletionRequ
 			return
 		}
 
-		req, err := http.NewRequestWithContext(ctx, "POST", p.endpoint, bytes.NewBuffer(jsonData))
+		endpoint, err := p.requestURL(model)
+		if err != nil {
+			chunks <- StreamChunk{Error: err, Done: true}
+			return
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
 		if err != nil {
 			chunks <- StreamChunk{Error: err, Done: true}
 			return
@@ -290,6 +505,18 @@ func getConfigString(config map[string]any, key, defaultVal string) string {
 	return defaultVal
 }
 
+func getConfigStringMap(config map[string]any, key string) map[string]string {
+	values := make(map[string]string)
+	if m, ok := config[key].(map[string]any); ok {
+		for k, v := range m {
+			if s, ok := v.(string); ok {
+				values[k] = s
+			}
+		}
+	}
+	return values
+}
+
 func getConfigInt(config map[string]any, key string, defaultVal int) int {
 	if val, ok := config[key].(int); ok {
 		return val
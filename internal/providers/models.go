@@ -0,0 +1,277 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Where a model listing came from
+const (
+	ModelSourceAPI     = "api"     // Discovered from the provider's models endpoint
+	ModelSourceConfig  = "config"  // Declared in the provider's models config
+	ModelSourceDefault = "default" // The provider's default model
+)
+
+// defaultModelsTTL is how long discovered models are cached unless
+// models_cache_seconds is set
+const defaultModelsTTL = time.Hour
+
+// modelsRetryInterval is how long a failed discovery is remembered before
+// the models endpoint is tried again
+const modelsRetryInterval = time.Minute
+
+// modelsTimeout bounds the background discovery GetModels starts, which
+// has no context
+const modelsTimeout = 10 * time.Second
+
+// ModelInfo describes a model a provider serves. Zero values are unknown.
+type ModelInfo struct {
+	ID              string   `json:"id" yaml:"id"`
+	DisplayName     string   `json:"display_name,omitempty" yaml:"display_name,omitempty"`
+	ContextWindow   int      `json:"context_window,omitempty" yaml:"context_window,omitempty"`
+	MaxOutputTokens int      `json:"max_output_tokens,omitempty" yaml:"max_output_tokens,omitempty"`
+	Capabilities    []string `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	Source          string   `json:"source,omitempty" yaml:"source,omitempty"`
+}
+
+// ModelLister is implemented by providers that can describe their models
+type ModelLister interface {
+	// ListModels returns the provider's models. When discovery fails it
+	// returns the fallback models together with the error.
+	ListModels(ctx context.Context) ([]ModelInfo, error)
+}
+
+// ListModels describes p's models, falling back to the names from GetModels
+// for providers that don't implement ModelLister
+func ListModels(ctx context.Context, p Provider) ([]ModelInfo, error) {
+	if lister, ok := p.(ModelLister); ok {
+		return lister.ListModels(ctx)
+	}
+	names := p.GetModels()
+	models := make([]ModelInfo, len(names))
+	for i, name := range names {
+		models[i] = ModelInfo{ID: name}
+	}
+	return models, nil
+}
+
+// modelIDs returns the ids of models
+func modelIDs(models []ModelInfo) []string {
+	ids := make([]string, len(models))
+	for i, m := range models {
+		ids[i] = m.ID
+	}
+	return ids
+}
+
+// modelCatalog caches the models a provider discovers and falls back to
+// the configured ones when discovery fails. The lock is never held while
+// the models endpoint is called.
+type modelCatalog struct {
+	fetch    func(ctx context.Context) ([]ModelInfo, error)
+	declared []ModelInfo
+	fallback string // Default model, listed when none are declared
+	ttl      time.Duration
+
+	mu         sync.Mutex
+	models     []ModelInfo
+	err        error
+	fetched    time.Time
+	refreshing bool // A background refresh started by ids is running
+}
+
+// newModelCatalog reads the models, models_cache_seconds and
+// discover_models config keys. Without discovery, fetch is never called.
+func newModelCatalog(config map[string]any, defaultModel string, fetch func(ctx context.Context) ([]ModelInfo, error)) *modelCatalog {
+	c := &modelCatalog{
+		fetch:    fetch,
+		declared: parseDeclaredModels(config["models"]),
+		fallback: defaultModel,
+		ttl:      time.Duration(getConfigInt(config, "models_cache_seconds", int(defaultModelsTTL/time.Second))) * time.Second,
+	}
+	if discover, ok := config["discover_models"].(bool); ok && !discover {
+		c.fetch = nil
+	}
+	return c
+}
+
+// list returns the discovered models, refreshing them once the cache
+// expires. When discovery fails it returns the offline models and the error.
+func (c *modelCatalog) list(ctx context.Context) ([]ModelInfo, error) {
+	if c.fetch == nil {
+		return c.offline(), nil
+	}
+
+	c.mu.Lock()
+	stale := c.stale()
+	c.mu.Unlock()
+	if stale {
+		if err := c.refresh(ctx); err != nil && ctx.Err() != nil {
+			return c.offline(), err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cached()
+}
+
+// ids returns the model ids for GetModels without waiting on the network:
+// the cached models, or the offline ones until discovery, started here in
+// the background, has succeeded
+func (c *modelCatalog) ids() []string {
+	if c.fetch == nil {
+		return modelIDs(c.offline())
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stale() && !c.refreshing {
+		c.refreshing = true
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), modelsTimeout)
+			defer cancel()
+			c.refresh(ctx)
+
+			c.mu.Lock()
+			c.refreshing = false
+			c.mu.Unlock()
+		}()
+	}
+	models, _ := c.cached()
+	return modelIDs(models)
+}
+
+// refresh calls the models endpoint and caches the result. A failure
+// caused by ctx ending is returned without being cached, since the caller
+// gave up rather than the provider failing.
+func (c *modelCatalog) refresh(ctx context.Context) error {
+	models, err := c.fetch(ctx)
+	if err != nil && ctx.Err() != nil {
+		return err
+	}
+	if err == nil && len(models) == 0 {
+		err = fmt.Errorf("no models listed")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.models, c.err, c.fetched = c.withDeclared(models), err, time.Now()
+	return err
+}
+
+// stale reports whether the cache should be refreshed; failures are
+// retried sooner than successes. Callers must hold mu.
+func (c *modelCatalog) stale() bool {
+	maxAge := c.ttl
+	if c.err != nil && modelsRetryInterval < maxAge {
+		maxAge = modelsRetryInterval
+	}
+	return c.fetched.IsZero() || time.Since(c.fetched) >= maxAge
+}
+
+// cached returns the cached models, or the offline models and the error
+// when the last discovery failed or none has finished. Callers must hold mu.
+func (c *modelCatalog) cached() ([]ModelInfo, error) {
+	if c.err != nil || c.fetched.IsZero() {
+		return c.offline(), c.err
+	}
+	return c.models, nil
+}
+
+// offline returns the declared models, or the default model
+func (c *modelCatalog) offline() []ModelInfo {
+	if len(c.declared) > 0 {
+		return c.declared
+	}
+	if c.fallback == "" {
+		return nil
+	}
+	return []ModelInfo{{ID: c.fallback, Source: ModelSourceDefault}}
+}
+
+// withDeclared fills in details the API doesn't report from the declared
+// models with the same id
+func (c *modelCatalog) withDeclared(models []ModelInfo) []ModelInfo {
+	for i := range models {
+		models[i].Source = ModelSourceAPI
+		for _, d := range c.declared {
+			if d.ID != models[i].ID {
+				continue
+			}
+			if models[i].DisplayName == "" {
+				models[i].DisplayName = d.DisplayName
+			}
+			if models[i].ContextWindow == 0 {
+				models[i].ContextWindow = d.ContextWindow
+			}
+			if models[i].MaxOutputTokens == 0 {
+				models[i].MaxOutputTokens = d.MaxOutputTokens
+			}
+			if len(models[i].Capabilities) == 0 {
+				models[i].Capabilities = d.Capabilities
+			}
+		}
+	}
+	return models
+}
+
+// parseDeclaredModels reads a models list whose entries are model ids or
+// maps with id, display_name, context_window, max_output_tokens and
+// capabilities
+func parseDeclaredModels(value any) []ModelInfo {
+	items, _ := value.([]any)
+	var models []ModelInfo
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			models = append(models, ModelInfo{ID: v, Source: ModelSourceConfig})
+		case map[string]any:
+			m := ModelInfo{
+				ID:              getConfigString(v, "id", ""),
+				DisplayName:     getConfigString(v, "display_name", ""),
+				ContextWindow:   getConfigInt(v, "context_window", 0),
+				MaxOutputTokens: getConfigInt(v, "max_output_tokens", 0),
+				Source:          ModelSourceConfig,
+			}
+			if caps, ok := v["capabilities"].([]any); ok {
+				for _, c := range caps {
+					if s, ok := c.(string); ok {
+						m.Capabilities = append(m.Capabilities, s)
+					}
+				}
+			}
+			if m.ID != "" {
+				models = append(models, m)
+			}
+		}
+	}
+	return models
+}
+
+// modelsEndpoint derives the models endpoint from a completion endpoint by
+// replacing suffix, unless models_endpoint is configured. It returns ""
+// when neither applies.
+func modelsEndpoint(config map[string]any, endpoint, suffix string) string {
+	if e := getConfigString(config, "models_endpoint", ""); e != "" {
+		return e
+	}
+	endpoint = strings.TrimRight(endpoint, "/")
+	if !strings.HasSuffix(endpoint, suffix) {
+		return ""
+	}
+	return strings.TrimSuffix(endpoint, suffix) + "/models"
+}
+
+// stringList decodes raw as a list of strings, ignoring any other shape
+func stringList(raw json.RawMessage) []string {
+	var list []string
+	if len(raw) == 0 || json.Unmarshal(raw, &list) != nil {
+		return nil
+	}
+	return list
+}
@@ -0,0 +1,174 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPProviderListModels(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/v1/models" {
+			t.Errorf("Expected /v1/models, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("Expected the API key, got %q", r.Header.Get("Authorization"))
+		}
+		fmt.Fprint(w, `{"data": [
+			{"id": "qwen2.5-7b", "max_model_len": 32768},
+			{"id": "llava", "context_length": 4096, "capabilities": ["vision"]},
+			{"id": "gpt-4o", "capabilities": {"ignored": true}}
+		]}`)
+	}))
+	defer server.Close()
+
+	p, err := NewHTTPProvider("local", map[string]any{
+		"endpoint": server.URL + "/v1/chat/completions",
+		"api_key":  "test-key",
+		"models":   []any{map[string]any{"id": "gpt-4o", "context_window": 128000, "capabilities": []any{"tools"}}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	models, err := p.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := strings.Join(modelIDs(models), ","); got != "gpt-4o,llava,qwen2.5-7b" {
+		t.Fatalf("Expected the discovered models sorted, got %s", got)
+	}
+	if models[0].ContextWindow != 128000 || models[0].Capabilities[0] != "tools" || models[0].Source != ModelSourceAPI {
+		t.Errorf("Expected configured details on a discovered model, got %+v", models[0])
+	}
+	if models[1].ContextWindow != 4096 || models[1].Capabilities[0] != "vision" || models[2].ContextWindow != 32768 {
+		t.Errorf("Expected context windows and capabilities from the API, got %+v", models[1:])
+	}
+
+	p.GetModels()
+	if requests.Load() != 1 {
+		t.Errorf("Expected the models to be cached, got %d requests", requests.Load())
+	}
+}
+
+func TestHTTPProviderListModelsOffline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "down"}`, http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	p, _ := NewHTTPProvider("local", map[string]any{
+		"endpoint": server.URL + "/v1/chat/completions",
+		"models":   []any{"llama3", "mistral"},
+	})
+	models, err := p.ListModels(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "status 503") {
+		t.Errorf("Expected the discovery error, got %v", err)
+	}
+	if strings.Join(modelIDs(models), ",") != "llama3,mistral" || models[0].Source != ModelSourceConfig {
+		t.Errorf("Expected the configured models, got %+v", models)
+	}
+
+	p, _ = NewHTTPProvider("local", map[string]any{
+		"endpoint": server.URL + "/v1/chat/completions",
+		"model":    "llama3",
+	})
+	if models := p.GetModels(); len(models) != 1 || models[0] != "llama3" {
+		t.Errorf("Expected the default model, got %v", models)
+	}
+
+	p, _ = NewHTTPProvider("local", map[string]any{
+		"endpoint":        "http://127.0.0.1:1/v1/chat/completions",
+		"models":          []any{"llama3"},
+		"discover_models": false,
+	})
+	if _, err := p.ListModels(context.Background()); err != nil {
+		t.Errorf("Expected no discovery, got %v", err)
+	}
+}
+
+func TestGetModelsDoesNotWaitForDiscovery(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		fmt.Fprint(w, `{"data": [{"id": "qwen2.5-7b"}]}`)
+	}))
+	defer server.Close()
+
+	p, _ := NewHTTPProvider("local", map[string]any{
+		"endpoint": server.URL + "/v1/chat/completions",
+		"models":   []any{"llama3"},
+	})
+
+	// Discovery is blocked, so the configured models are returned meanwhile
+	done := make(chan []string)
+	go func() { done <- p.GetModels() }()
+	select {
+	case models := <-done:
+		if strings.Join(models, ",") != "llama3" {
+			t.Errorf("Expected the configured models, got %v", models)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected GetModels not to wait for discovery")
+	}
+	close(release)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for strings.Join(p.GetModels(), ",") != "qwen2.5-7b" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the discovered models once the refresh finished, got %v", p.GetModels())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAnthropicProviderListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" || r.Header.Get("x-api-key") != "test-key" || r.Header.Get("anthropic-version") != anthropicAPIVersion {
+			t.Errorf("Unexpected request: %s %v", r.URL, r.Header)
+		}
+		if r.URL.Query().Get("after_id") == "" {
+			fmt.Fprint(w, `{"data": [{"id": "claude-b", "display_name": "Claude B"}], "has_more": true, "last_id": "claude-b"}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": [{"id": "claude-a", "display_name": "Claude A"}], "has_more": false}`)
+	}))
+	defer server.Close()
+
+	p, _ := NewAnthropicProvider("anthropic", map[string]any{
+		"endpoint": server.URL + "/v1/messages",
+		"api_key":  "test-key",
+	})
+	models, err := p.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Join(modelIDs(models), ",") != "claude-b,claude-a" || models[1].DisplayName != "Claude A" {
+		t.Errorf("Expected both pages in order, got %+v", models)
+	}
+}
+
+func TestModelsEndpoint(t *testing.T) {
+	tests := []struct {
+		config   map[string]any
+		endpoint string
+		want     string
+	}{
+		{nil, "http://localhost:1234/v1/chat/completions", "http://localhost:1234/v1/models"},
+		{nil, "http://localhost:1234/v1/chat/completions/", "http://localhost:1234/v1/models"},
+		{nil, "http://localhost:1234/generate", ""},
+		{map[string]any{"models_endpoint": "http://x/models"}, "http://localhost:1234/generate", "http://x/models"},
+	}
+	for _, tt := range tests {
+		if got := modelsEndpoint(tt.config, tt.endpoint, "/chat/completions"); got != tt.want {
+			t.Errorf("modelsEndpoint(%q) = %q, want %q", tt.endpoint, got, tt.want)
+		}
+	}
+}
@@ -24,6 +24,7 @@ type ollamaChatRequest struct {
 	Model    string              `json:"model"`
 	Messages []ollamaChatMessage `json:"messages"`
 	Stream   bool                `json:"stream"`
+	Options  *Sampling           `json:"options,omitempty"` // Same names as Ollama's options
 }
 
 type ollamaChatMessage struct {
@@ -121,6 +122,9 @@ func (p *OllamaProvider) Execute(ctx context.Context, request CompletionRequest)
 		Messages: messages,
 		Stream:   false,
 	}
+	if !request.Sampling.IsZero() {
+		ollamaReq.Options = &request.Sampling
+	}
 
 	jsonData, err := json.Marshal(ollamaReq)
 	if err != nil {
@@ -0,0 +1,320 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PluginProtocolVersion is the plugin protocol version this build speaks.
+//
+// A plugin is started once per request. It reads one JSON request from
+// stdin and writes JSON lines to stdout:
+//
+//	{"protocol": 1, "type": "describe"}
+//	  -> {"type": "describe", "protocol": 1, "models": [...], "capabilities": {"streaming": true}}
+//	{"protocol": 1, "type": "complete", "system": "...", "prompt": "...", "model": "...", "stream": true,
+//	 "temperature": 0.2, "top_p": 0.9, "stop": ["..."], ...}
+//	  -> {"type": "chunk", "content": "..."}            (any number)
+//	  -> {"type": "usage", "prompt_tokens": 10, "completion_tokens": 20}
+//	  -> {"type": "done", "model": "..."}
+//	  or {"type": "error", "message": "..."}
+//
+// Anything written to stderr is reported when the plugin fails.
+const PluginProtocolVersion = 1
+
+// Plugin message types
+const (
+	pluginDescribe = "describe"
+	pluginComplete = "complete"
+	pluginChunk    = "chunk"
+	pluginUsage    = "usage"
+	pluginDone     = "done"
+	pluginError    = "error"
+)
+
+// describeTimeout bounds the describe handshake
+const describeTimeout = 10 * time.Second
+
+// maxPluginLine bounds a single line of plugin output
+const maxPluginLine = 4 << 20
+
+// PluginCapabilities are the optional features a plugin supports
+type PluginCapabilities struct {
+	Streaming bool `json:"streaming"` // Sends content in several chunks
+	Usage     bool `json:"usage"`     // Reports token usage
+}
+
+// PluginDescription is a plugin's answer to the describe handshake
+type PluginDescription struct {
+	Protocol     int                `json:"protocol"`
+	Name         string             `json:"name,omitempty"`
+	Models       []string           `json:"models,omitempty"`
+	Capabilities PluginCapabilities `json:"capabilities"`
+}
+
+type pluginRequest struct {
+	Protocol  int            `json:"protocol"`
+	Type      string         `json:"type"`
+	System    string         `json:"system,omitempty"`
+	Prompt    string         `json:"prompt,omitempty"`
+	Model     string         `json:"model,omitempty"`
+	MaxTokens int            `json:"max_tokens,omitempty"`
+	Stream    bool           `json:"stream,omitempty"`
+	Options   map[string]any `json:"options,omitempty"`
+	Sampling
+}
+
+type pluginMessage struct {
+	Type string `json:"type"`
+
+	// describe
+	PluginDescription
+
+	// chunk
+	Content string `json:"content,omitempty"`
+
+	// usage
+	PromptTokens     int `json:"prompt_tokens,omitempty"`
+	CompletionTokens int `json:"completion_tokens,omitempty"`
+	TotalTokens      int `json:"total_tokens,omitempty"`
+
+	// done
+	Model string `json:"model,omitempty"`
+
+	// error
+	Message string `json:"message,omitempty"`
+}
+
+// tokens returns the total of a usage message
+func (m *pluginMessage) tokens() int {
+	if m.TotalTokens > 0 {
+		return m.TotalTokens
+	}
+	return m.PromptTokens + m.CompletionTokens
+}
+
+// PluginProvider implements Provider with an out-of-process plugin
+// speaking the JSON-over-stdio plugin protocol
+type PluginProvider struct {
+	commandConfig
+	name string
+
+	describeOnce sync.Once
+	description  *PluginDescription
+	describeErr  error
+}
+
+// NewPluginProvider creates a provider backed by a plugin executable
+func NewPluginProvider(name string, config map[string]any) (*PluginProvider, error) {
+	cc, err := parseCommandConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w for plugin provider", err)
+	}
+	return &PluginProvider{commandConfig: cc, name: name}, nil
+}
+
+func (p *PluginProvider) Name() string {
+	return p.name
+}
+
+// Describe runs the describe handshake once and caches the answer
+func (p *PluginProvider) Describe() (*PluginDescription, error) {
+	p.describeOnce.Do(func() {
+		timeout := describeTimeout
+		if p.timeout < timeout {
+			timeout = p.timeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		err := p.run(ctx, pluginRequest{Type: pluginDescribe}, func(msg *pluginMessage) error {
+			if msg.Type == pluginDescribe {
+				desc := msg.PluginDescription
+				p.description = &desc
+			}
+			return nil
+		})
+		switch {
+		case err != nil:
+			p.describeErr = err
+		case p.description == nil:
+			p.describeErr = fmt.Errorf("plugin %s: no describe response", p.name)
+		case p.description.Protocol != PluginProtocolVersion:
+			p.describeErr = fmt.Errorf("plugin %s speaks protocol %d; expected %d",
+				p.name, p.description.Protocol, PluginProtocolVersion)
+			p.description = nil
+		}
+	})
+	return p.description, p.describeErr
+}
+
+// IsAvailable reports whether the plugin is installed and answers describe
+func (p *PluginProvider) IsAvailable() bool {
+	if !p.installed() {
+		return false
+	}
+	_, err := p.Describe()
+	return err == nil
+}
+
+func (p *PluginProvider) GetModels() []string {
+	desc, err := p.Describe()
+	if err != nil {
+		return nil
+	}
+	return desc.Models
+}
+
+func (p *PluginProvider) Execute(ctx context.Context, request CompletionRequest) (*CompletionResponse, error) {
+	start := time.Now()
+	resp := &CompletionResponse{Model: request.Model}
+
+	var content strings.Builder
+	err := p.complete(ctx, request, false, func(msg *pluginMessage) error {
+		switch msg.Type {
+		case pluginChunk:
+			content.WriteString(msg.Content)
+		case pluginUsage:
+			resp.Tokens = msg.tokens()
+		case pluginDone:
+			if msg.Model != "" {
+				resp.Model = msg.Model
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Content = content.String()
+	resp.Duration = time.Since(start)
+	return resp, nil
+}
+
+func (p *PluginProvider) ExecuteStream(ctx context.Context, request CompletionRequest) (<-chan StreamChunk, error) {
+	chunks := make(chan StreamChunk)
+
+	go func() {
+		defer close(chunks)
+		err := p.complete(ctx, request, true, func(msg *pluginMessage) error {
+			if msg.Type != pluginChunk || msg.Content == "" {
+				return nil
+			}
+			select {
+			case chunks <- StreamChunk{Content: msg.Content}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		select {
+		case chunks <- StreamChunk{Error: err, Done: true}:
+		case <-ctx.Done():
+		}
+	}()
+
+	return chunks, nil
+}
+
+// complete runs a completion request, requiring the plugin to finish with done
+func (p *PluginProvider) complete(ctx context.Context, request CompletionRequest, stream bool, handle func(*pluginMessage) error) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	req := pluginRequest{
+		Type:      pluginComplete,
+		System:    request.System,
+		Prompt:    request.Prompt,
+		Model:     request.Model,
+		MaxTokens: request.MaxTokens,
+		Stream:    stream,
+		Options:   request.Options,
+		Sampling:  request.Sampling,
+	}
+
+	var done bool
+	err := p.run(ctx, req, func(msg *pluginMessage) error {
+		if msg.Type == pluginDone {
+			done = true
+		}
+		return handle(msg)
+	})
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("plugin %s timed out after %v", p.name, p.timeout)
+		}
+		return err
+	}
+	if !done {
+		return fmt.Errorf("plugin %s exited without a done message", p.name)
+	}
+	return nil
+}
+
+// run starts the plugin, sends req and passes each message to handle.
+// An error message from the plugin ends the request with that error.
+func (p *PluginProvider) run(ctx context.Context, req pluginRequest, handle func(*pluginMessage) error) error {
+	req.Protocol = PluginProtocolVersion
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	cmd := p.command(ctx)
+	cmd.Stdin = bytes.NewReader(append(data, '\n'))
+	stderr := &limitedBuffer{max: maxStderrBytes}
+	cmd.Stderr = stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start plugin %s: %w", p.name, err)
+	}
+
+	var runErr error
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), maxPluginLine)
+	for runErr == nil && scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var msg pluginMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			runErr = fmt.Errorf("plugin %s: invalid output %q: %w", p.name, truncate(string(line), 200), err)
+			break
+		}
+		if msg.Type == pluginError {
+			runErr = fmt.Errorf("plugin %s: %s", p.name, msg.Message)
+			break
+		}
+		runErr = handle(&msg)
+	}
+	if runErr == nil {
+		runErr = scanner.Err()
+	}
+	if runErr != nil {
+		// Stop the plugin rather than wait for output nobody reads
+		cmd.Cancel()
+	}
+
+	if err := cmd.Wait(); err != nil && runErr == nil {
+		runErr = fmt.Errorf("plugin %s failed: %w\nstderr: %s", p.name, err, stderr.String())
+	}
+	return runErr
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
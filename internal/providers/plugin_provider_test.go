@@ -0,0 +1,148 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestPluginHelperProcess is not a real test: it is the plugin the tests
+// below run, by re-executing the test binary with FABRIC_PLUGIN_HELPER set.
+func TestPluginHelperProcess(t *testing.T) {
+	mode := os.Getenv("FABRIC_PLUGIN_HELPER")
+	if mode == "" {
+		return
+	}
+	defer os.Exit(0)
+
+	var req pluginRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, "bad request:", err)
+		os.Exit(2)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	if req.Type == pluginDescribe {
+		protocol := PluginProtocolVersion
+		if mode == "future" {
+			protocol = PluginProtocolVersion + 1
+		}
+		enc.Encode(map[string]any{
+			"type": "describe", "protocol": protocol, "name": "helper",
+			"models": []string{"small", "large"}, "capabilities": map[string]bool{"streaming": true, "usage": true},
+		})
+		return
+	}
+
+	switch mode {
+	case "error":
+		enc.Encode(map[string]string{"type": "error", "message": "model overloaded"})
+	case "truncated":
+		enc.Encode(map[string]string{"type": "chunk", "content": "partial"})
+	case "crash":
+		fmt.Fprintln(os.Stderr, "segfault in model loader")
+		os.Exit(3)
+	default:
+		for _, word := range strings.Fields(req.Prompt) {
+			enc.Encode(map[string]string{"type": "chunk", "content": word + " "})
+		}
+		enc.Encode(map[string]any{"type": "usage", "prompt_tokens": 3, "completion_tokens": len(strings.Fields(req.Prompt))})
+		enc.Encode(map[string]string{"type": "done", "model": req.Model + "@" + req.System})
+	}
+}
+
+func newHelperPlugin(t *testing.T, mode string) *PluginProvider {
+	t.Helper()
+	p, err := NewPluginProvider("helper", map[string]any{
+		"executable": os.Args[0],
+		"args":       []any{"-test.run=TestPluginHelperProcess", "--"},
+		"env":        map[string]any{"FABRIC_PLUGIN_HELPER": mode},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return p
+}
+
+func TestPluginProviderDescribe(t *testing.T) {
+	p := newHelperPlugin(t, "ok")
+	if !p.IsAvailable() {
+		t.Fatal("Expected the plugin to be available")
+	}
+	desc, err := p.Describe()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if desc.Name != "helper" || !desc.Capabilities.Streaming || !desc.Capabilities.Usage {
+		t.Errorf("Unexpected description: %+v", desc)
+	}
+	if models := p.GetModels(); len(models) != 2 || models[0] != "small" {
+		t.Errorf("Expected the plugin's models, got %v", models)
+	}
+
+	future := newHelperPlugin(t, "future")
+	if _, err := future.Describe(); err == nil || !strings.Contains(err.Error(), "protocol 2") {
+		t.Errorf("Expected a protocol version error, got %v", err)
+	}
+	if future.IsAvailable() {
+		t.Error("Expected a plugin with an unsupported protocol to be unavailable")
+	}
+}
+
+func TestPluginProviderExecute(t *testing.T) {
+	p := newHelperPlugin(t, "ok")
+	resp, err := p.Execute(context.Background(), CompletionRequest{System: "sys", Prompt: "hello plugin world", Model: "small"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Content != "hello plugin world " || resp.Tokens != 6 || resp.Model != "small@sys" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+
+	for mode, want := range map[string]string{
+		"error":     "model overloaded",
+		"truncated": "without a done message",
+		"crash":     "segfault in model loader",
+	} {
+		_, err := newHelperPlugin(t, mode).Execute(context.Background(), CompletionRequest{Prompt: "hi"})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %s to fail with %q, got %v", mode, want, err)
+		}
+	}
+}
+
+func TestPluginProviderExecuteStream(t *testing.T) {
+	p := newHelperPlugin(t, "ok")
+	chunks, err := p.ExecuteStream(context.Background(), CompletionRequest{Prompt: "one two three"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var parts []string
+	var done bool
+	for chunk := range chunks {
+		if chunk.Error != nil {
+			t.Fatalf("Unexpected stream error: %v", chunk.Error)
+		}
+		if chunk.Done {
+			done = true
+			continue
+		}
+		parts = append(parts, chunk.Content)
+	}
+	if len(parts) != 3 || parts[2] != "three " || !done {
+		t.Errorf("Expected three chunks and done, got %q, done %v", parts, done)
+	}
+
+	chunks, _ = newHelperPlugin(t, "error").ExecuteStream(context.Background(), CompletionRequest{Prompt: "hi"})
+	var last StreamChunk
+	for chunk := range chunks {
+		last = chunk
+	}
+	if !last.Done || last.Error == nil {
+		t.Errorf("Expected the stream to end with the plugin error, got %+v", last)
+	}
+}
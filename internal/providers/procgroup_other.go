@@ -0,0 +1,9 @@
+//go:build !unix
+
+package providers
+
+import "os/exec"
+
+// setProcessGroup is a no-op without Unix process groups; cancelling
+// kills the process itself
+func setProcessGroup(cmd *exec.Cmd) {}
@@ -2,6 +2,7 @@ package providers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 )
@@ -14,6 +15,71 @@ type CompletionRequest struct {
 	MaxTokens int            `json:"max_tokens,omitempty"`
 	Stream    bool           `json:"stream,omitempty"`
 	Options   map[string]any `json:"options,omitempty"`
+	Sampling
+}
+
+// Sampling holds generation parameters. Nil and empty fields leave the
+// provider's default; providers ignore the ones their API lacks.
+type Sampling struct {
+	Temperature      *float64 `json:"temperature,omitempty" yaml:"temperature,omitempty"`
+	TopP             *float64 `json:"top_p,omitempty" yaml:"top_p,omitempty"`
+	TopK             *int     `json:"top_k,omitempty" yaml:"top_k,omitempty"`
+	Stop             []string `json:"stop,omitempty" yaml:"stop,omitempty"`
+	Seed             *int     `json:"seed,omitempty" yaml:"seed,omitempty"`
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty" yaml:"presence_penalty,omitempty"`
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty" yaml:"frequency_penalty,omitempty"`
+}
+
+// Merge returns s with the fields set in override replaced
+func (s Sampling) Merge(override Sampling) Sampling {
+	if override.Temperature != nil {
+		s.Temperature = override.Temperature
+	}
+	if override.TopP != nil {
+		s.TopP = override.TopP
+	}
+	if override.TopK != nil {
+		s.TopK = override.TopK
+	}
+	if override.Stop != nil {
+		s.Stop = override.Stop
+	}
+	if override.Seed != nil {
+		s.Seed = override.Seed
+	}
+	if override.PresencePenalty != nil {
+		s.PresencePenalty = override.PresencePenalty
+	}
+	if override.FrequencyPenalty != nil {
+		s.FrequencyPenalty = override.FrequencyPenalty
+	}
+	return s
+}
+
+// IsZero reports whether no parameter is set
+func (s Sampling) IsZero() bool {
+	return s.Temperature == nil && s.TopP == nil && s.TopK == nil && len(s.Stop) == 0 &&
+		s.Seed == nil && s.PresencePenalty == nil && s.FrequencyPenalty == nil
+}
+
+// Validate checks the parameters against the ranges every API accepts
+func (s Sampling) Validate() error {
+	if s.Temperature != nil && (*s.Temperature < 0 || *s.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2, got %v", *s.Temperature)
+	}
+	if s.TopP != nil && (*s.TopP < 0 || *s.TopP > 1) {
+		return fmt.Errorf("top_p must be between 0 and 1, got %v", *s.TopP)
+	}
+	if s.TopK != nil && *s.TopK < 0 {
+		return fmt.Errorf("top_k must not be negative, got %d", *s.TopK)
+	}
+	if s.PresencePenalty != nil && (*s.PresencePenalty < -2 || *s.PresencePenalty > 2) {
+		return fmt.Errorf("presence_penalty must be between -2 and 2, got %v", *s.PresencePenalty)
+	}
+	if s.FrequencyPenalty != nil && (*s.FrequencyPenalty < -2 || *s.FrequencyPenalty > 2) {
+		return fmt.Errorf("frequency_penalty must be between -2 and 2, got %v", *s.FrequencyPenalty)
+	}
+	return nil
 }
 
 // CompletionResponse represents a response from an AI provider
@@ -53,7 +119,7 @@ type Provider interface {
 // ProviderConfig represents configuration for a provider
 type ProviderConfig struct {
 	Name   string         `yaml:"name"`
-	Type   string         `yaml:"type"` // http, ollama, executable
+	Type   string         `yaml:"type"` // http, azure, anthropic, ollama, executable, plugin
 	Config map[string]any `yaml:"config"`
 }
 
@@ -93,19 +159,26 @@ func ListAvailableProviders() []Provider {
 	return available
 }
 
+// ErrUnknownProviderType is returned by NewProvider for a type no provider implements
+var ErrUnknownProviderType = errors.New("unknown provider type")
+
 // NewProvider creates a provider from configuration
 func NewProvider(config ProviderConfig) (Provider, error) {
 	switch config.Type {
 	case "http", "openai":
 		return NewHTTPProvider(config.Name, config.Config)
+	case "azure":
+		return NewAzureProvider(config.Name, config.Config)
 	case "ollama":
 		return NewOllamaProvider(config.Name, config.Config)
 	case "anthropic":
 		return NewAnthropicProvider(config.Name, config.Config)
 	case "executable":
 		return NewExecutableProvider(config.Name, config.Config)
+	case "plugin":
+		return NewPluginProvider(config.Name, config.Config)
 	default:
-		return nil, fmt.Errorf("unknown provider type: %s", config.Type)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProviderType, config.Type)
 	}
 }
 
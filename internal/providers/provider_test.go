@@ -2,8 +2,13 @@ package providers
 
 import (
 	"context"
+	"encoding/json"
+	"encoding/pem"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -591,3 +596,220 @@ func TestNewExecutableProviderMissingExecutable(t *testing.T) {
 		t.Error("Expected provider to be nil on error")
 	}
 }
+
+func TestSampling(t *testing.T) {
+	low, high, k := 0.2, 0.8, 40
+	base := Sampling{Temperature: &low, TopK: &k, Stop: []string{"END"}}
+	merged := base.Merge(Sampling{Temperature: &high})
+	if *merged.Temperature != 0.8 || *merged.TopK != 40 || merged.Stop[0] != "END" {
+		t.Errorf("Expected only the temperature overridden, got %+v", merged)
+	}
+	if *base.Temperature != 0.2 {
+		t.Error("Expected Merge to leave the receiver unchanged")
+	}
+
+	if !(Sampling{}).IsZero() || merged.IsZero() {
+		t.Error("Expected IsZero only for unset parameters")
+	}
+
+	bad := 1.5
+	for _, s := range []Sampling{{Temperature: &bad, TopP: &bad}, {PresencePenalty: new(float64)}} {
+		err := s.Validate()
+		if s.TopP != nil && err == nil {
+			t.Errorf("Expected an error for top_p %v", *s.TopP)
+		}
+		if s.TopP == nil && err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", s, err)
+		}
+	}
+}
+
+// captureRequest serves reply and decodes each request body into body
+func captureRequest(t *testing.T, body *map[string]any, reply string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		w.Write([]byte(reply))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPProviderSampling(t *testing.T) {
+	var body map[string]any
+	server := captureRequest(t, &body, `{"model": "m", "choices": [{"message": {"content": "ok"}}]}`)
+
+	p, _ := NewHTTPProvider("test", map[string]any{"endpoint": server.URL, "api_key": "k"})
+	zero, seed, penalty := 0.0, 7, 0.5
+	_, err := p.Execute(context.Background(), CompletionRequest{
+		Prompt:   "hi",
+		Sampling: Sampling{Temperature: &zero, Seed: &seed, FrequencyPenalty: &penalty, Stop: []string{"END"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if body["temperature"] != 0.0 || body["seed"] != 7.0 || body["frequency_penalty"] != 0.5 || body["stop"].([]any)[0] != "END" {
+		t.Errorf("Expected the sampling parameters, got %v", body)
+	}
+	if _, ok := body["top_p"]; ok {
+		t.Errorf("Expected unset parameters to be omitted, got %v", body)
+	}
+}
+
+func TestOllamaProviderSampling(t *testing.T) {
+	var body map[string]any
+	server := captureRequest(t, &body, `{"model": "m", "message": {"content": "ok"}, "done": true}`)
+
+	p, _ := NewOllamaProvider("test", map[string]any{"endpoint": server.URL})
+	k := 20
+	if _, err := p.Execute(context.Background(), CompletionRequest{Prompt: "hi", Sampling: Sampling{TopK: &k}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if options, ok := body["options"].(map[string]any); !ok || options["top_k"] != 20.0 {
+		t.Errorf("Expected top_k in options, got %v", body)
+	}
+
+	body = nil
+	if _, err := p.Execute(context.Background(), CompletionRequest{Prompt: "hi"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := body["options"]; ok {
+		t.Errorf("Expected no options without sampling parameters, got %v", body)
+	}
+}
+
+func TestHTTPProviderAuth(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Write([]byte(`{"model": "m", "choices": [{"message": {"content": "ok"}}]}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("GATEWAY_TEAM", "search")
+	tests := []struct {
+		name   string
+		config map[string]any
+		want   map[string]string
+	}{
+		{"bearer", map[string]any{"api_key": "k"}, map[string]string{"Authorization": "Bearer k"}},
+		{"header", map[string]any{"api_key": "k", "auth": "header", "auth_header": "X-Api-Key"}, map[string]string{"X-Api-Key": "k", "Authorization": ""}},
+		{"none", map[string]any{"auth": "none", "headers": map[string]any{"X-Team": "$GATEWAY_TEAM"}}, map[string]string{"Authorization": "", "X-Team": "search"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["endpoint"] = server.URL
+			p, err := NewHTTPProvider("test", tt.config)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !p.IsAvailable() {
+				t.Fatal("Expected the provider to be available")
+			}
+			if _, err := p.Execute(context.Background(), CompletionRequest{Prompt: "hi"}); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			for k, v := range tt.want {
+				if got.Get(k) != v {
+					t.Errorf("Expected %s %q, got %q", k, v, got.Get(k))
+				}
+			}
+		})
+	}
+
+	if _, err := NewHTTPProvider("test", map[string]any{"auth": "basic"}); err == nil {
+		t.Error("Expected an error for an unknown auth mode")
+	}
+}
+
+func TestAzureProvider(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(`{"model": "gpt-4o", "choices": [{"message": {"content": "ok"}}]}`))
+	}))
+	defer server.Close()
+
+	p, err := NewAzureProvider("azure", map[string]any{
+		"base_url":    server.URL + "/",
+		"api_key":     "k",
+		"model":       "gpt-4o",
+		"deployments": map[string]any{"gpt-4o": "prod-4o", "gpt-4o-mini": "cheap"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := p.Execute(context.Background(), CompletionRequest{Prompt: "hi"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.URL.Path != "/openai/deployments/prod-4o/chat/completions" || got.URL.Query().Get("api-version") != azureAPIVersion {
+		t.Errorf("Expected the deployment URL, got %s", got.URL)
+	}
+	if got.Header.Get("api-key") != "k" || got.Header.Get("Authorization") != "" {
+		t.Errorf("Expected the api-key header, got %v", got.Header)
+	}
+	if models := p.GetModels(); strings.Join(models, ",") != "gpt-4o,gpt-4o-mini" {
+		t.Errorf("Expected the deployments as models, got %v", models)
+	}
+
+	p, _ = NewHTTPProvider("gateway", map[string]any{
+		"endpoint":    server.URL + "/{{.Deployment}}/chat?api-version=pinned",
+		"api_version": "ignored",
+		"api_key":     "k",
+	})
+	p.Execute(context.Background(), CompletionRequest{Prompt: "hi", Model: "a b"})
+	if got.URL.Path != "/a b/chat" || got.URL.Query().Get("api-version") != "pinned" {
+		t.Errorf("Expected the endpoint's own api-version to win, got %s", got.URL)
+	}
+
+	t.Setenv("AZURE_OPENAI_ENDPOINT", "")
+	if _, err := NewAzureProvider("azure", map[string]any{"api_key": "k"}); err == nil {
+		t.Error("Expected an error without base_url")
+	}
+}
+
+func TestHTTPProviderProxyAndCA(t *testing.T) {
+	reply := `{"model": "m", "choices": [{"message": {"content": "ok"}}]}`
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(reply))
+	}))
+	defer server.Close()
+
+	p, _ := NewHTTPProvider("test", map[string]any{"endpoint": server.URL, "api_key": "k"})
+	if _, err := p.Execute(context.Background(), CompletionRequest{Prompt: "hi"}); err == nil {
+		t.Fatal("Expected an untrusted certificate to fail")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, cert, 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewHTTPProvider("test", map[string]any{"endpoint": server.URL, "api_key": "k", "ca_file": caFile})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := p.Execute(context.Background(), CompletionRequest{Prompt: "hi"}); err != nil {
+		t.Errorf("Expected ca_file to trust the server, got %v", err)
+	}
+
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.Write([]byte(reply))
+	}))
+	defer proxy.Close()
+	p, _ = NewHTTPProvider("test", map[string]any{"endpoint": "http://llm.internal/v1/chat/completions", "api_key": "k", "proxy": proxy.URL})
+	if _, err := p.Execute(context.Background(), CompletionRequest{Prompt: "hi"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if proxied != "http://llm.internal/v1/chat/completions" {
+		t.Errorf("Expected the request to go through the proxy, got %q", proxied)
+	}
+
+	if _, err := NewHTTPProvider("test", map[string]any{"ca_file": filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("Expected an error for a missing ca_file")
+	}
+}
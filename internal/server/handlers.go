@@ -0,0 +1,272 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/rice0649/fabric-lite/internal/executor"
+	"github.com/rice0649/fabric-lite/internal/providers"
+)
+
+// patternView describes a pattern in /api/patterns
+type patternView struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Variables   []string `json:"variables,omitempty"`
+}
+
+// executeRequest is the body of POST /api/execute
+type executeRequest struct {
+	Pattern   string            `json:"pattern"`
+	Input     string            `json:"input"`
+	Variables map[string]string `json:"variables,omitempty"`
+	Provider  string            `json:"provider,omitempty"`
+	Model     string            `json:"model,omitempty"`
+	Stream    bool              `json:"stream,omitempty"`
+
+	// Sampling parameters override the pattern's defaults
+	providers.Sampling
+}
+
+// executeResponse is the result of a non-streaming execution
+type executeResponse struct {
+	Pattern    string `json:"pattern"`
+	Provider   string `json:"provider"`
+	Model      string `json:"model"`
+	Content    string `json:"content"`
+	Tokens     int    `json:"tokens"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// providerView describes a provider in /api/providers
+type providerView struct {
+	Name      string   `json:"name"`
+	Available bool     `json:"available"`
+	Default   bool     `json:"default"`
+	Models    []string `json:"models"`
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handlePatterns(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	patterns, err := s.newExecutor().ListPatterns()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	views := make([]patternView, 0, len(patterns))
+	for _, p := range patterns {
+		views = append(views, patternView{Name: p.Name, Description: p.Description, Variables: p.Variables})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"patterns": views})
+}
+
+func (s *Server) handleProviders(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	views := []providerView{}
+	for _, name := range s.providerNames() {
+		p, err := s.providers.Get(name)
+		if err != nil {
+			continue
+		}
+		models := p.GetModels()
+		if models == nil {
+			models = []string{}
+		}
+		views = append(views, providerView{
+			Name:      name,
+			Available: p.IsAvailable(),
+			Default:   name == s.opts.DefaultProvider,
+			Models:    models,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"providers": views})
+}
+
+func (s *Server) handleExecute(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req executeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if err := validatePatternName(req.Pattern); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.Sampling.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Provider == "" {
+		req.Provider = s.opts.DefaultProvider
+	}
+	provider, err := s.providers.Get(req.Provider)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ex := s.newExecutor()
+	ex.LoadProviderDirect(req.Provider, provider)
+	ex.SetVariables(req.Variables)
+	ex.SetSampling(req.Sampling)
+
+	if req.Stream || strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		s.streamExecution(w, r, ex, req)
+		return
+	}
+
+	resp, err := ex.ExecuteWithOptions(r.Context(), req.Pattern, req.Input, req.Provider, req.Model, false)
+	if err != nil {
+		writeError(w, executionStatus(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, executeResponse{
+		Pattern:    req.Pattern,
+		Provider:   req.Provider,
+		Model:      resp.Model,
+		Content:    resp.Content,
+		Tokens:     resp.Tokens,
+		DurationMs: resp.Duration.Milliseconds(),
+	})
+}
+
+// streamExecution sends the pattern output as server-sent events: "chunk"
+// events carry content, followed by "done" or "error"
+func (s *Server) streamExecution(w http.ResponseWriter, r *http.Request, ex *executor.PatternExecutor, req executeRequest) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	chunks, err := ex.ExecuteStream(r.Context(), req.Pattern, req.Input, req.Provider, req.Model)
+	if err != nil {
+		writeError(w, executionStatus(err), err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case chunk, ok := <-chunks:
+			if !ok {
+				writeEvent(w, "done", map[string]interface{}{})
+				flusher.Flush()
+				return
+			}
+			if chunk.Error != nil {
+				writeEvent(w, "error", map[string]string{"error": chunk.Error.Error()})
+				flusher.Flush()
+				return
+			}
+			if chunk.Content != "" {
+				writeEvent(w, "chunk", map[string]string{"content": chunk.Content})
+				flusher.Flush()
+			}
+			if chunk.Done {
+				writeEvent(w, "done", map[string]interface{}{})
+				flusher.Flush()
+				return
+			}
+		}
+	}
+}
+
+// newExecutor creates a pattern executor for one request
+func (s *Server) newExecutor() *executor.PatternExecutor {
+	ex := executor.NewPatternExecutor()
+	if s.opts.PatternsDir != "" {
+		ex.SetPatternsDir(s.opts.PatternsDir)
+	}
+	return ex
+}
+
+// validatePatternName rejects names that would escape the patterns directory
+func validatePatternName(name string) error {
+	if name == "" {
+		return fmt.Errorf("pattern is required")
+	}
+	if strings.HasPrefix(name, "/") || strings.Contains(name, "\\") || path.Clean(name) != name || strings.HasPrefix(name, "..") {
+		return fmt.Errorf("invalid pattern name: %s", name)
+	}
+	return nil
+}
+
+// executionStatus maps execution errors to HTTP status codes
+func executionStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "failed to load pattern"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "provider not available"):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeRouteError(w, r, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// writeRouteError writes an error in the format of the route: OpenAI style
+// under /v1, {"error": message} elsewhere
+func writeRouteError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	if strings.HasPrefix(r.URL.Path, "/v1/") {
+		writeOpenAIError(w, status, message)
+		return
+	}
+	writeError(w, status, message)
+}
+
+func writeEvent(w http.ResponseWriter, event string, v interface{}) {
+	data, _ := json.Marshal(v)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
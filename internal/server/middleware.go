@@ -0,0 +1,91 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// authenticate requires the configured bearer token, which OpenAI clients
+// send as their API key
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.opts.Token == "" {
+		return next
+	}
+	want := []byte("Bearer " + s.opts.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="fabric-lite"`)
+			writeRouteError(w, r, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limitBody rejects request bodies larger than MaxBodyBytes
+func (s *Server) limitBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > s.opts.MaxBodyBytes {
+			writeRouteError(w, r, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, s.opts.MaxBodyBytes)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limit bounds the requests a client address has in flight
+func (s *Server) limit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client := clientAddr(r)
+		if !s.limiter.acquire(client) {
+			w.Header().Set("Retry-After", "1")
+			writeRouteError(w, r, http.StatusTooManyRequests, "too many concurrent requests")
+			return
+		}
+		defer s.limiter.release(client)
+		next(w, r)
+	}
+}
+
+// clientAddr identifies the client by its IP address
+func clientAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return strings.TrimSpace(host)
+}
+
+// clientLimiter counts in-flight requests per client
+type clientLimiter struct {
+	max      int
+	mu       sync.Mutex
+	inFlight map[string]int
+}
+
+func newClientLimiter(max int) *clientLimiter {
+	return &clientLimiter{max: max, inFlight: make(map[string]int)}
+}
+
+func (l *clientLimiter) acquire(client string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight[client] >= l.max {
+		return false
+	}
+	l.inFlight[client]++
+	return true
+}
+
+func (l *clientLimiter) release(client string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight[client]--; l.inFlight[client] <= 0 {
+		delete(l.inFlight, client)
+	}
+}
@@ -0,0 +1,406 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rice0649/fabric-lite/internal/providers"
+)
+
+// chatRequest is the subset of the OpenAI chat completions request that
+// providers can honour
+type chatRequest struct {
+	Model               string          `json:"model"`
+	Messages            []chatMessage   `json:"messages"`
+	Stream              bool            `json:"stream,omitempty"`
+	MaxTokens           int             `json:"max_tokens,omitempty"`
+	MaxCompletionTokens int             `json:"max_completion_tokens,omitempty"`
+	Temperature         *float64        `json:"temperature,omitempty"`
+	TopP                *float64        `json:"top_p,omitempty"`
+	TopK                *int            `json:"top_k,omitempty"`
+	Stop                json.RawMessage `json:"stop,omitempty"`
+	Seed                *int            `json:"seed,omitempty"`
+	PresencePenalty     *float64        `json:"presence_penalty,omitempty"`
+	FrequencyPenalty    *float64        `json:"frequency_penalty,omitempty"`
+}
+
+// chatMessage content is either a string or a list of content parts
+type chatMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+type chatChoice struct {
+	Index        int               `json:"index"`
+	Message      *chatReplyMessage `json:"message,omitempty"`
+	Delta        *chatReplyMessage `json:"delta,omitempty"`
+	FinishReason *string           `json:"finish_reason"`
+}
+
+type chatReplyMessage struct {
+	Role    string `json:"role,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// chatResponse is a chat.completion or, when streaming, a chat.completion.chunk
+type chatResponse struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"`
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []chatChoice `json:"choices"`
+	Usage   *chatUsage   `json:"usage,omitempty"`
+}
+
+type modelView struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
+func (s *Server) handleModels(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	models := []modelView{}
+	aliases := make([]string, 0, len(s.opts.ModelMap))
+	for alias := range s.opts.ModelMap {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		models = append(models, modelView{ID: alias, Object: "model", OwnedBy: parseTarget(s.opts.ModelMap[alias]).Provider})
+	}
+	for _, name := range s.providerNames() {
+		p, err := s.provider(name)
+		if err != nil {
+			continue
+		}
+		for _, m := range p.GetModels() {
+			models = append(models, modelView{ID: name + "/" + m, Object: "model", OwnedBy: name})
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"object": "list", "data": models})
+}
+
+func (s *Server) handleUsage(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"object": "list", "data": s.usage.snapshot()})
+}
+
+func (s *Server) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req chatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeOpenAIError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		writeOpenAIError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	completion, err := req.completionRequest()
+	if err != nil {
+		writeOpenAIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Stream {
+		s.streamChat(w, r, req, completion)
+		return
+	}
+
+	var failures []string
+	status := http.StatusBadGateway
+	for i, rt := range s.routes(req.Model) {
+		p, err := s.provider(rt.Provider)
+		if err == nil {
+			completion.Model = rt.Model
+			var resp *providers.CompletionResponse
+			if resp, err = p.Execute(r.Context(), completion); err == nil {
+				s.usage.record(rt, resp.Tokens, i > 0)
+				w.Header().Set("X-Fabric-Lite-Route", rt.String())
+				writeJSON(w, http.StatusOK, chatResponse{
+					ID:      newCompletionID(),
+					Object:  "chat.completion",
+					Created: time.Now().Unix(),
+					Model:   responseModel(req.Model, resp.Model, rt),
+					Choices: []chatChoice{{
+						Message:      &chatReplyMessage{Role: "assistant", Content: resp.Content},
+						FinishReason: finishReason("stop"),
+					}},
+					Usage: &chatUsage{CompletionTokens: resp.Tokens, TotalTokens: resp.Tokens},
+				})
+				return
+			}
+		}
+		s.usage.recordError(rt)
+		failures = append(failures, fmt.Sprintf("%s: %v", rt, err))
+		status = routeStatus(err)
+		if r.Context().Err() != nil {
+			break
+		}
+	}
+	writeOpenAIError(w, status, "all providers failed: "+strings.Join(failures, "; "))
+}
+
+// streamChat streams the completion as chat.completion.chunk events. A
+// route that fails before producing output falls through to the next one;
+// once output has been sent, errors are reported in the stream.
+func (s *Server) streamChat(w http.ResponseWriter, r *http.Request, req chatRequest, completion providers.CompletionRequest) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeOpenAIError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	var failures []string
+	status := http.StatusBadGateway
+	for i, rt := range s.routes(req.Model) {
+		chunks, first, err := s.openStream(r, rt, completion)
+		if err != nil {
+			s.usage.recordError(rt)
+			failures = append(failures, fmt.Sprintf("%s: %v", rt, err))
+			status = routeStatus(err)
+			if r.Context().Err() != nil {
+				break
+			}
+			continue
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Fabric-Lite-Route", rt.String())
+		w.WriteHeader(http.StatusOK)
+
+		base := chatResponse{
+			ID:      newCompletionID(),
+			Object:  "chat.completion.chunk",
+			Created: time.Now().Unix(),
+			Model:   responseModel(req.Model, "", rt),
+		}
+		send := func(delta *chatReplyMessage, finish *string) {
+			chunk := base
+			chunk.Choices = []chatChoice{{Delta: delta, FinishReason: finish}}
+			data, _ := json.Marshal(chunk)
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+
+		send(&chatReplyMessage{Role: "assistant"}, nil)
+		chunk, open := first, true
+		for {
+			if !open || chunk.Done {
+				send(&chatReplyMessage{}, finishReason("stop"))
+				fmt.Fprint(w, "data: [DONE]\n\n")
+				flusher.Flush()
+				s.usage.record(rt, 0, i > 0)
+				return
+			}
+			if chunk.Error != nil {
+				s.usage.recordError(rt)
+				data, _ := json.Marshal(openAIError(chunk.Error.Error()))
+				fmt.Fprintf(w, "data: %s\n\n", data)
+				flusher.Flush()
+				return
+			}
+			if chunk.Content != "" {
+				send(&chatReplyMessage{Content: chunk.Content}, nil)
+			}
+			select {
+			case <-r.Context().Done():
+				return
+			case chunk, open = <-chunks:
+			}
+		}
+	}
+	writeOpenAIError(w, status, "all providers failed: "+strings.Join(failures, "; "))
+}
+
+// openStream starts a stream on rt and waits for its first chunk so that a
+// provider failing up front can be skipped
+func (s *Server) openStream(r *http.Request, rt route, completion providers.CompletionRequest) (<-chan providers.StreamChunk, providers.StreamChunk, error) {
+	p, err := s.provider(rt.Provider)
+	if err != nil {
+		return nil, providers.StreamChunk{}, err
+	}
+	completion.Model = rt.Model
+	completion.Stream = true
+	chunks, err := p.ExecuteStream(r.Context(), completion)
+	if err != nil {
+		return nil, providers.StreamChunk{}, err
+	}
+	select {
+	case <-r.Context().Done():
+		return nil, providers.StreamChunk{}, r.Context().Err()
+	case first, ok := <-chunks:
+		if !ok {
+			return chunks, providers.StreamChunk{Done: true}, nil
+		}
+		if first.Error != nil {
+			return nil, providers.StreamChunk{}, first.Error
+		}
+		return chunks, first, nil
+	}
+}
+
+// completionRequest translates the chat request for a provider. System and
+// developer messages become the system prompt; a single remaining message
+// is the prompt, and longer conversations are flattened to a transcript.
+func (req chatRequest) completionRequest() (providers.CompletionRequest, error) {
+	var system, turns []string
+	var last string
+	for _, m := range req.Messages {
+		text, err := messageText(m.Content)
+		if err != nil {
+			return providers.CompletionRequest{}, err
+		}
+		switch m.Role {
+		case "system", "developer":
+			system = append(system, text)
+		case "user", "assistant", "tool":
+			turns = append(turns, roleLabel(m.Role)+": "+text)
+			last = text
+		default:
+			return providers.CompletionRequest{}, fmt.Errorf("unsupported message role: %s", m.Role)
+		}
+	}
+	if len(turns) == 0 {
+		return providers.CompletionRequest{}, fmt.Errorf("messages must include a user message")
+	}
+
+	prompt := last
+	if len(turns) > 1 {
+		prompt = strings.Join(turns, "\n\n")
+	}
+
+	completion := providers.CompletionRequest{
+		System:    strings.Join(system, "\n\n"),
+		Prompt:    prompt,
+		MaxTokens: req.MaxTokens,
+	}
+	if req.MaxCompletionTokens > 0 {
+		completion.MaxTokens = req.MaxCompletionTokens
+	}
+
+	completion.Sampling = providers.Sampling{
+		Temperature:      req.Temperature,
+		TopP:             req.TopP,
+		TopK:             req.TopK,
+		Seed:             req.Seed,
+		PresencePenalty:  req.PresencePenalty,
+		FrequencyPenalty: req.FrequencyPenalty,
+	}
+	if len(req.Stop) > 0 && string(req.Stop) != "null" {
+		var stop []string
+		if err := json.Unmarshal(req.Stop, &stop); err != nil {
+			var single string
+			if err := json.Unmarshal(req.Stop, &single); err != nil {
+				return providers.CompletionRequest{}, fmt.Errorf("stop must be a string or a list of strings")
+			}
+			stop = []string{single}
+		}
+		completion.Stop = stop
+	}
+	if err := completion.Sampling.Validate(); err != nil {
+		return providers.CompletionRequest{}, err
+	}
+	return completion, nil
+}
+
+// messageText returns the text of a string or content-part message
+func messageText(content json.RawMessage) (string, error) {
+	if len(content) == 0 || string(content) == "null" {
+		return "", nil
+	}
+	var text string
+	if err := json.Unmarshal(content, &text); err == nil {
+		return text, nil
+	}
+
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(content, &parts); err != nil {
+		return "", fmt.Errorf("message content must be a string or a list of content parts")
+	}
+	texts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part.Type != "text" {
+			return "", fmt.Errorf("unsupported content part type: %s", part.Type)
+		}
+		texts = append(texts, part.Text)
+	}
+	return strings.Join(texts, "\n"), nil
+}
+
+func roleLabel(role string) string {
+	return strings.ToUpper(role[:1]) + role[1:]
+}
+
+// responseModel reports the model the client asked for, falling back to
+// what the provider used
+func responseModel(requested, served string, rt route) string {
+	switch {
+	case requested != "":
+		return requested
+	case served != "":
+		return served
+	default:
+		return rt.String()
+	}
+}
+
+// routeStatus maps a route failure to an HTTP status code
+func routeStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "provider not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "provider not available"):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+func finishReason(reason string) *string {
+	return &reason
+}
+
+func newCompletionID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return "chatcmpl-" + hex.EncodeToString(b)
+}
+
+func openAIError(message string) map[string]interface{} {
+	return map[string]interface{}{
+		"error": map[string]string{"message": message, "type": "fabric_lite_error"},
+	}
+}
+
+func writeOpenAIError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, openAIError(message))
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newGatewayServer serves the /v1 routes with a primary "mock" provider, a
+// "backup" provider and an unavailable "offline" one
+func newGatewayServer(t *testing.T, opts Options, primary, backup *mockProvider) *httptest.Server {
+	t.Helper()
+	opts.DefaultProvider = "mock"
+	registry := mockRegistry{"mock": primary, "backup": backup, "offline": &mockProvider{name: "offline", models: []string{"hidden"}}}
+	ts := httptest.NewServer(New(opts, registry).Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestResolveModel(t *testing.T) {
+	registry := mockRegistry{
+		"mock":      &mockProvider{name: "mock", available: true},
+		"anthropic": &mockProvider{name: "anthropic", available: true, models: []string{"claude-3-haiku"}},
+	}
+	s := New(Options{DefaultProvider: "mock", ModelMap: map[string]string{"gpt-4o": "anthropic/claude-3-haiku", "local": "mock"}}, registry)
+
+	tests := map[string]route{
+		"gpt-4o":                  {Provider: "anthropic", Model: "claude-3-haiku"},
+		"local":                   {Provider: "mock"},
+		"anthropic/claude-3-opus": {Provider: "anthropic", Model: "claude-3-opus"},
+		"claude-3-haiku":          {Provider: "anthropic", Model: "claude-3-haiku"},
+		"meta-llama/llama-3":      {Provider: "mock", Model: "meta-llama/llama-3"},
+		"":                        {Provider: "mock"},
+		"something-else-entirely": {Provider: "mock", Model: "something-else-entirely"},
+	}
+	for model, want := range tests {
+		if got := s.resolveModel(model); got != want {
+			t.Errorf("Expected %q to route to %v, got %v", model, want, got)
+		}
+	}
+
+	s.opts.Fallback = []string{"anthropic/claude-3-haiku", "mock"}
+	if routes := s.routes("local"); len(routes) != 2 || routes[1].String() != "anthropic/claude-3-haiku" {
+		t.Errorf("Expected the fallback after the primary, without repeating it, got %v", routes)
+	}
+}
+
+func TestChatCompletionRequest(t *testing.T) {
+	var req chatRequest
+	body := `{
+		"model": "m",
+		"messages": [
+			{"role": "system", "content": "Be brief."},
+			{"role": "user", "content": [{"type": "text", "text": "Hi"}, {"type": "text", "text": "there"}]}
+		],
+		"max_completion_tokens": 50,
+		"temperature": 0.2,
+		"stop": "END"
+	}`
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatal(err)
+	}
+	completion, err := req.completionRequest()
+	if err != nil {
+		t.Fatalf("Expected a valid request, got %v", err)
+	}
+	if completion.System != "Be brief." || completion.Prompt != "Hi\nthere" || completion.MaxTokens != 50 {
+		t.Errorf("Unexpected translation: %+v", completion)
+	}
+	if completion.Temperature == nil || *completion.Temperature != 0.2 || len(completion.Stop) != 1 || completion.Stop[0] != "END" {
+		t.Errorf("Expected temperature and stop as sampling parameters, got %+v", completion.Sampling)
+	}
+
+	req = chatRequest{Messages: []chatMessage{
+		{Role: "user", Content: json.RawMessage(`"Hi"`)},
+		{Role: "assistant", Content: json.RawMessage(`"Hello"`)},
+		{Role: "user", Content: json.RawMessage(`"Bye"`)},
+	}}
+	completion, _ = req.completionRequest()
+	if completion.Prompt != "User: Hi\n\nAssistant: Hello\n\nUser: Bye" {
+		t.Errorf("Expected a transcript for multi-turn chats, got %q", completion.Prompt)
+	}
+
+	for _, messages := range []string{
+		`[{"role": "system", "content": "only system"}]`,
+		`[{"role": "user", "content": [{"type": "image_url"}]}]`,
+		`[{"role": "wizard", "content": "hi"}]`,
+	} {
+		req = chatRequest{}
+		json.Unmarshal([]byte(messages), &req.Messages)
+		if _, err := req.completionRequest(); err == nil {
+			t.Errorf("Expected an error for %s", messages)
+		}
+	}
+}
+
+func TestChatCompletions(t *testing.T) {
+	primary := &mockProvider{name: "mock", available: true, models: []string{"m1"}}
+	ts := newGatewayServer(t, Options{Token: "secret"}, primary, &mockProvider{name: "backup", available: true})
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/chat/completions", "", `{"model": "m1", "messages": [{"role": "user", "content": "hi"}]}`)
+	var failure struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	decodeBody(t, resp, &failure)
+	if resp.StatusCode != http.StatusUnauthorized || failure.Error.Message == "" {
+		t.Errorf("Expected an OpenAI-style 401, got %d %+v", resp.StatusCode, failure)
+	}
+
+	resp = doRequest(t, http.MethodPost, ts.URL+"/v1/chat/completions", "secret", `{"model": "m1", "messages": [{"role": "user", "content": "hi"}]}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	var result chatResponse
+	decodeBody(t, resp, &result)
+	if result.Object != "chat.completion" || result.Model != "m1" || len(result.Choices) != 1 {
+		t.Fatalf("Unexpected response: %+v", result)
+	}
+	if msg := result.Choices[0].Message; msg.Role != "assistant" || msg.Content != "echo: hi" {
+		t.Errorf("Expected the echoed reply, got %+v", msg)
+	}
+	if result.Usage == nil || result.Usage.TotalTokens != 3 || primary.last.Model != "m1" {
+		t.Errorf("Expected usage and the model passed through, got %+v, model %q", result.Usage, primary.last.Model)
+	}
+}
+
+func TestChatCompletionsFallback(t *testing.T) {
+	primary := &mockProvider{name: "mock", available: true, fail: errors.New("rate limited")}
+	backup := &mockProvider{name: "backup", available: true}
+	ts := newGatewayServer(t, Options{Fallback: []string{"offline", "backup/b1"}}, primary, backup)
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/chat/completions", "", `{"model": "m1", "messages": [{"role": "user", "content": "hi"}]}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the fallback to serve the request, got %d", resp.StatusCode)
+	}
+	if route := resp.Header.Get("X-Fabric-Lite-Route"); route != "backup/b1" || backup.last.Model != "b1" {
+		t.Errorf("Expected backup/b1 to serve the request, got %q with model %q", route, backup.last.Model)
+	}
+
+	var usage struct {
+		Data []usageStats `json:"data"`
+	}
+	decodeBody(t, doRequest(t, http.MethodGet, ts.URL+"/v1/usage", "", ""), &usage)
+	want := map[string]usageStats{
+		"backup/b1": {Provider: "backup", Model: "b1", Requests: 1, Fallbacks: 1, Tokens: 3},
+		"mock/m1":   {Provider: "mock", Model: "m1", Errors: 1},
+		"offline":   {Provider: "offline", Errors: 1},
+	}
+	if len(usage.Data) != len(want) {
+		t.Fatalf("Expected %d usage entries, got %+v", len(want), usage.Data)
+	}
+	for _, st := range usage.Data {
+		key := route{Provider: st.Provider, Model: st.Model}.String()
+		if st != want[key] {
+			t.Errorf("Expected usage %+v for %s, got %+v", want[key], key, st)
+		}
+	}
+
+	backup.fail = errors.New("down too")
+	resp = doRequest(t, http.MethodPost, ts.URL+"/v1/chat/completions", "", `{"messages": [{"role": "user", "content": "hi"}]}`)
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected 502 when every route fails, got %d", resp.StatusCode)
+	}
+}
+
+func TestChatCompletionsStream(t *testing.T) {
+	primary := &mockProvider{name: "mock", available: true, fail: errors.New("overloaded")}
+	backup := &mockProvider{name: "backup", available: true}
+	ts := newGatewayServer(t, Options{Fallback: []string{"backup"}}, primary, backup)
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/chat/completions", "", `{"model": "m1", "stream": true, "messages": [{"role": "user", "content": "hi"}]}`)
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %q", ct)
+	}
+	if route := resp.Header.Get("X-Fabric-Lite-Route"); route != "backup" {
+		t.Errorf("Expected the stream to fall back to backup, got %q", route)
+	}
+
+	var content strings.Builder
+	var finish string
+	var done bool
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		if data == "[DONE]" {
+			done = true
+			continue
+		}
+		var chunk chatResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			t.Fatalf("Invalid chunk %q: %v", data, err)
+		}
+		if chunk.Object != "chat.completion.chunk" || chunk.Model != "m1" {
+			t.Errorf("Unexpected chunk: %+v", chunk)
+		}
+		content.WriteString(chunk.Choices[0].Delta.Content)
+		if reason := chunk.Choices[0].FinishReason; reason != nil {
+			finish = *reason
+		}
+	}
+	if content.String() != "echo: hi" || finish != "stop" || !done {
+		t.Errorf("Expected the echoed reply, a stop reason and [DONE], got %q, %q, %v", content.String(), finish, done)
+	}
+}
+
+func TestModels(t *testing.T) {
+	ts := newGatewayServer(t, Options{ModelMap: map[string]string{"gpt-4o": "backup/b1"}},
+		&mockProvider{name: "mock", available: true, models: []string{"m1", "m2"}},
+		&mockProvider{name: "backup", available: true, models: []string{"b1"}})
+
+	var list struct {
+		Object string      `json:"object"`
+		Data   []modelView `json:"data"`
+	}
+	decodeBody(t, doRequest(t, http.MethodGet, ts.URL+"/v1/models", "", ""), &list)
+
+	var ids []string
+	for _, m := range list.Data {
+		ids = append(ids, m.ID+"@"+m.OwnedBy)
+	}
+	if got := strings.Join(ids, ","); list.Object != "list" || got != "gpt-4o@backup,backup/b1@backup,mock/m1@mock,mock/m2@mock" {
+		t.Errorf("Expected aliases then available provider models, got %s", got)
+	}
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rice0649/fabric-lite/internal/providers"
+)
+
+// route is a provider and the model to request from it; an empty model
+// leaves the choice to the provider
+type route struct {
+	Provider string
+	Model    string
+}
+
+func (r route) String() string {
+	if r.Model == "" {
+		return r.Provider
+	}
+	return r.Provider + "/" + r.Model
+}
+
+// resolveModel routes a client model name to a provider. In order it tries
+// the ModelMap aliases, a "provider/model" prefix naming a known provider,
+// providers that list the model, and finally the default provider.
+func (s *Server) resolveModel(model string) route {
+	if target, ok := s.opts.ModelMap[model]; ok {
+		return parseTarget(target)
+	}
+	if provider, rest, ok := strings.Cut(model, "/"); ok {
+		if _, err := s.providers.Get(provider); err == nil {
+			return route{Provider: provider, Model: rest}
+		}
+	}
+	if model != "" {
+		for _, name := range s.providerNames() {
+			p, err := s.providers.Get(name)
+			if err != nil {
+				continue
+			}
+			for _, m := range p.GetModels() {
+				if m == model {
+					return route{Provider: name, Model: model}
+				}
+			}
+		}
+	}
+	return route{Provider: s.opts.DefaultProvider, Model: model}
+}
+
+// parseTarget splits a "provider/model" target; a bare provider keeps its
+// default model
+func parseTarget(target string) route {
+	provider, model, _ := strings.Cut(target, "/")
+	return route{Provider: provider, Model: model}
+}
+
+// routes returns the resolved route followed by the fallback routes,
+// skipping duplicates of the primary provider
+func (s *Server) routes(model string) []route {
+	primary := s.resolveModel(model)
+	routes := []route{primary}
+	for _, target := range s.opts.Fallback {
+		r := parseTarget(target)
+		if r.Provider != primary.Provider {
+			routes = append(routes, r)
+		}
+	}
+	return routes
+}
+
+// provider returns the named provider if it is registered and available
+func (s *Server) provider(name string) (providers.Provider, error) {
+	p, err := s.providers.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if !p.IsAvailable() {
+		return nil, fmt.Errorf("provider not available: %s", name)
+	}
+	return p, nil
+}
+
+// usageStats counts the traffic routed to one provider and model
+type usageStats struct {
+	Provider  string `json:"provider"`
+	Model     string `json:"model"`
+	Requests  int    `json:"requests"`
+	Errors    int    `json:"errors"`
+	Fallbacks int    `json:"fallbacks"` // Requests served after an earlier route failed
+	Tokens    int    `json:"tokens"`
+}
+
+// usageTracker aggregates usageStats for /v1/usage
+type usageTracker struct {
+	mu    sync.Mutex
+	stats map[route]*usageStats
+}
+
+func newUsageTracker() *usageTracker {
+	return &usageTracker{stats: make(map[route]*usageStats)}
+}
+
+func (u *usageTracker) entry(r route) *usageStats {
+	st, ok := u.stats[r]
+	if !ok {
+		st = &usageStats{Provider: r.Provider, Model: r.Model}
+		u.stats[r] = st
+	}
+	return st
+}
+
+// record counts a successful request; fallback is true when an earlier
+// route failed first
+func (u *usageTracker) record(r route, tokens int, fallback bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	st := u.entry(r)
+	st.Requests++
+	st.Tokens += tokens
+	if fallback {
+		st.Fallbacks++
+	}
+}
+
+// recordError counts a failed attempt on a route
+func (u *usageTracker) recordError(r route) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.entry(r).Errors++
+}
+
+// snapshot returns the stats sorted by provider and model
+func (u *usageTracker) snapshot() []usageStats {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	list := make([]usageStats, 0, len(u.stats))
+	for _, st := range u.stats {
+		list = append(list, *st)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Provider != list[j].Provider {
+			return list[i].Provider < list[j].Provider
+		}
+		return list[i].Model < list[j].Model
+	})
+	return list
+}
@@ -0,0 +1,141 @@
+// Package server exposes fabric-lite patterns and providers over HTTP, both
+// as a REST API and as an OpenAI-compatible gateway
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/rice0649/fabric-lite/internal/providers"
+)
+
+// Defaults for unset Options fields
+const (
+	DefaultAddr            = "127.0.0.1:8080"
+	DefaultMaxBodyBytes    = 1 << 20
+	DefaultMaxConcurrent   = 4
+	DefaultShutdownTimeout = 10 * time.Second
+)
+
+// ProviderRegistry resolves providers by name; core.ProviderManager implements it
+type ProviderRegistry interface {
+	Get(name string) (providers.Provider, error)
+	ListAvailable() []string
+}
+
+// Options configures the HTTP server
+type Options struct {
+	Addr            string
+	Token           string // Bearer token required on /api and /v1 routes; empty disables auth
+	MaxBodyBytes    int64  // Largest accepted request body
+	MaxConcurrent   int    // Executions in flight per client address
+	DefaultProvider string // Provider used when a request names none
+	PatternsDir     string // Overrides the pattern executor's directory when set
+	ShutdownTimeout time.Duration
+
+	// ModelMap maps model names on /v1 routes to "provider/model" targets
+	ModelMap map[string]string
+	// Fallback lists routes tried in order when the resolved provider fails
+	Fallback []string
+}
+
+// Server serves the REST API
+type Server struct {
+	opts      Options
+	providers ProviderRegistry
+	limiter   *clientLimiter
+	usage     *usageTracker
+}
+
+// New creates a server for the given providers, filling in option defaults
+func New(opts Options, registry ProviderRegistry) *Server {
+	if opts.Addr == "" {
+		opts.Addr = DefaultAddr
+	}
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if opts.MaxConcurrent <= 0 {
+		opts.MaxConcurrent = DefaultMaxConcurrent
+	}
+	if opts.ShutdownTimeout <= 0 {
+		opts.ShutdownTimeout = DefaultShutdownTimeout
+	}
+	return &Server{
+		opts:      opts,
+		providers: registry,
+		limiter:   newClientLimiter(opts.MaxConcurrent),
+		usage:     newUsageTracker(),
+	}
+}
+
+// Handler returns the HTTP handler with all routes and middleware
+func (s *Server) Handler() http.Handler {
+	api := http.NewServeMux()
+	api.HandleFunc("/api/patterns", s.handlePatterns)
+	api.HandleFunc("/api/execute", s.limit(s.handleExecute))
+	api.HandleFunc("/api/providers", s.handleProviders)
+
+	// OpenAI-compatible gateway
+	api.HandleFunc("/v1/chat/completions", s.limit(s.handleChatCompletions))
+	api.HandleFunc("/v1/models", s.handleModels)
+	api.HandleFunc("/v1/usage", s.handleUsage)
+
+	protected := s.authenticate(s.limitBody(api))
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.Handle("/api/", protected)
+	mux.Handle("/v1/", protected)
+	return mux
+}
+
+// ListenAndServe serves until ctx is cancelled, then shuts down gracefully,
+// letting requests in flight finish within the shutdown timeout
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.opts.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve is ListenAndServe on an existing listener
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.opts.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// Closing cancels the requests still running, such as long streams
+		srv.Close()
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-errc; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// providerNames returns the registered provider names, sorted
+func (s *Server) providerNames() []string {
+	names := s.providers.ListAvailable()
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,329 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rice0649/fabric-lite/internal/providers"
+)
+
+// mockProvider echoes the prompt and records the last request. With fail
+// set it returns that error; with release set, Execute signals started and
+// blocks until release is closed
+type mockProvider struct {
+	name      string
+	available bool
+	models    []string
+	fail      error
+	last      providers.CompletionRequest
+	started   chan struct{}
+	release   chan struct{}
+}
+
+func newBlockingProvider() *mockProvider {
+	return &mockProvider{name: "mock", available: true, started: make(chan struct{}, 8), release: make(chan struct{})}
+}
+
+func (m *mockProvider) Name() string { return m.name }
+
+func (m *mockProvider) Execute(ctx context.Context, req providers.CompletionRequest) (*providers.CompletionResponse, error) {
+	m.last = req
+	if m.fail != nil {
+		return nil, m.fail
+	}
+	if m.release != nil {
+		m.started <- struct{}{}
+		select {
+		case <-m.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return &providers.CompletionResponse{Content: "echo: " + req.Prompt, Model: req.Model, Tokens: 3, Duration: 5 * time.Millisecond}, nil
+}
+
+func (m *mockProvider) ExecuteStream(ctx context.Context, req providers.CompletionRequest) (<-chan providers.StreamChunk, error) {
+	m.last = req
+	ch := make(chan providers.StreamChunk, 3)
+	if m.fail != nil {
+		ch <- providers.StreamChunk{Error: m.fail}
+		close(ch)
+		return ch, nil
+	}
+	ch <- providers.StreamChunk{Content: "echo: "}
+	ch <- providers.StreamChunk{Content: req.Prompt}
+	ch <- providers.StreamChunk{Done: true}
+	close(ch)
+	return ch, nil
+}
+
+func (m *mockProvider) IsAvailable() bool   { return m.available }
+func (m *mockProvider) GetModels() []string { return m.models }
+
+// mockRegistry is a fixed set of providers
+type mockRegistry map[string]providers.Provider
+
+func (r mockRegistry) Get(name string) (providers.Provider, error) {
+	if p, ok := r[name]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("provider not found: %s", name)
+}
+
+func (r mockRegistry) ListAvailable() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	return names
+}
+
+// newTestServer serves a summarize pattern through a mock provider
+func newTestServer(t *testing.T, opts Options, provider *mockProvider) *httptest.Server {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "summarize"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "summarize", "system.md"), []byte("# IDENTITY and PURPOSE\n\nYou are a summarizer.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts.PatternsDir = dir
+	opts.DefaultProvider = "mock"
+	registry := mockRegistry{"mock": provider, "offline": &mockProvider{name: "offline"}}
+	ts := httptest.NewServer(New(opts, registry).Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func doRequest(t *testing.T, method, url, token, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func decodeBody(t *testing.T, resp *http.Response, v interface{}) {
+	t.Helper()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+}
+
+func TestHealthAndAuth(t *testing.T) {
+	ts := newTestServer(t, Options{Token: "secret"}, &mockProvider{name: "mock", available: true})
+
+	if resp := doRequest(t, http.MethodGet, ts.URL+"/health", "", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected health without auth to be 200, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(t, http.MethodGet, ts.URL+"/api/patterns", "", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(t, http.MethodGet, ts.URL+"/api/patterns", "wrong", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 with a wrong token, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(t, http.MethodGet, ts.URL+"/api/patterns", "secret", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 with the token, got %d", resp.StatusCode)
+	}
+}
+
+func TestListPatternsAndProviders(t *testing.T) {
+	ts := newTestServer(t, Options{}, &mockProvider{name: "mock", available: true, models: []string{"m1"}})
+
+	var patterns struct {
+		Patterns []patternView `json:"patterns"`
+	}
+	decodeBody(t, doRequest(t, http.MethodGet, ts.URL+"/api/patterns", "", ""), &patterns)
+	if len(patterns.Patterns) != 1 || patterns.Patterns[0].Name != "summarize" || patterns.Patterns[0].Description != "You are a summarizer." {
+		t.Errorf("Expected the summarize pattern, got %+v", patterns.Patterns)
+	}
+
+	var list struct {
+		Providers []providerView `json:"providers"`
+	}
+	decodeBody(t, doRequest(t, http.MethodGet, ts.URL+"/api/providers", "", ""), &list)
+	if len(list.Providers) != 2 {
+		t.Fatalf("Expected two providers, got %+v", list.Providers)
+	}
+	mock, offline := list.Providers[0], list.Providers[1]
+	if mock.Name != "mock" || !mock.Available || !mock.Default || len(mock.Models) != 1 {
+		t.Errorf("Expected the available default mock provider, got %+v", mock)
+	}
+	if offline.Available || offline.Default || offline.Models == nil {
+		t.Errorf("Expected an unavailable provider with an empty model list, got %+v", offline)
+	}
+
+	if resp := doRequest(t, http.MethodPost, ts.URL+"/api/patterns", "", ""); resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for POST /api/patterns, got %d", resp.StatusCode)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	mock := &mockProvider{name: "mock", available: true}
+	ts := newTestServer(t, Options{MaxBodyBytes: 256}, mock)
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/api/execute", "", `{"pattern": "summarize", "input": "hello", "model": "m1", "temperature": 0.3}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	var result executeResponse
+	decodeBody(t, resp, &result)
+	if result.Content != "echo: hello" || result.Provider != "mock" || result.Model != "m1" || result.Tokens != 3 || result.DurationMs != 5 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if mock.last.Temperature == nil || *mock.last.Temperature != 0.3 {
+		t.Errorf("Expected the temperature to reach the provider, got %+v", mock.last.Sampling)
+	}
+
+	for body, status := range map[string]int{
+		`{"pattern": "missing", "input": "x"}`:            http.StatusNotFound,
+		`{"pattern": "../etc", "input": "x"}`:             http.StatusBadRequest,
+		`{"pattern": "summarize", "provider": "nope"}`:    http.StatusBadRequest,
+		`{"pattern": "summarize", "temperature": 5}`:      http.StatusBadRequest,
+		`{"pattern": "summarize", "provider": "offline"}`: http.StatusServiceUnavailable,
+		`{"pattern": `: http.StatusBadRequest,
+		`{"pattern": "summarize", "input": "` + strings.Repeat("x", 300) + `"}`: http.StatusRequestEntityTooLarge,
+	} {
+		if resp := doRequest(t, http.MethodPost, ts.URL+"/api/execute", "", body); resp.StatusCode != status {
+			t.Errorf("Expected %d for %.40s, got %d", status, body, resp.StatusCode)
+		}
+	}
+}
+
+func TestExecuteStream(t *testing.T) {
+	ts := newTestServer(t, Options{}, &mockProvider{name: "mock", available: true})
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/api/execute", "", `{"pattern": "summarize", "input": "hello", "stream": true}`)
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %q", ct)
+	}
+
+	var events, content []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "event: ") {
+			events = append(events, strings.TrimPrefix(line, "event: "))
+		}
+		if strings.HasPrefix(line, "data: ") && events[len(events)-1] == "chunk" {
+			var chunk map[string]string
+			json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &chunk)
+			content = append(content, chunk["content"])
+		}
+	}
+
+	if strings.Join(events, ",") != "chunk,chunk,done" || strings.Join(content, "") != "echo: hello" {
+		t.Errorf("Expected two chunks and done, got events %v with content %q", events, content)
+	}
+}
+
+func TestExecuteConcurrencyLimit(t *testing.T) {
+	provider := newBlockingProvider()
+	ts := newTestServer(t, Options{MaxConcurrent: 1}, provider)
+
+	done := make(chan int, 1)
+	go func() {
+		resp, err := http.Post(ts.URL+"/api/execute", "application/json", strings.NewReader(`{"pattern": "summarize", "input": "slow"}`))
+		if err != nil {
+			done <- 0
+			return
+		}
+		resp.Body.Close()
+		done <- resp.StatusCode
+	}()
+	<-provider.started
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/api/execute", "", `{"pattern": "summarize", "input": "fast"}`)
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 while the client has a request in flight, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") == "" {
+		t.Error("Expected a Retry-After header")
+	}
+
+	close(provider.release)
+	if status := <-done; status != http.StatusOK {
+		t.Errorf("Expected the first request to succeed, got %d", status)
+	}
+}
+
+func TestClientLimiter(t *testing.T) {
+	l := newClientLimiter(2)
+	if !l.acquire("a") || !l.acquire("a") {
+		t.Fatal("Expected two slots for client a")
+	}
+	if l.acquire("a") {
+		t.Error("Expected a third request from a to be refused")
+	}
+	if !l.acquire("b") {
+		t.Error("Expected client b to have its own slots")
+	}
+	l.release("a")
+	if !l.acquire("a") {
+		t.Error("Expected a released slot to be reusable")
+	}
+}
+
+func TestServeGracefulShutdown(t *testing.T) {
+	provider := newBlockingProvider()
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "summarize"), 0755)
+	os.WriteFile(filepath.Join(dir, "summarize", "system.md"), []byte("You are a summarizer."), 0644)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := New(Options{PatternsDir: dir, DefaultProvider: "mock"}, mockRegistry{"mock": provider})
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ctx, ln) }()
+
+	url := "http://" + ln.Addr().String()
+	inFlight := make(chan int, 1)
+	go func() {
+		resp, err := http.Post(url+"/api/execute", "application/json", strings.NewReader(`{"pattern": "summarize", "input": "x"}`))
+		if err != nil {
+			inFlight <- 0
+			return
+		}
+		resp.Body.Close()
+		inFlight <- resp.StatusCode
+	}()
+
+	<-provider.started
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(provider.release)
+
+	if status := <-inFlight; status != http.StatusOK {
+		t.Errorf("Expected the in-flight request to finish during shutdown, got %d", status)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("Expected a clean shutdown, got %v", err)
+	}
+	if _, err := http.Get(url + "/health"); err == nil {
+		t.Error("Expected the server to stop accepting connections")
+	}
+}
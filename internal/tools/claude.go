@@ -1,11 +1,6 @@
 package tools
 
-import (
-	"bytes"
-	"fmt"
-	"os"
-	"os/exec"
-)
+import "github.com/rice0649/fabric-lite/internal/core"
 
 // ClaudeTool wraps the Claude CLI for large-scale architecture and refactoring tasks
 type ClaudeTool struct {
@@ -23,96 +18,37 @@ func NewClaudeTool() *ClaudeTool {
 	}
 }
 
-// Execute runs the Claude CLI with the given context
-func (t *ClaudeTool) Execute(ctx ExecutionContext) (*ExecutionResult, error) {
-	// Build the prompt based on phase
-	prompt := ctx.Prompt
-	if prompt == "" {
-		prompt = t.getPhasePrompt(ctx.Phase)
-	}
-
-	// Build command arguments
-	args := []string{}
-
-	// Add prompt if provided (claude uses -p for print/prompt mode)
-	if prompt != "" {
-		args = append(args, "-p", prompt)
-	}
-
-	// Add any additional arguments
-	args = append(args, ctx.Args...)
-
-	// Create command
-	cmd := exec.Command(t.command, args...)
-	cmd.Dir = ctx.WorkDir
-	if cmd.Dir == "" {
-		cmd.Dir, _ = os.Getwd()
-	}
-
-	// Set environment
-	cmd.Env = os.Environ()
-	for k, v := range ctx.Env {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
-	}
-
-	// Connect to terminal for interactive use
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	// Run the command
-	err := cmd.Run()
-
-	result := &ExecutionResult{
-		ExitCode: cmd.ProcessState.ExitCode(),
-		Success:  err == nil,
+// Definition runs Claude in print mode (-p) with the phase prompt
+func (t *ClaudeTool) Definition() core.ToolDefinition {
+	return core.ToolDefinition{
+		Description: t.description,
+		Command:     t.command,
+		Args:        []string{"{{if .Prompt}}-p{{end}}", "{{.Prompt}}"},
+		Prompts:     claudePhasePrompts,
 	}
+}
 
+// Execute runs the Claude CLI attached to the terminal
+func (t *ClaudeTool) Execute(ctx ExecutionContext) (*ExecutionResult, error) {
+	tool, err := NewCommandTool(t.name, t.Definition())
 	if err != nil {
-		result.Error = err.Error()
+		return nil, err
 	}
-
-	return result, nil
+	return tool.Execute(ctx)
 }
 
-// ExecuteNonInteractive runs Claude and captures output (for automation)
+// ExecuteNonInteractive runs the Claude CLI and captures output (for automation)
 func (t *ClaudeTool) ExecuteNonInteractive(ctx ExecutionContext) (*ExecutionResult, error) {
-	prompt := ctx.Prompt
-	if prompt == "" {
-		prompt = t.getPhasePrompt(ctx.Phase)
-	}
-
-	args := []string{}
-	if prompt != "" {
-		args = append(args, "-p", prompt)
-	}
-	args = append(args, ctx.Args...)
-
-	cmd := exec.Command(t.command, args...)
-	cmd.Dir = ctx.WorkDir
-	if cmd.Dir == "" {
-		cmd.Dir, _ = os.Getwd()
-	}
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-
-	result := &ExecutionResult{
-		Output:   stdout.String(),
-		Error:    stderr.String(),
-		ExitCode: cmd.ProcessState.ExitCode(),
-		Success:  err == nil,
+	tool, err := NewCommandTool(t.name, t.Definition())
+	if err != nil {
+		return nil, err
 	}
-
-	return result, nil
+	return tool.ExecuteNonInteractive(ctx)
 }
 
-func (t *ClaudeTool) getPhasePrompt(phase string) string {
-	prompts := map[string]string{
-		"planning": `You are helping with the planning phase of a software project.
+// claudePhasePrompts are the default prompts per phase
+var claudePhasePrompts = map[string]string{
+	"planning": `You are helping with the planning phase of a software project.
 
 Tasks:
 1. Analyze the project requirements and constraints
@@ -122,7 +58,7 @@ Tasks:
 
 Please help create a comprehensive project plan.`,
 
-		"design": `You are helping with the design phase of a software project.
+	"design": `You are helping with the design phase of a software project.
 
 Tasks:
 1. Create detailed technical designs
@@ -132,7 +68,7 @@ Tasks:
 
 Please help refine the technical design.`,
 
-		"implementation": `You are helping with the implementation phase of a software project.
+	"implementation": `You are helping with the implementation phase of a software project.
 
 Tasks:
 1. Write clean, maintainable code
@@ -142,7 +78,7 @@ Tasks:
 
 Please help implement the required functionality.`,
 
-		"deployment": `You are helping with the deployment phase of a software project.
+	"deployment": `You are helping with the deployment phase of a software project.
 
 Tasks:
 1. Prepare deployment configurations
@@ -151,10 +87,4 @@ Tasks:
 4. Create rollback procedures
 
 Please help prepare for production deployment.`,
-	}
-
-	if p, ok := prompts[phase]; ok {
-		return p
-	}
-	return ""
 }
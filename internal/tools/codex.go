@@ -1,8 +1,8 @@
 package tools
 
 import (
-	"context"
 	"fmt"
+	"strings"
 
 	"github.com/rice0649/fabric-lite/internal/core"
 	"github.com/rice0649/fabric-lite/internal/providers"
@@ -40,14 +40,26 @@ func (t *CodexTool) IsAvailable() bool {
 	return t.providerManager.CheckAvailability(t.config.Provider)
 }
 
-// Execute runs the codex meta-tool. It uses an LLM provider.
+// Execute runs the codex meta-tool. It uses an LLM provider, so there is no
+// interactive mode and the output is always captured.
 func (t *CodexTool) Execute(ctx ExecutionContext) (*ExecutionResult, error) {
+	return t.ExecuteNonInteractive(ctx)
+}
+
+// ExecuteNonInteractive runs the codex meta-tool and captures the provider response.
+func (t *CodexTool) ExecuteNonInteractive(ctx ExecutionContext) (*ExecutionResult, error) {
 	if !t.IsAvailable() {
 		return nil, fmt.Errorf("codex tool is not available (either not enabled or provider '%s' is not ready)", t.config.Provider)
 	}
 
+	prompt := ctx.Prompt
+	if prompt == "" {
+		prompt = t.getPhasePrompt(ctx.Phase)
+	}
+	prompt = withContext(prompt, ctx.Context)
+
 	// Combine the codex system prompt with the user's prompt.
-	fullPrompt := fmt.Sprintf("%s\n\n--- Prompt ---\n\n%s", codexSystemPrompt, ctx.Prompt)
+	fullPrompt := fmt.Sprintf("%s\n\n--- Prompt ---\n\n%s", codexSystemPrompt, prompt)
 
 	// Create a CompletionRequest for the LLM provider
 	completionRequest := providers.CompletionRequest{
@@ -56,8 +68,11 @@ func (t *CodexTool) Execute(ctx ExecutionContext) (*ExecutionResult, error) {
 		Prompt: "", // User's prompt is integrated into System
 	}
 
+	runCtx, cancel := ctx.deadline()
+	defer cancel()
+
 	// Delegate execution to the LLM provider
-	resp, err := t.providerManager.Execute(context.Background(), t.config.Provider, completionRequest)
+	resp, err := t.providerManager.Execute(runCtx, t.config.Provider, completionRequest)
 	if err != nil {
 		return nil, fmt.Errorf("codex execution via provider '%s' failed: %w", t.config.Provider, err)
 	}
@@ -69,3 +84,24 @@ func (t *CodexTool) Execute(ctx ExecutionContext) (*ExecutionResult, error) {
 		Success:  true,
 	}, nil
 }
+
+// getPhasePrompt builds a prompt from the phase definition, since codex has
+// no phase-specific prompts of its own
+func (t *CodexTool) getPhasePrompt(phase string) string {
+	p := core.GetPhase(phase)
+	if p == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("You are helping with the %s phase of a software project (%s).\n\n", p.Name, p.Description))
+	sb.WriteString("The phase is complete when:\n")
+	for _, c := range p.Checkpoint.Criteria {
+		sb.WriteString(fmt.Sprintf("- %s\n", c))
+	}
+	sb.WriteString("\nProduce the following artifacts as markdown sections:\n")
+	for _, a := range p.Artifacts {
+		sb.WriteString(fmt.Sprintf("- %s\n", a))
+	}
+	return sb.String()
+}
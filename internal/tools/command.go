@@ -0,0 +1,257 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/rice0649/fabric-lite/internal/core"
+)
+
+// checkTimeout bounds a definition's availability check
+const checkTimeout = 5 * time.Second
+
+// defaultArgs passes the prompt as the only argument
+var defaultArgs = []string{"{{.Prompt}}"}
+
+// CommandTool runs a command-line tool declared by a core.ToolDefinition
+type CommandTool struct {
+	BaseTool
+	def core.ToolDefinition
+
+	args  []*template.Template
+	stdin *template.Template
+	env   map[string]*template.Template
+}
+
+// NewCommandTool creates a tool from a definition
+func NewCommandTool(name string, def core.ToolDefinition) (*CommandTool, error) {
+	if err := def.Validate(); err != nil {
+		return nil, fmt.Errorf("tool %s: %w", name, err)
+	}
+	if def.Args == nil {
+		def.Args = defaultArgs
+	}
+
+	t := &CommandTool{
+		BaseTool: BaseTool{name: name, description: def.Description, command: def.Command},
+		def:      def,
+		env:      make(map[string]*template.Template, len(def.Env)),
+	}
+	if t.description == "" {
+		t.description = "Custom tool: " + def.Command
+	}
+
+	parse := func(field, text string) (*template.Template, error) {
+		tmpl, err := template.New(field).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: %s: %w", name, field, err)
+		}
+		return tmpl, nil
+	}
+	for i, arg := range def.Args {
+		tmpl, err := parse(fmt.Sprintf("args[%d]", i), arg)
+		if err != nil {
+			return nil, err
+		}
+		t.args = append(t.args, tmpl)
+	}
+	if def.Stdin != "" {
+		tmpl, err := parse("stdin", def.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		t.stdin = tmpl
+	}
+	for k, v := range def.Env {
+		tmpl, err := parse("env."+k, os.ExpandEnv(v))
+		if err != nil {
+			return nil, err
+		}
+		t.env[k] = tmpl
+	}
+	return t, nil
+}
+
+// Definition returns the tool's definition
+func (t *CommandTool) Definition() core.ToolDefinition {
+	return t.def
+}
+
+// IsAvailable runs the check command, or looks the command up in PATH
+func (t *CommandTool) IsAvailable() bool {
+	if len(t.def.Check) == 0 {
+		return checkCommand(t.command)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, t.def.Check[0], t.def.Check[1:]...).Run() == nil
+}
+
+// Execute runs the tool attached to the terminal, or captures its output
+// in capture mode
+func (t *CommandTool) Execute(ctx ExecutionContext) (*ExecutionResult, error) {
+	if t.def.Mode == core.ToolModeCapture {
+		return t.ExecuteNonInteractive(ctx)
+	}
+
+	cmd, err := t.buildCmd(context.Background(), ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Connect to terminal for interactive use
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Run()
+
+	result := &ExecutionResult{
+		ExitCode: cmd.ProcessState.ExitCode(),
+		Success:  err == nil,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result, nil
+}
+
+// ExecuteNonInteractive runs the tool and captures its output
+func (t *CommandTool) ExecuteNonInteractive(ctx ExecutionContext) (*ExecutionResult, error) {
+	runCtx, cancel := ctx.deadline()
+	defer cancel()
+
+	cmd, err := t.buildCmd(runCtx, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if t.stdin != nil {
+		input, err := render(t.stdin, t.data(ctx))
+		if err != nil {
+			return nil, err
+		}
+		if input != "" {
+			cmd.Stdin = strings.NewReader(input)
+		}
+	}
+
+	err = cmd.Run()
+
+	result := &ExecutionResult{
+		Output:   stdout.String(),
+		Error:    stderr.String(),
+		ExitCode: cmd.ProcessState.ExitCode(),
+		Success:  err == nil,
+	}
+	if err != nil && cmd.ProcessState == nil {
+		result.Error = err.Error()
+	}
+
+	return timeoutResult(result, runCtx, ctx.Timeout), nil
+}
+
+// buildCmd builds the command with rendered args, environment and directory
+func (t *CommandTool) buildCmd(runCtx context.Context, ctx ExecutionContext) (*exec.Cmd, error) {
+	data := t.data(ctx)
+
+	args := make([]string, 0, len(t.args)+len(ctx.Args))
+	for _, tmpl := range t.args {
+		arg, err := render(tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+		if arg != "" {
+			args = append(args, arg)
+		}
+	}
+	args = append(args, ctx.Args...)
+
+	cmd := exec.CommandContext(runCtx, t.command, args...)
+
+	cmd.Dir = ctx.WorkDir
+	if t.def.WorkDir != "" {
+		cmd.Dir = t.def.WorkDir
+		if !filepath.IsAbs(cmd.Dir) && ctx.WorkDir != "" {
+			cmd.Dir = filepath.Join(ctx.WorkDir, cmd.Dir)
+		}
+	}
+	if cmd.Dir == "" {
+		cmd.Dir, _ = os.Getwd()
+	}
+
+	// Definition env first so the execution context can override it
+	cmd.Env = os.Environ()
+	names := make([]string, 0, len(t.env))
+	for k := range t.env {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		v, err := render(t.env[k], data)
+		if err != nil {
+			return nil, err
+		}
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	for k, v := range ctx.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	return cmd, nil
+}
+
+// toolTemplateData is what args, stdin and env templates see
+type toolTemplateData struct {
+	Prompt  string // The prompt, followed by any context
+	Phase   string
+	Pattern string
+	Context string
+	Config  map[string]string
+}
+
+// data resolves the prompt and pattern, falling back to the phase defaults
+func (t *CommandTool) data(ctx ExecutionContext) toolTemplateData {
+	prompt := ctx.Prompt
+	if prompt == "" {
+		prompt = phaseDefault(t.def.Prompts, ctx.Phase)
+	}
+	pattern := ctx.Pattern
+	if pattern == "" {
+		pattern = phaseDefault(t.def.Patterns, ctx.Phase)
+	}
+	return toolTemplateData{
+		Prompt:  withContext(prompt, ctx.Context),
+		Phase:   ctx.Phase,
+		Pattern: pattern,
+		Context: ctx.Context,
+		Config:  t.def.Config,
+	}
+}
+
+// phaseDefault returns the value for phase, or the "default" entry
+func phaseDefault(values map[string]string, phase string) string {
+	if v, ok := values[phase]; ok {
+		return v
+	}
+	return values["default"]
+}
+
+func render(tmpl *template.Template, data toolTemplateData) (string, error) {
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render %s: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
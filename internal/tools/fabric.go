@@ -1,11 +1,10 @@
 package tools
 
 import (
-	"bytes"
-	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+
+	"github.com/rice0649/fabric-lite/internal/core"
 )
 
 // FabricTool wraps fabric-lite for pattern-based generation
@@ -32,91 +31,34 @@ func NewFabricTool() *FabricTool {
 	}
 }
 
-// Execute runs fabric-lite with a pattern
-func (t *FabricTool) Execute(ctx ExecutionContext) (*ExecutionResult, error) {
-	// Determine pattern to use
-	pattern := ctx.Pattern
-	if pattern == "" {
-		pattern = t.getPhasePattern(ctx.Phase)
-	}
-
-	// Build command arguments
-	args := []string{}
-
-	if pattern != "" {
-		args = append(args, "--pattern", pattern)
-	}
-
-	// Add any additional arguments
-	args = append(args, ctx.Args...)
-
-	// Create command
-	cmd := exec.Command(t.command, args...)
-	cmd.Dir = ctx.WorkDir
-	if cmd.Dir == "" {
-		cmd.Dir, _ = os.Getwd()
-	}
-
-	// Set environment
-	cmd.Env = os.Environ()
-	for k, v := range ctx.Env {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
-	}
-
-	// Connect to terminal for interactive use
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	// Run the command
-	err := cmd.Run()
-
-	result := &ExecutionResult{
-		ExitCode: cmd.ProcessState.ExitCode(),
-		Success:  err == nil,
+// Definition selects the pattern with --pattern and pipes the prompt to
+// stdin when output is captured
+func (t *FabricTool) Definition() core.ToolDefinition {
+	return core.ToolDefinition{
+		Description: t.description,
+		Command:     t.command,
+		Args:        []string{"{{if .Pattern}}--pattern{{end}}", "{{.Pattern}}"},
+		Stdin:       "{{.Prompt}}",
+		Patterns:    fabricPhasePatterns,
 	}
+}
 
+// Execute runs fabric-lite with a pattern
+func (t *FabricTool) Execute(ctx ExecutionContext) (*ExecutionResult, error) {
+	tool, err := NewCommandTool(t.name, t.Definition())
 	if err != nil {
-		result.Error = err.Error()
+		return nil, err
 	}
-
-	return result, nil
+	return tool.Execute(ctx)
 }
 
 // ExecuteNonInteractive runs fabric-lite and captures output
 func (t *FabricTool) ExecuteNonInteractive(ctx ExecutionContext) (*ExecutionResult, error) {
-	pattern := ctx.Pattern
-	if pattern == "" {
-		pattern = t.getPhasePattern(ctx.Phase)
-	}
-
-	args := []string{}
-	if pattern != "" {
-		args = append(args, "--pattern", pattern)
-	}
-	args = append(args, ctx.Args...)
-
-	cmd := exec.Command(t.command, args...)
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	// Pipe input if provided
-	if ctx.Prompt != "" {
-		cmd.Stdin = bytes.NewBufferString(ctx.Prompt)
-	}
-
-	err := cmd.Run()
-
-	result := &ExecutionResult{
-		Output:   stdout.String(),
-		Error:    stderr.String(),
-		ExitCode: cmd.ProcessState.ExitCode(),
-		Success:  err == nil,
+	tool, err := NewCommandTool(t.name, t.Definition())
+	if err != nil {
+		return nil, err
 	}
-
-	return result, nil
+	return tool.ExecuteNonInteractive(ctx)
 }
 
 // ListPatterns returns available patterns
@@ -156,21 +98,15 @@ func (t *FabricTool) GetPatternPath(name string) string {
 	return ""
 }
 
-func (t *FabricTool) getPhasePattern(phase string) string {
-	// Map phases to default patterns
-	patterns := map[string]string{
-		"discovery":      "research_topic",
-		"planning":       "create_architecture",
-		"design":         "create_api_spec",
-		"implementation": "explain_code",
-		"testing":        "create_test_plan",
-		"deployment":     "create_release_notes",
-	}
-
-	if p, ok := patterns[phase]; ok {
-		return p
-	}
-	return "summarize"
+// fabricPhasePatterns maps phases to default patterns
+var fabricPhasePatterns = map[string]string{
+	"discovery":      "research_topic",
+	"planning":       "create_architecture",
+	"design":         "create_api_spec",
+	"implementation": "explain_code",
+	"testing":        "create_test_plan",
+	"deployment":     "create_release_notes",
+	"default":        "summarize",
 }
 
 // DeploymentPatterns returns patterns suitable for deployment phase
@@ -1,11 +1,6 @@
 package tools
 
-import (
-	"bytes"
-	"fmt"
-	"os"
-	"os/exec"
-)
+import "github.com/rice0649/fabric-lite/internal/core"
 
 // GeminiTool wraps the Gemini CLI for research and discovery tasks
 type GeminiTool struct {
@@ -23,92 +18,37 @@ func NewGeminiTool() *GeminiTool {
 	}
 }
 
-// Execute runs the Gemini CLI with the given context
-func (t *GeminiTool) Execute(ctx ExecutionContext) (*ExecutionResult, error) {
-	// Build the prompt based on phase
-	prompt := ctx.Prompt
-	if prompt == "" {
-		prompt = t.getPhasePrompt(ctx.Phase)
-	}
-
-	// Build command arguments
-	args := []string{}
-
-	// Add prompt if provided
-	if prompt != "" {
-		args = append(args, prompt)
-	}
-
-	// Add any additional arguments
-	args = append(args, ctx.Args...)
-
-	// Create command
-	cmd := exec.Command(t.command, args...)
-	cmd.Dir = ctx.WorkDir
-	if cmd.Dir == "" {
-		cmd.Dir, _ = os.Getwd()
-	}
-
-	// Set environment
-	cmd.Env = os.Environ()
-	for k, v := range ctx.Env {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
-	}
-
-	// Connect to terminal for interactive use
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	// Run the command
-	err := cmd.Run()
-
-	result := &ExecutionResult{
-		ExitCode: cmd.ProcessState.ExitCode(),
-		Success:  err == nil,
+// Definition passes the prompt as the only argument; tools.yaml may override any field
+func (t *GeminiTool) Definition() core.ToolDefinition {
+	return core.ToolDefinition{
+		Description: t.description,
+		Command:     t.command,
+		Args:        []string{"{{.Prompt}}"},
+		Prompts:     geminiPhasePrompts,
 	}
+}
 
+// Execute runs the Gemini CLI attached to the terminal
+func (t *GeminiTool) Execute(ctx ExecutionContext) (*ExecutionResult, error) {
+	tool, err := NewCommandTool(t.name, t.Definition())
 	if err != nil {
-		result.Error = err.Error()
+		return nil, err
 	}
-
-	return result, nil
+	return tool.Execute(ctx)
 }
 
-// ExecuteNonInteractive runs Gemini and captures output (for automation)
+// ExecuteNonInteractive runs the Gemini CLI and captures output (for automation)
 func (t *GeminiTool) ExecuteNonInteractive(ctx ExecutionContext) (*ExecutionResult, error) {
-	prompt := ctx.Prompt
-	if prompt == "" {
-		prompt = t.getPhasePrompt(ctx.Phase)
-	}
-
-	args := []string{}
-	if prompt != "" {
-		args = append(args, prompt)
-	}
-	args = append(args, ctx.Args...)
-
-	cmd := exec.Command(t.command, args...)
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-
-	result := &ExecutionResult{
-		Output:   stdout.String(),
-		Error:    stderr.String(),
-		ExitCode: cmd.ProcessState.ExitCode(),
-		Success:  err == nil,
+	tool, err := NewCommandTool(t.name, t.Definition())
+	if err != nil {
+		return nil, err
 	}
-
-	return result, nil
+	return tool.ExecuteNonInteractive(ctx)
 }
 
-func (t *GeminiTool) getPhasePrompt(phase string) string {
-	prompts := map[string]string{
-		"discovery": `You are helping with the discovery phase of a software project.
+// geminiPhasePrompts are the default prompts per phase
+var geminiPhasePrompts = map[string]string{
+	"discovery": `You are helping with the discovery phase of a software project.
 
 Tasks:
 1. Gather requirements and understand the problem space
@@ -118,7 +58,7 @@ Tasks:
 
 Please analyze the project context and help gather requirements.`,
 
-		"testing": `You are helping with the testing phase of a software project.
+	"testing": `You are helping with the testing phase of a software project.
 
 Tasks:
 1. Analyze the codebase for test coverage gaps
@@ -127,10 +67,4 @@ Tasks:
 4. Review test quality and completeness
 
 Please examine the code and help improve test coverage.`,
-	}
-
-	if p, ok := prompts[phase]; ok {
-		return p
-	}
-	return ""
 }
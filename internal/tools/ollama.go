@@ -96,6 +96,11 @@ func (t *OllamaTool) Execute(ctx ExecutionContext) (*ExecutionResult, error) {
 	}, nil
 }
 
+// ExecuteNonInteractive runs the ollama command; output is always captured.
+func (t *OllamaTool) ExecuteNonInteractive(ctx ExecutionContext) (*ExecutionResult, error) {
+	return t.Execute(ctx)
+}
+
 func (t *OllamaTool) pullModel(ctx context.Context, model string) (string, error) {
 	cmd := exec.CommandContext(ctx, "ollama", "pull", model)
 	output, err := cmd.CombinedOutput()
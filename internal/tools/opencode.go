@@ -1,11 +1,6 @@
 package tools
 
-import (
-	"bytes"
-	"fmt"
-	"os"
-	"os/exec"
-)
+import "github.com/rice0649/fabric-lite/internal/core"
 
 // OpenCodeTool wraps OpenCode for planning and design tasks
 type OpenCodeTool struct {
@@ -23,98 +18,37 @@ func NewOpenCodeTool() *OpenCodeTool {
 	}
 }
 
-// Execute runs OpenCode with the given context
-func (t *OpenCodeTool) Execute(ctx ExecutionContext) (*ExecutionResult, error) {
-	// Build the prompt based on phase
-	prompt := ctx.Prompt
-	if prompt == "" {
-		prompt = t.getPhasePrompt(ctx.Phase)
-	}
-
-	// Build command arguments
-	args := []string{}
-
-	// For planning phases, start in plan mode
-	if ctx.Phase == "planning" || ctx.Phase == "design" {
-		// OpenCode may support plan mode or similar
-		// This depends on the actual CLI interface
-	}
-
-	// Add prompt if provided
-	if prompt != "" {
-		args = append(args, prompt)
-	}
-
-	// Add any additional arguments
-	args = append(args, ctx.Args...)
-
-	// Create command
-	cmd := exec.Command(t.command, args...)
-	cmd.Dir = ctx.WorkDir
-	if cmd.Dir == "" {
-		cmd.Dir, _ = os.Getwd()
-	}
-
-	// Set environment
-	cmd.Env = os.Environ()
-	for k, v := range ctx.Env {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
-	}
-
-	// Connect to terminal for interactive use
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	// Run the command
-	err := cmd.Run()
-
-	result := &ExecutionResult{
-		ExitCode: cmd.ProcessState.ExitCode(),
-		Success:  err == nil,
+// Definition passes the planning and design prompts as the first argument
+func (t *OpenCodeTool) Definition() core.ToolDefinition {
+	return core.ToolDefinition{
+		Description: t.description,
+		Command:     t.command,
+		Args:        []string{"{{.Prompt}}"},
+		Prompts:     openCodePhasePrompts,
 	}
+}
 
+// Execute runs the OpenCode CLI attached to the terminal
+func (t *OpenCodeTool) Execute(ctx ExecutionContext) (*ExecutionResult, error) {
+	tool, err := NewCommandTool(t.name, t.Definition())
 	if err != nil {
-		result.Error = err.Error()
+		return nil, err
 	}
-
-	return result, nil
+	return tool.Execute(ctx)
 }
 
-// ExecuteNonInteractive runs OpenCode and captures output
+// ExecuteNonInteractive runs the OpenCode CLI and captures output (for automation)
 func (t *OpenCodeTool) ExecuteNonInteractive(ctx ExecutionContext) (*ExecutionResult, error) {
-	prompt := ctx.Prompt
-	if prompt == "" {
-		prompt = t.getPhasePrompt(ctx.Phase)
-	}
-
-	args := []string{}
-	if prompt != "" {
-		args = append(args, prompt)
-	}
-	args = append(args, ctx.Args...)
-
-	cmd := exec.Command(t.command, args...)
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-
-	result := &ExecutionResult{
-		Output:   stdout.String(),
-		Error:    stderr.String(),
-		ExitCode: cmd.ProcessState.ExitCode(),
-		Success:  err == nil,
+	tool, err := NewCommandTool(t.name, t.Definition())
+	if err != nil {
+		return nil, err
 	}
-
-	return result, nil
+	return tool.ExecuteNonInteractive(ctx)
 }
 
-func (t *OpenCodeTool) getPhasePrompt(phase string) string {
-	prompts := map[string]string{
-		"planning": `You are helping with the planning phase of a software project.
+// openCodePhasePrompts are the default prompts per phase
+var openCodePhasePrompts = map[string]string{
+	"planning": `You are helping with the planning phase of a software project.
 
 Tasks:
 1. Design the high-level architecture
@@ -125,7 +59,7 @@ Tasks:
 
 Explore the codebase (if any) and help plan the architecture.`,
 
-		"design": `You are helping with the design phase of a software project.
+	"design": `You are helping with the design phase of a software project.
 
 Tasks:
 1. Define API endpoints and contracts
@@ -135,10 +69,4 @@ Tasks:
 5. Create sequence diagrams for complex flows
 
 Build on the architecture and create detailed designs.`,
-	}
-
-	if p, ok := prompts[phase]; ok {
-		return p
-	}
-	return ""
 }
@@ -1,8 +1,11 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
+	"sort"
+	"time"
 
 	"github.com/rice0649/fabric-lite/internal/core"
 )
@@ -21,6 +24,9 @@ type Tool interface {
 	// Execute runs the tool with the given context
 	Execute(ctx ExecutionContext) (*ExecutionResult, error)
 
+	// ExecuteNonInteractive runs the tool without a terminal and captures its output
+	ExecuteNonInteractive(ctx ExecutionContext) (*ExecutionResult, error)
+
 	// GetCommand returns the base command for the tool
 	GetCommand() string
 }
@@ -30,9 +36,39 @@ type ExecutionContext struct {
 	Phase   string            // Current development phase
 	Pattern string            // Pattern name (for fabric-lite)
 	Prompt  string            // Custom prompt
+	Context string            // Additional context appended to the prompt (e.g. prior phase artifacts)
 	Args    []string          // Additional arguments
 	Env     map[string]string // Environment variables
 	WorkDir string            // Working directory
+	Timeout time.Duration     // Maximum run time for non-interactive execution (0 = no limit)
+}
+
+// deadline returns a context bounded by the execution timeout, if any
+func (ctx ExecutionContext) deadline() (context.Context, context.CancelFunc) {
+	if ctx.Timeout > 0 {
+		return context.WithTimeout(context.Background(), ctx.Timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// withContext appends additional context to a prompt
+func withContext(prompt, extra string) string {
+	if extra == "" {
+		return prompt
+	}
+	if prompt == "" {
+		return extra
+	}
+	return prompt + "\n\n--- Context ---\n\n" + extra
+}
+
+// timeoutResult marks a result as failed when the execution deadline was exceeded
+func timeoutResult(result *ExecutionResult, runCtx context.Context, timeout time.Duration) *ExecutionResult {
+	if runCtx.Err() == context.DeadlineExceeded {
+		result.Success = false
+		result.Error = fmt.Sprintf("timed out after %s\n%s", timeout, result.Error)
+	}
+	return result
 }
 
 // ExecutionResult contains the result of a tool execution
@@ -110,6 +146,40 @@ func init() {
 	RegisterTool(NewOllamaTool("http://localhost:11434"))
 }
 
+// definer is implemented by tools that run from a core.ToolDefinition
+type definer interface {
+	Definition() core.ToolDefinition
+}
+
+// RegisterDefinitions registers tools declared in tools.yaml. A definition
+// named after a registered tool is merged over that tool's own definition,
+// so it only needs the fields it changes; other tools are replaced.
+func RegisterDefinitions(defs map[string]core.ToolDefinition) error {
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Build every tool before registering any, so a bad file changes nothing
+	defined := make([]Tool, 0, len(names))
+	for _, name := range names {
+		def := defs[name]
+		if existing, ok := toolRegistry[name].(definer); ok {
+			def = existing.Definition().Merge(def)
+		}
+		tool, err := NewCommandTool(name, def)
+		if err != nil {
+			return err
+		}
+		defined = append(defined, tool)
+	}
+	for _, tool := range defined {
+		RegisterTool(tool)
+	}
+	return nil
+}
+
 // RegisterConfiguredTools registers tools that require configuration from the main config.
 func RegisterConfiguredTools(codexConfig core.CodexConfig, pm *core.ProviderManager) {
 	RegisterTool(NewCodexTool(codexConfig, pm))
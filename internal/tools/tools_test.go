@@ -113,6 +113,10 @@ func (m *MockTool) Execute(ctx ExecutionContext) (*ExecutionResult, error) {
 	}, nil
 }
 
+func (m *MockTool) ExecuteNonInteractive(ctx ExecutionContext) (*ExecutionResult, error) {
+	return m.Execute(ctx)
+}
+
 // mockOllamaProvider implements providers.Provider for testing purposes
 type mockOllamaProvider struct {
 	name string
@@ -233,7 +237,7 @@ func TestNewClaudeTool(t *testing.T) {
 	}
 }
 
-func TestClaudeToolGetPhasePrompt(t *testing.T) {
+func TestClaudeToolPhasePrompts(t *testing.T) {
 	tool := NewClaudeTool()
 
 	tests := []struct {
@@ -268,7 +272,7 @@ func TestClaudeToolGetPhasePrompt(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.phase, func(t *testing.T) {
-			prompt := tool.getPhasePrompt(tt.phase)
+			prompt := tool.Definition().Prompts[tt.phase]
 			if tt.expected == "" {
 				if prompt != "" {
 					t.Errorf("Expected empty prompt for phase '%s', got '%s'", tt.phase, prompt)
@@ -485,7 +489,7 @@ func TestNewGeminiTool(t *testing.T) {
 	}
 }
 
-func TestGeminiToolGetPhasePrompt(t *testing.T) {
+func TestGeminiToolPhasePrompts(t *testing.T) {
 	tool := NewGeminiTool()
 
 	tests := []struct {
@@ -512,7 +516,7 @@ func TestGeminiToolGetPhasePrompt(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.phase, func(t *testing.T) {
-			prompt := tool.getPhasePrompt(tt.phase)
+			prompt := tool.Definition().Prompts[tt.phase]
 			if tt.expected == "" {
 				if prompt != "" {
 					t.Errorf("Expected empty prompt for phase '%s', got '%s'", tt.phase, prompt)
@@ -570,7 +574,7 @@ func TestNewOpenCodeTool(t *testing.T) {
 	}
 }
 
-func TestOpenCodeToolGetPhasePrompt(t *testing.T) {
+func TestOpenCodeToolPhasePrompts(t *testing.T) {
 	tool := NewOpenCodeTool()
 
 	tests := []struct {
@@ -597,7 +601,7 @@ func TestOpenCodeToolGetPhasePrompt(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.phase, func(t *testing.T) {
-			prompt := tool.getPhasePrompt(tt.phase)
+			prompt := tool.Definition().Prompts[tt.phase]
 			if tt.expected == "" {
 				if prompt != "" {
 					t.Errorf("Expected empty prompt for phase '%s', got '%s'", tt.phase, prompt)
@@ -628,3 +632,139 @@ func TestNewFabricTool(t *testing.T) {
 		t.Errorf("Expected command to be 'fabric-lite', got %s", tool.GetCommand())
 	}
 }
+
+func TestWithContext(t *testing.T) {
+	if got := withContext("prompt", ""); got != "prompt" {
+		t.Errorf("Expected prompt unchanged, got '%s'", got)
+	}
+	if got := withContext("", "extra"); got != "extra" {
+		t.Errorf("Expected context alone, got '%s'", got)
+	}
+	got := withContext("prompt", "extra")
+	if !strings.HasPrefix(got, "prompt") || !strings.HasSuffix(got, "extra") {
+		t.Errorf("Expected prompt followed by context, got '%s'", got)
+	}
+}
+
+func TestExecuteNonInteractiveTimeout(t *testing.T) {
+	if !checkCommand("sleep") {
+		t.Skip("sleep not available")
+	}
+
+	tool := &GeminiTool{BaseTool: BaseTool{name: "sleeper", command: "sleep"}}
+	result, err := tool.ExecuteNonInteractive(ExecutionContext{
+		Prompt:  "5",
+		Timeout: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Success {
+		t.Error("Expected timed out execution to fail")
+	}
+	if !strings.Contains(result.Error, "timed out") {
+		t.Errorf("Expected timeout message, got '%s'", result.Error)
+	}
+}
+
+func TestCommandTool(t *testing.T) {
+	if !checkCommand("sh") {
+		t.Skip("sh not available")
+	}
+
+	tool, err := NewCommandTool("custom", core.ToolDefinition{
+		Command: "sh",
+		Args:    []string{"-c", `printf '%s|%s|%s|' "$0" "$TOOL_VAR" "{{.Config.model}}"; cat`, "{{.Phase}}", "{{.Pattern}}"},
+		Env:     map[string]string{"TOOL_VAR": "{{.Pattern}}-x"},
+		Stdin:   "{{.Prompt}}",
+		Prompts: map[string]string{"discovery": "find things"},
+		Config:  map[string]string{"model": "m1"},
+	})
+	if err != nil {
+		t.Fatalf("Expected a valid definition, got %v", err)
+	}
+	if tool.Name() != "custom" || tool.Description() != "Custom tool: sh" {
+		t.Errorf("Unexpected name or description: %s, %s", tool.Name(), tool.Description())
+	}
+
+	result, err := tool.ExecuteNonInteractive(ExecutionContext{Phase: "discovery", Context: "ctx"})
+	if err != nil || !result.Success {
+		t.Fatalf("Expected success, got %v, %+v", err, result)
+	}
+	expected := "discovery|-x|m1|find things\n\n--- Context ---\n\nctx"
+	if result.Output != expected {
+		t.Errorf("Expected output '%s', got '%s'", expected, result.Output)
+	}
+
+	if _, err := NewCommandTool("bad", core.ToolDefinition{Command: "sh", Args: []string{"{{.Prompt"}}); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+	if _, err := NewCommandTool("bad", core.ToolDefinition{Command: "sh", Mode: "detached"}); err == nil {
+		t.Error("Expected an error for an invalid mode")
+	}
+}
+
+func TestBuiltinToolDefinitions(t *testing.T) {
+	if !checkCommand("echo") {
+		t.Skip("echo not available")
+	}
+
+	def := NewClaudeTool().Definition()
+	def.Command = "echo"
+	tool, err := NewCommandTool("claude", def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, _ := tool.ExecuteNonInteractive(ExecutionContext{Prompt: "hi"})
+	if result.Output != "-p hi\n" {
+		t.Errorf("Expected claude to get the prompt with -p, got '%s'", result.Output)
+	}
+
+	def = NewFabricTool().Definition()
+	def.Command = "echo"
+	tool, _ = NewCommandTool("fabric", def)
+	result, _ = tool.ExecuteNonInteractive(ExecutionContext{Phase: "unknown"})
+	if result.Output != "--pattern summarize\n" {
+		t.Errorf("Expected the default pattern, got '%s'", result.Output)
+	}
+}
+
+func TestRegisterDefinitions(t *testing.T) {
+	originalRegistry := toolRegistry
+	defer func() { toolRegistry = originalRegistry }()
+	toolRegistry = make(map[string]Tool)
+	RegisterTool(NewGeminiTool())
+	RegisterTool(NewOllamaTool("http://localhost:11434"))
+
+	err := RegisterDefinitions(map[string]core.ToolDefinition{
+		"gemini": {Command: "gemini-beta"},
+		"ollama": {Command: "my-ollama"},
+		"aider":  {Command: "aider", Args: []string{"--message", "{{.Prompt}}"}, Mode: core.ToolModeCapture},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tool, _ := GetTool("gemini")
+	gemini, ok := tool.(*CommandTool)
+	if !ok || gemini.GetCommand() != "gemini-beta" || gemini.Definition().Prompts["discovery"] == "" {
+		t.Errorf("Expected gemini's command overridden and its prompts kept, got %+v", tool)
+	}
+	if tool, _ := GetTool("ollama"); tool.GetCommand() != "my-ollama" {
+		t.Errorf("Expected ollama replaced, got %s", tool.GetCommand())
+	}
+	if tool, err := GetTool("aider"); err != nil || tool.GetCommand() != "aider" {
+		t.Errorf("Expected aider to be registered, got %v", err)
+	}
+
+	err = RegisterDefinitions(map[string]core.ToolDefinition{
+		"aider": {Command: "aider-next"},
+		"empty": {},
+	})
+	if err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Errorf("Expected an error naming the invalid tool, got %v", err)
+	}
+	if tool, _ := GetTool("aider"); tool.GetCommand() != "aider" {
+		t.Error("Expected no tools registered from an invalid set")
+	}
+}
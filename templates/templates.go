@@ -0,0 +1,9 @@
+// Package templates embeds the built-in forge project templates.
+package templates
+
+import "embed"
+
+// FS holds the built-in template definitions, one <name>/template.yaml per template
+//
+//go:embed */template.yaml
+var FS embed.FS